@@ -0,0 +1,163 @@
+package awsbedrock
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// ToolHandler registers a Go function as a tool the model can invoke during
+// RunAgent. Name must match Spec.Name so ContentBlockMemberToolUse dispatch
+// can find it.
+type ToolHandler struct {
+	Name    string
+	Spec    types.ToolSpecification
+	Handler func(ctx context.Context, input document.Interface) (string, error)
+}
+
+// AgentOptions configures a RunAgent invocation.
+type AgentOptions struct {
+	MaxSteps int // maximum number of Converse round-trips before giving up
+}
+
+// AgentStepTrace records a single tool dispatch inside an agent run so callers
+// can audit what the model did and why.
+type AgentStepTrace struct {
+	Step         int
+	ToolName     string
+	Input        string
+	Output       string
+	LatencyMs    float64
+	InputTokens  int32
+	OutputTokens int32
+}
+
+// AgentResult is the outcome of RunAgent: the model's final text response plus
+// the full trace of tool calls it made to get there.
+type AgentResult struct {
+	FinalText string
+	Steps     []AgentStepTrace
+}
+
+// RunAgent registers tools with Bedrock and repeatedly invokes Converse,
+// dispatching every ContentBlockMemberToolUse response to the matching
+// ToolHandler and appending its ToolResultBlock to the conversation, until the
+// model emits a final text block or opts.MaxSteps is reached. This lets
+// security-analysis prompts call vendor APIs, filesystem scanners, etc.
+// iteratively rather than being limited to one round-trip.
+func (wrapper BedrockClient) RunAgent(ctx context.Context, request string, system string, tools []ToolHandler, opts AgentOptions) (*AgentResult, error) {
+	maxSteps := opts.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 10
+	}
+
+	modelId := MODEL
+	handlers := make(map[string]ToolHandler, len(tools))
+	toolConfig := &types.ToolConfiguration{}
+	for _, t := range tools {
+		handlers[t.Name] = t
+		toolConfig.Tools = append(toolConfig.Tools, &types.ToolMemberToolSpec{Value: t.Spec})
+	}
+
+	messages := []types.Message{
+		{
+			Role: types.ConversationRole("user"),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{Value: request},
+			},
+		},
+	}
+
+	result := &AgentResult{}
+
+	for step := 1; step <= maxSteps; step++ {
+		output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
+			ModelId:  aws.String(modelId),
+			Messages: messages,
+			System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
+				Value: system,
+			}},
+			ToolConfig: toolConfig,
+		})
+		if err != nil {
+			return result, fmt.Errorf("model err: %s : %w", modelId, err)
+		}
+
+		assistantMessage, ok := output.Output.(*types.ConverseOutputMemberMessage)
+		if !ok {
+			return result, fmt.Errorf("unexpected agent output on step %d", step)
+		}
+		messages = append(messages, assistantMessage.Value)
+
+		var inputTokens, outputTokens int32
+		if output.Usage != nil {
+			inputTokens = aws.ToInt32(output.Usage.InputTokens)
+			outputTokens = aws.ToInt32(output.Usage.OutputTokens)
+		}
+
+		toolResults := []types.ContentBlock{}
+		for _, block := range assistantMessage.Value.Content {
+			switch v := block.(type) {
+			case *types.ContentBlockMemberText:
+				result.FinalText = v.Value
+			case *types.ContentBlockMemberToolUse:
+				start := time.Now()
+				handler, found := handlers[aws.ToString(v.Value.Name)]
+				var toolOutput string
+				var toolErr error
+				if !found {
+					toolErr = fmt.Errorf("no handler registered for tool %q", aws.ToString(v.Value.Name))
+				} else {
+					toolOutput, toolErr = handler.Handler(ctx, v.Value.Input)
+				}
+				latency := time.Since(start).Seconds() * 1000
+
+				status := types.ToolResultStatusSuccess
+				resultText := toolOutput
+				if toolErr != nil {
+					status = types.ToolResultStatusError
+					resultText = toolErr.Error()
+					log.Printf("agent step %d: tool %s failed: %v", step, aws.ToString(v.Value.Name), toolErr)
+				}
+
+				result.Steps = append(result.Steps, AgentStepTrace{
+					Step:         step,
+					ToolName:     aws.ToString(v.Value.Name),
+					Input:        fmt.Sprintf("%+v", v.Value.Input),
+					Output:       resultText,
+					LatencyMs:    latency,
+					InputTokens:  inputTokens,
+					OutputTokens: outputTokens,
+				})
+
+				toolResults = append(toolResults, &types.ContentBlockMemberToolResult{
+					Value: types.ToolResultBlock{
+						ToolUseId: v.Value.ToolUseId,
+						Status:    status,
+						Content: []types.ToolResultContentBlock{
+							&types.ToolResultContentBlockMemberText{Value: resultText},
+						},
+					},
+				})
+			}
+		}
+
+		if len(toolResults) == 0 {
+			// The model produced a final text response with no further tool calls.
+			return result, nil
+		}
+
+		messages = append(messages, types.Message{
+			Role:    types.ConversationRole("user"),
+			Content: toolResults,
+		})
+	}
+
+	return result, fmt.Errorf("agent did not converge within %d steps", maxSteps)
+}
@@ -51,6 +51,99 @@ type BedrockClient struct {
 	System               string
 	PastMessages         []Message
 	Schema               string
+
+	guardrailID          string
+	guardrailVersion     string
+	guardrailTrace       bool
+	promptCachingEnabled bool
+}
+
+// WithGuardrail returns a copy of wrapper configured to apply the given Bedrock
+// Guardrail on the Converse calls made by the *Schema and *SchemaWithUsage
+// methods; GenerateAI, AnthropicAI, the streaming, multimodal and agent calls
+// do not apply it. When the guardrail blocks content, the *Schema methods
+// return a *GuardrailBlockedError instead of collapsing the block reason into
+// an "info: ..." string.
+func (wrapper BedrockClient) WithGuardrail(id, version string, trace bool) BedrockClient {
+	wrapper.guardrailID = id
+	wrapper.guardrailVersion = version
+	wrapper.guardrailTrace = trace
+	return wrapper
+}
+
+// EnableSystemPromptCaching returns a copy of wrapper that inserts a
+// SystemContentBlockMemberCachePoint after the system prompt, so repeated
+// datasnack scans of the same codebase reuse cached tokens instead of paying
+// full input-token cost on every call.
+func (wrapper BedrockClient) EnableSystemPromptCaching() BedrockClient {
+	wrapper.promptCachingEnabled = true
+	return wrapper
+}
+
+// GuardrailBlockedError is returned by the *Schema methods when a configured
+// Bedrock Guardrail intervenes on a request or response.
+type GuardrailBlockedError struct {
+	Reason string
+}
+
+func (e *GuardrailBlockedError) Error() string {
+	return fmt.Sprintf("guardrail blocked content: %s", e.Reason)
+}
+
+// SchemaResult is the usage-aware counterpart of the plain string returned by
+// GenerateAISchema/AnthropicAISchema, surfacing cached-token counts so callers
+// can confirm prompt caching is actually saving tokens.
+type SchemaResult struct {
+	Text                 string
+	InputTokens          int32
+	OutputTokens         int32
+	CacheReadInputTokens int32
+}
+
+// guardrailConfig builds the ConverseInput.GuardrailConfig for wrapper, or nil
+// if no guardrail has been configured via WithGuardrail.
+func (wrapper BedrockClient) guardrailConfig() *types.GuardrailConfiguration {
+	if wrapper.guardrailID == "" {
+		return nil
+	}
+	trace := types.GuardrailTraceDisabled
+	if wrapper.guardrailTrace {
+		trace = types.GuardrailTraceEnabled
+	}
+	return &types.GuardrailConfiguration{
+		GuardrailIdentifier: aws.String(wrapper.guardrailID),
+		GuardrailVersion:    aws.String(wrapper.guardrailVersion),
+		Trace:               trace,
+	}
+}
+
+// systemBlocks builds the System content blocks for a Converse call, appending
+// a cache point after the system prompt when EnableSystemPromptCaching is on.
+func (wrapper BedrockClient) systemBlocks(system string) []types.SystemContentBlock {
+	blocks := []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: system}}
+	if wrapper.promptCachingEnabled {
+		blocks = append(blocks, &types.SystemContentBlockMemberCachePoint{
+			Value: types.CachePointBlock{Type: types.CachePointTypeDefault},
+		})
+	}
+	return blocks
+}
+
+// cachePointAfterHistory marks the end of the past-message prefix with a cache
+// point when EnableSystemPromptCaching is on and the prefix is long enough to be
+// worth caching, so repeated datasnack scans of the same codebase reuse the
+// already-processed history instead of reprocessing it on every call.
+const cachePointMinPastMessages = 20
+
+func (wrapper BedrockClient) cachePointAfterHistory(messages []types.Message, numPastMessages int) []types.Message {
+	if !wrapper.promptCachingEnabled || numPastMessages < cachePointMinPastMessages {
+		return messages
+	}
+	last := numPastMessages - 1
+	messages[last].Content = append(messages[last].Content, &types.ContentBlockMemberCachePoint{
+		Value: types.CachePointBlock{Type: types.CachePointTypeDefault},
+	})
+	return messages
 }
 
 type Embedding struct {
@@ -68,6 +161,105 @@ func removeJsonAItags(content string) string {
 	return content
 }
 
+// buildContentBlocks converts past messages plus the current request into the
+// ordered []types.Message shape every Converse call needs.
+func buildContentBlocks(request string, pastMessages []map[string]string) []types.Message {
+	var contentBlocks []types.Message
+
+	for _, msg := range pastMessages {
+		contentBlocks = append(contentBlocks, types.Message{
+			Role: types.ConversationRole(msg["role"]),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{
+					Value: fmt.Sprintf("%v", msg["content"]),
+				},
+			},
+		})
+	}
+
+	contentBlocks = append(contentBlocks, types.Message{
+		Role: types.ConversationRole("user"),
+		Content: []types.ContentBlock{
+			&types.ContentBlockMemberText{
+				Value: fmt.Sprintf("%v", request),
+			},
+		},
+	})
+
+	return contentBlocks
+}
+
+// generateWithModel runs a single-shot Converse call against an arbitrary model ID,
+// used by Router to retry the same request across fallback models.
+func (wrapper BedrockClient) generateWithModel(ctx context.Context, modelId, request, system string, pastMessages []map[string]string) (string, error) {
+	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:  aws.String(modelId),
+		Messages: buildContentBlocks(request, pastMessages),
+		System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
+			Value: system,
+		}},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	responseText, _ := output.Output.(*types.ConverseOutputMemberMessage)
+	responseContentBlock := responseText.Value.Content[0]
+	text, _ := responseContentBlock.(*types.ContentBlockMemberText)
+	return removeJsonAItags(text.Value), nil
+}
+
+// generateSchemaWithModel is the ChatWithSchema counterpart to generateWithModel.
+func (wrapper BedrockClient) generateSchemaWithModel(ctx context.Context, modelId, request, system string, pastMessages []map[string]string, schema string) (string, error) {
+	var schemaObj map[string]any
+	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
+		return "", err
+	}
+	schemaDoc := document.NewLazyDocument(schemaObj)
+
+	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:  aws.String(modelId),
+		Messages: buildContentBlocks(request, pastMessages),
+		System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
+			Value: system,
+		}},
+		ToolConfig: &types.ToolConfiguration{
+			Tools: []types.Tool{
+				&types.ToolMemberToolSpec{
+					Value: types.ToolSpecification{
+						InputSchema: &types.ToolInputSchemaMemberJson{
+							Value: schemaDoc,
+						},
+						Name:        aws.String("API_Connector"),
+						Description: aws.String("Tool to generate payload to call vendor APIs"),
+					},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	p := output.Output.(*types.ConverseOutputMemberMessage)
+	union := p.Value.Content[0]
+
+	switch v := union.(type) {
+	case *types.ContentBlockMemberToolUse:
+		bob, err := v.Value.Input.MarshalSmithyDocument()
+		if err != nil {
+			return "", fmt.Errorf("model err: %s : %w", modelId, err)
+		}
+		return string(bob), nil
+	case *types.ContentBlockMemberText:
+		return v.Value, nil
+	default:
+		return "", fmt.Errorf("problem with ai schema")
+	}
+}
+
 func (wrapper BedrockClient) GenerateAI(request string, system string, pastMessages []map[string]string) (string, error) {
 	modelId := MODEL
 	ctx := context.TODO()
@@ -113,6 +305,182 @@ func (wrapper BedrockClient) GenerateAI(request string, system string, pastMessa
 	return final, nil
 }
 
+// StreamEventType identifies the kind of event emitted on a GenerateAIStream channel.
+type StreamEventType string
+
+const (
+	StreamEventTextDelta    StreamEventType = "text_delta"
+	StreamEventToolUseDelta StreamEventType = "tool_use_delta"
+	StreamEventMessageStop  StreamEventType = "message_stop"
+	StreamEventMetadata     StreamEventType = "metadata"
+	StreamEventError        StreamEventType = "error"
+)
+
+// StreamEvent is a single unit of progress from a streaming Converse call.
+type StreamEvent struct {
+	Type            StreamEventType
+	TextDelta       string
+	ToolUseDelta    string
+	StopReason      string
+	InputTokens     int32
+	OutputTokens    int32
+	CacheReadTokens int32
+	Err             error
+}
+
+// GenerateAIStream behaves like GenerateAI but streams the response incrementally
+// over the returned channel using bedrockruntime.ConverseStream, so CLI callers can
+// render output progressively and cancel via ctx. The channel is closed when the
+// stream ends, whether successfully or with an error (surfaced as a StreamEventError).
+func (wrapper BedrockClient) GenerateAIStream(ctx context.Context, request string, system string, pastMessages []map[string]string) (<-chan StreamEvent, error) {
+	modelId := MODEL
+	var contentBlocks []types.Message
+
+	for _, msg := range pastMessages {
+		contentBlocks = append(contentBlocks, types.Message{
+			Role: types.ConversationRole(msg["role"]),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{
+					Value: fmt.Sprintf("%v", msg["content"]),
+				},
+			},
+		})
+	}
+
+	contentBlocks = append(contentBlocks, types.Message{
+		Role: types.ConversationRole("user"),
+		Content: []types.ContentBlock{
+			&types.ContentBlockMemberText{
+				Value: fmt.Sprintf("%v", request),
+			},
+		},
+	})
+
+	output, err := wrapper.BedrockRuntimeClient.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+		ModelId:  aws.String(modelId),
+		Messages: contentBlocks,
+		System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
+			Value: system,
+		}},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	events := make(chan StreamEvent)
+	go streamConverseEvents(ctx, output.GetStream(), events)
+	return events, nil
+}
+
+// GenerateAISchemaStream is the streaming variant of GenerateAISchema. It emits each
+// ToolUseBlockDelta chunk as a raw delta as it arrives, without accumulating them;
+// callers assemble the final JSON by concatenating the ToolUseDelta fields they have
+// observed once a StreamEventMessageStop event is received.
+func (wrapper BedrockClient) GenerateAISchemaStream(ctx context.Context, request string, system string, pastMessages []map[string]string, schema string) (<-chan StreamEvent, error) {
+	modelId := MODEL
+
+	var contentBlocks []types.Message
+	for _, msg := range pastMessages {
+		contentBlocks = append(contentBlocks, types.Message{
+			Role: types.ConversationRole(msg["role"]),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{
+					Value: fmt.Sprintf("%v", msg["content"]),
+				},
+			},
+		})
+	}
+
+	contentBlocks = append(contentBlocks, types.Message{
+		Role: types.ConversationRole("user"),
+		Content: []types.ContentBlock{
+			&types.ContentBlockMemberText{
+				Value: fmt.Sprintf("%v", request),
+			},
+		},
+	})
+
+	var schemaObj map[string]any
+	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
+		return nil, err
+	}
+	schemaDoc := document.NewLazyDocument(schemaObj)
+
+	output, err := wrapper.BedrockRuntimeClient.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+		ModelId:  aws.String(modelId),
+		Messages: contentBlocks,
+		System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
+			Value: system,
+		}},
+		ToolConfig: &types.ToolConfiguration{
+			Tools: []types.Tool{
+				&types.ToolMemberToolSpec{
+					Value: types.ToolSpecification{
+						InputSchema: &types.ToolInputSchemaMemberJson{
+							Value: schemaDoc,
+						},
+						Name:        aws.String("API_Connector"),
+						Description: aws.String("Tool to generate payload to call vendor APIs"),
+					},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	events := make(chan StreamEvent)
+	go streamConverseEvents(ctx, output.GetStream(), events)
+	return events, nil
+}
+
+// streamConverseEvents drains a ConverseStream event stream into typed StreamEvents,
+// closing the channel once the stream ends or ctx is cancelled.
+func streamConverseEvents(ctx context.Context, stream *bedrockruntime.ConverseStreamEventStream, events chan<- StreamEvent) {
+	defer close(events)
+	defer stream.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-stream.Events():
+			if !ok {
+				if err := stream.Err(); err != nil {
+					events <- StreamEvent{Type: StreamEventError, Err: err}
+				}
+				return
+			}
+
+			switch v := e.(type) {
+			case *types.ConverseStreamOutputMemberContentBlockDelta:
+				switch d := v.Value.Delta.(type) {
+				case *types.ContentBlockDeltaMemberText:
+					events <- StreamEvent{Type: StreamEventTextDelta, TextDelta: removeJsonAItags(d.Value)}
+				case *types.ContentBlockDeltaMemberToolUse:
+					if d.Value.Input != nil {
+						events <- StreamEvent{Type: StreamEventToolUseDelta, ToolUseDelta: *d.Value.Input}
+					}
+				}
+			case *types.ConverseStreamOutputMemberMessageStop:
+				events <- StreamEvent{Type: StreamEventMessageStop, StopReason: string(v.Value.StopReason)}
+			case *types.ConverseStreamOutputMemberMetadata:
+				if v.Value.Usage != nil {
+					events <- StreamEvent{
+						Type:            StreamEventMetadata,
+						InputTokens:     aws.ToInt32(v.Value.Usage.InputTokens),
+						OutputTokens:    aws.ToInt32(v.Value.Usage.OutputTokens),
+						CacheReadTokens: aws.ToInt32(v.Value.Usage.CacheReadInputTokens),
+					}
+				}
+			}
+		}
+	}
+}
+
 func (wrapper BedrockClient) AnthropicAI(request any, system string, pastMessages []Message) (string, error) {
 	modelId := "us.anthropic.claude-3-5-haiku-20241022-v1:0"
 	ctx := context.TODO()
@@ -223,13 +591,13 @@ func (wrapper BedrockClient) AnthropicAISchema(request any, system string, pastM
 	}
 
 	schemaDoc := document.NewLazyDocument(schemaObj)
+	contentBlocks = wrapper.cachePointAfterHistory(contentBlocks, len(pastMessages))
 
 	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
-		ModelId:  aws.String(modelId),
-		Messages: contentBlocks,
-		System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
-			Value: system,
-		}},
+		ModelId:         aws.String(modelId),
+		Messages:        contentBlocks,
+		System:          wrapper.systemBlocks(system),
+		GuardrailConfig: wrapper.guardrailConfig(),
 		ToolConfig: &types.ToolConfiguration{
 			Tools: []types.Tool{
 				&types.ToolMemberToolSpec{
@@ -268,8 +636,7 @@ func (wrapper BedrockClient) AnthropicAISchema(request any, system string, pastM
 		return fmt.Sprintf("info: %+v", v.Value), nil
 
 	case *types.ContentBlockMemberGuardContent:
-		_ = v.Value // Value is types.GuardrailConverseContentBlock
-		return fmt.Sprintf("info: %+v", v.Value), nil
+		return "", &GuardrailBlockedError{Reason: fmt.Sprintf("%+v", v.Value)}
 	case *types.ContentBlockMemberImage:
 		_ = v.Value // Value is types.ImageBlock
 		return fmt.Sprintf("info: %+v", v.Value), nil
@@ -343,13 +710,13 @@ func (wrapper BedrockClient) GenerateAISchema(request string, system string, pas
 	}
 
 	schemaDoc := document.NewLazyDocument(schemaObj)
+	contentBlocks = wrapper.cachePointAfterHistory(contentBlocks, len(pastMessages))
 
 	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
-		ModelId:  aws.String(modelId),
-		Messages: contentBlocks,
-		System: []types.SystemContentBlock{&types.SystemContentBlockMemberText{
-			Value: system,
-		}},
+		ModelId:         aws.String(modelId),
+		Messages:        contentBlocks,
+		System:          wrapper.systemBlocks(system),
+		GuardrailConfig: wrapper.guardrailConfig(),
 		ToolConfig: &types.ToolConfiguration{
 			Tools: []types.Tool{
 				&types.ToolMemberToolSpec{
@@ -388,8 +755,7 @@ func (wrapper BedrockClient) GenerateAISchema(request string, system string, pas
 		return fmt.Sprintf("info: %+v", v.Value), nil
 
 	case *types.ContentBlockMemberGuardContent:
-		_ = v.Value // Value is types.GuardrailConverseContentBlock
-		return fmt.Sprintf("info: %+v", v.Value), nil
+		return "", &GuardrailBlockedError{Reason: fmt.Sprintf("%+v", v.Value)}
 	case *types.ContentBlockMemberImage:
 		_ = v.Value // Value is types.ImageBlock
 		return fmt.Sprintf("info: %+v", v.Value), nil
@@ -427,3 +793,187 @@ func (wrapper BedrockClient) GenerateAISchema(request string, system string, pas
 	}
 
 }
+
+// AnthropicAISchemaWithUsage behaves like AnthropicAISchema but also reports
+// token accounting, including cache-read tokens saved by EnableSystemPromptCaching,
+// for callers that need usage telemetry alongside the generated text.
+func (wrapper BedrockClient) AnthropicAISchemaWithUsage(request any, system string, pastMessages []Message, schema SCHEMA) (*SchemaResult, error) {
+	modelId := "us.anthropic.claude-3-5-haiku-20241022-v1:0"
+	ctx := context.TODO()
+
+	var contentBlocks []types.Message
+	for _, msg := range pastMessages {
+		contentBlocks = append(contentBlocks, types.Message{
+			Role: types.ConversationRole(msg.Role),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{
+					Value: fmt.Sprintf("%v", msg.Content),
+				},
+			},
+		})
+	}
+
+	contentBlocks = append(contentBlocks, types.Message{
+		Role: types.ConversationRole("user"),
+		Content: []types.ContentBlock{
+			&types.ContentBlockMemberText{
+				Value: fmt.Sprintf("%v", request),
+			},
+		},
+	})
+
+	var schemaObj map[string]any
+	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	schemaDoc := document.NewLazyDocument(schemaObj)
+	contentBlocks = wrapper.cachePointAfterHistory(contentBlocks, len(pastMessages))
+
+	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(modelId),
+		Messages:        contentBlocks,
+		System:          wrapper.systemBlocks(system),
+		GuardrailConfig: wrapper.guardrailConfig(),
+		ToolConfig: &types.ToolConfiguration{
+			Tools: []types.Tool{
+				&types.ToolMemberToolSpec{
+					Value: types.ToolSpecification{
+						InputSchema: &types.ToolInputSchemaMemberJson{
+							Value: schemaDoc,
+						},
+						Name:        aws.String("JSON_Output"),
+						Description: aws.String("Generate structured output"),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	p := output.Output.(*types.ConverseOutputMemberMessage)
+	union := p.Value.Content[0]
+
+	result := &SchemaResult{}
+	if output.Usage != nil {
+		result.InputTokens = aws.ToInt32(output.Usage.InputTokens)
+		result.OutputTokens = aws.ToInt32(output.Usage.OutputTokens)
+		result.CacheReadInputTokens = aws.ToInt32(output.Usage.CacheReadInputTokens)
+	}
+
+	switch v := union.(type) {
+	case *types.ContentBlockMemberGuardContent:
+		return nil, &GuardrailBlockedError{Reason: fmt.Sprintf("%+v", v.Value)}
+
+	case *types.ContentBlockMemberText:
+		result.Text = v.Value
+		return result, nil
+
+	case *types.ContentBlockMemberToolUse:
+		bob, err := v.Value.Input.MarshalSmithyDocument()
+		if err != nil {
+			return nil, err
+		}
+		result.Text = string(bob)
+		return result, nil
+
+	default:
+		result.Text = fmt.Sprintf("info: %+v", union)
+		return result, nil
+	}
+}
+
+// GenerateAISchemaWithUsage behaves like GenerateAISchema but also reports
+// token accounting, including cache-read tokens saved by EnableSystemPromptCaching,
+// for callers that need usage telemetry alongside the generated text.
+func (wrapper BedrockClient) GenerateAISchemaWithUsage(request string, system string, pastMessages []map[string]string, schema string) (*SchemaResult, error) {
+	modelId := MODEL
+	ctx := context.TODO()
+
+	var contentBlocks []types.Message
+	for _, msg := range pastMessages {
+		contentBlocks = append(contentBlocks, types.Message{
+			Role: types.ConversationRole(msg["role"]),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{
+					Value: fmt.Sprintf("%v", msg["content"]),
+				},
+			},
+		})
+	}
+
+	contentBlocks = append(contentBlocks, types.Message{
+		Role: types.ConversationRole("user"),
+		Content: []types.ContentBlock{
+			&types.ContentBlockMemberText{
+				Value: fmt.Sprintf("%v", request),
+			},
+		},
+	})
+
+	var schemaObj map[string]any
+	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	schemaDoc := document.NewLazyDocument(schemaObj)
+	contentBlocks = wrapper.cachePointAfterHistory(contentBlocks, len(pastMessages))
+
+	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(modelId),
+		Messages:        contentBlocks,
+		System:          wrapper.systemBlocks(system),
+		GuardrailConfig: wrapper.guardrailConfig(),
+		ToolConfig: &types.ToolConfiguration{
+			Tools: []types.Tool{
+				&types.ToolMemberToolSpec{
+					Value: types.ToolSpecification{
+						InputSchema: &types.ToolInputSchemaMemberJson{
+							Value: schemaDoc,
+						},
+						Name:        aws.String("API_Connector"),
+						Description: aws.String("Tool to generate payload to call vendor APIs"),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	p := output.Output.(*types.ConverseOutputMemberMessage)
+	union := p.Value.Content[0]
+
+	result := &SchemaResult{}
+	if output.Usage != nil {
+		result.InputTokens = aws.ToInt32(output.Usage.InputTokens)
+		result.OutputTokens = aws.ToInt32(output.Usage.OutputTokens)
+		result.CacheReadInputTokens = aws.ToInt32(output.Usage.CacheReadInputTokens)
+	}
+
+	switch v := union.(type) {
+	case *types.ContentBlockMemberGuardContent:
+		return nil, &GuardrailBlockedError{Reason: fmt.Sprintf("%+v", v.Value)}
+
+	case *types.ContentBlockMemberText:
+		result.Text = v.Value
+		return result, nil
+
+	case *types.ContentBlockMemberToolUse:
+		bob, err := v.Value.Input.MarshalSmithyDocument()
+		if err != nil {
+			return nil, err
+		}
+		result.Text = string(bob)
+		return result, nil
+
+	default:
+		result.Text = fmt.Sprintf("info: %+v", union)
+		return result, nil
+	}
+}
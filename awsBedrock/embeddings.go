@@ -0,0 +1,177 @@
+package awsbedrock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+)
+
+const (
+	TitanEmbedTextV2          = "amazon.titan-embed-text-v2:0"
+	CohereEmbedEnglishV3      = "cohere.embed-english-v3"
+	CohereEmbedMultilingualV3 = "cohere.embed-multilingual-v3"
+)
+
+// EmbedOptions configures EmbedBatch. Model selects the Bedrock embedding
+// model; Dimensions and Normalize only apply to TitanEmbedTextV2. Concurrency
+// and MaxRetries default to 5 and 3 respectively when left at zero.
+type EmbedOptions struct {
+	Model       string
+	Dimensions  int32
+	Normalize   bool
+	Concurrency int
+	MaxRetries  int
+}
+
+type titanEmbedV2Request struct {
+	InputText  string `json:"inputText"`
+	Dimensions int32  `json:"dimensions,omitempty"`
+	Normalize  bool   `json:"normalize,omitempty"`
+}
+
+type titanEmbedV2Response struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+type cohereEmbedRequest struct {
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type"`
+}
+
+type cohereEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// EmbedBatch embeds every entry in inputs, fanning out across opts.Concurrency
+// workers and retrying throttled requests with jittered backoff. A failed
+// input leaves its slot in the result nil rather than aborting the whole
+// batch; the returned error is non-nil only when at least one input failed,
+// and wraps the first such failure for context.
+func (wrapper BedrockClient) EmbedBatch(ctx context.Context, inputs []string, opts EmbedOptions) ([][]float32, error) {
+	if opts.Model == "" {
+		opts.Model = TitanEmbedTextV2
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+
+	results := make([][]float32, len(inputs))
+	errs := make([]error, len(inputs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, input := range inputs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, input string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = wrapper.embedOneWithRetry(ctx, input, opts, maxRetries)
+		}(i, input)
+	}
+	wg.Wait()
+
+	var firstErr error
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return results, fmt.Errorf("embed batch: %d/%d inputs failed, first error: %w", failed, len(inputs), firstErr)
+	}
+	return results, nil
+}
+
+func (wrapper BedrockClient) embedOneWithRetry(ctx context.Context, input string, opts EmbedOptions, maxRetries int) ([]float32, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			base := 200 * time.Millisecond * time.Duration(1<<uint(attempt-1))
+			jitter := time.Duration(rand.Int63n(int64(base)))
+			time.Sleep(base/2 + jitter/2)
+		}
+
+		vec, err := wrapper.embedOne(ctx, input, opts)
+		if err == nil {
+			return vec, nil
+		}
+		lastErr = err
+		if !isRetryableBedrockError(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func (wrapper BedrockClient) embedOne(ctx context.Context, input string, opts EmbedOptions) ([]float32, error) {
+	switch opts.Model {
+	case CohereEmbedEnglishV3, CohereEmbedMultilingualV3:
+		body, err := json.Marshal(cohereEmbedRequest{
+			Texts:     []string{input},
+			InputType: "search_document",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cohere embed request: %w", err)
+		}
+
+		output, err := wrapper.BedrockRuntimeClient.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:     aws.String(opts.Model),
+			ContentType: aws.String("application/json"),
+			Body:        body,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("model err: %s : %w", opts.Model, err)
+		}
+
+		var response cohereEmbedResponse
+		if err := json.Unmarshal(output.Body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cohere embed response: %w", err)
+		}
+		if len(response.Embeddings) == 0 {
+			return nil, fmt.Errorf("cohere embed response contained no embeddings")
+		}
+		return response.Embeddings[0], nil
+
+	default: // amazon.titan-embed-text-v2:0
+		body, err := json.Marshal(titanEmbedV2Request{
+			InputText:  input,
+			Dimensions: opts.Dimensions,
+			Normalize:  opts.Normalize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal titan embed request: %w", err)
+		}
+
+		output, err := wrapper.BedrockRuntimeClient.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:     aws.String(opts.Model),
+			ContentType: aws.String("application/json"),
+			Body:        body,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("model err: %s : %w", opts.Model, err)
+		}
+
+		var response titanEmbedV2Response
+		if err := json.Unmarshal(output.Body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal titan embed response: %w", err)
+		}
+		return response.Embedding, nil
+	}
+}
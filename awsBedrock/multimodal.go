@@ -0,0 +1,213 @@
+package awsbedrock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// ContentPartKind identifies which field of a ContentPart is populated.
+type ContentPartKind string
+
+const (
+	ContentPartText     ContentPartKind = "text"
+	ContentPartImage    ContentPartKind = "image"
+	ContentPartDocument ContentPartKind = "document"
+)
+
+// ContentPart is a sum type for one piece of multimodal input to
+// GenerateAIMultimodal. Exactly one group of fields is meaningful, selected by
+// Kind: Text; ImageBytes+ImageMIME; or DocumentBytes+DocumentName+DocumentFormat.
+type ContentPart struct {
+	Kind ContentPartKind
+
+	Text string
+
+	ImageBytes []byte
+	ImageMIME  string // e.g. "image/png", "image/jpeg"
+
+	DocumentBytes  []byte
+	DocumentName   string
+	DocumentFormat string // e.g. "pdf", "md", "go"
+}
+
+// TextPart builds a text ContentPart.
+func TextPart(text string) ContentPart {
+	return ContentPart{Kind: ContentPartText, Text: text}
+}
+
+// ImagePart builds an image ContentPart from raw bytes and a MIME type.
+func ImagePart(data []byte, mime string) ContentPart {
+	return ContentPart{Kind: ContentPartImage, ImageBytes: data, ImageMIME: mime}
+}
+
+// DocumentPart builds a document ContentPart, e.g. a source file or PDF, from
+// raw bytes, a display name, and a format such as "pdf", "md", or "go".
+func DocumentPart(data []byte, name, format string) ContentPart {
+	return ContentPart{Kind: ContentPartDocument, DocumentBytes: data, DocumentName: name, DocumentFormat: format}
+}
+
+// modelModalities describes which non-text content a model accepts in Converse.
+type modelModalities struct {
+	Image    bool
+	Document bool
+}
+
+// modalitySupport maps known model IDs to the input modalities Bedrock
+// documents them as accepting. Models not listed here are assumed text-only.
+var modalitySupport = map[string]modelModalities{
+	"us.anthropic.claude-3-5-haiku-20241022-v1:0": {Image: true, Document: true},
+	"us.meta.llama4-maverick-17b-instruct-v1:0":   {Image: true, Document: false},
+	"us.meta.llama4-scout-17b-instruct-v1:0":      {Image: true, Document: false},
+}
+
+// checkModalitySupport reports an error if modelId cannot accept the kinds of
+// content present in parts, so callers fail fast instead of getting an opaque
+// Bedrock validation error back after the round trip.
+func checkModalitySupport(modelId string, parts []ContentPart) error {
+	caps := modalitySupport[modelId]
+	for _, p := range parts {
+		switch p.Kind {
+		case ContentPartImage:
+			if !caps.Image {
+				return fmt.Errorf("model %s does not support image input", modelId)
+			}
+		case ContentPartDocument:
+			if !caps.Document {
+				return fmt.Errorf("model %s does not support document input", modelId)
+			}
+		}
+	}
+	return nil
+}
+
+func imageFormatFromMIME(mime string) types.ImageFormat {
+	switch mime {
+	case "image/png":
+		return types.ImageFormatPng
+	case "image/jpeg", "image/jpg":
+		return types.ImageFormatJpeg
+	case "image/gif":
+		return types.ImageFormatGif
+	case "image/webp":
+		return types.ImageFormatWebp
+	default:
+		return types.ImageFormatPng
+	}
+}
+
+// documentFormatFor maps a document format hint to the closest Bedrock
+// DocumentFormat. Bedrock has no source-code format, so extensions like "go"
+// or "py" are sent as plain text rather than rejected.
+func documentFormatFor(format string) types.DocumentFormat {
+	switch format {
+	case "pdf":
+		return types.DocumentFormatPdf
+	case "csv":
+		return types.DocumentFormatCsv
+	case "doc":
+		return types.DocumentFormatDoc
+	case "docx":
+		return types.DocumentFormatDocx
+	case "xls":
+		return types.DocumentFormatXls
+	case "xlsx":
+		return types.DocumentFormatXlsx
+	case "html":
+		return types.DocumentFormatHtml
+	case "md":
+		return types.DocumentFormatMd
+	default:
+		return types.DocumentFormatTxt
+	}
+}
+
+// contentBlocksFromParts converts ContentPart values into the ContentBlock
+// union members a Converse message expects.
+func contentBlocksFromParts(parts []ContentPart) ([]types.ContentBlock, error) {
+	blocks := make([]types.ContentBlock, 0, len(parts))
+	for _, p := range parts {
+		switch p.Kind {
+		case ContentPartText:
+			blocks = append(blocks, &types.ContentBlockMemberText{Value: p.Text})
+		case ContentPartImage:
+			blocks = append(blocks, &types.ContentBlockMemberImage{
+				Value: types.ImageBlock{
+					Format: imageFormatFromMIME(p.ImageMIME),
+					Source: &types.ImageSourceMemberBytes{Value: p.ImageBytes},
+				},
+			})
+		case ContentPartDocument:
+			blocks = append(blocks, &types.ContentBlockMemberDocument{
+				Value: types.DocumentBlock{
+					Format: documentFormatFor(p.DocumentFormat),
+					Name:   aws.String(p.DocumentName),
+					Source: &types.DocumentSourceMemberBytes{Value: p.DocumentBytes},
+				},
+			})
+		default:
+			return nil, fmt.Errorf("unknown content part kind %q", p.Kind)
+		}
+	}
+	return blocks, nil
+}
+
+// GenerateAIMultimodal is GenerateAI's counterpart for requests that mix text
+// with images or documents, e.g. feeding a dashboard screenshot or an entire
+// source file to the model instead of pre-flattening everything to a string.
+// It preflight-checks parts against the target model's known modality support
+// so an unsupported combination fails fast with a clear error instead of an
+// opaque Bedrock validation error.
+func (wrapper BedrockClient) GenerateAIMultimodal(ctx context.Context, parts []ContentPart, system string, pastMessages []map[string]string) (string, error) {
+	modelId := MODEL
+
+	if err := checkModalitySupport(modelId, parts); err != nil {
+		return "", err
+	}
+
+	blocks, err := contentBlocksFromParts(parts)
+	if err != nil {
+		return "", err
+	}
+
+	var contentBlocks []types.Message
+	for _, msg := range pastMessages {
+		contentBlocks = append(contentBlocks, types.Message{
+			Role: types.ConversationRole(msg["role"]),
+			Content: []types.ContentBlock{
+				&types.ContentBlockMemberText{
+					Value: fmt.Sprintf("%v", msg["content"]),
+				},
+			},
+		})
+	}
+
+	contentBlocks = append(contentBlocks, types.Message{
+		Role:    types.ConversationRole("user"),
+		Content: blocks,
+	})
+
+	output, err := wrapper.BedrockRuntimeClient.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:  aws.String(modelId),
+		Messages: contentBlocks,
+		System:   wrapper.systemBlocks(system),
+	})
+	if err != nil {
+		return "", fmt.Errorf("model err: %s : %w", modelId, err)
+	}
+
+	responseMessage, ok := output.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return "", fmt.Errorf("unexpected multimodal output")
+	}
+
+	switch v := responseMessage.Value.Content[0].(type) {
+	case *types.ContentBlockMemberText:
+		return removeJsonAItags(v.Value), nil
+	default:
+		return "", fmt.Errorf("problem with ai multimodal response")
+	}
+}
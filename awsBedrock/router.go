@@ -0,0 +1,187 @@
+package awsbedrock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// LLMProvider is the provider-agnostic interface the rest of datasnack should
+// depend on instead of reaching for BedrockClient directly. It exists so that
+// callers (like Router) can be composed regardless of which model backs them.
+type LLMProvider interface {
+	Chat(request, system string, pastMessages []map[string]string) (string, error)
+	ChatWithSchema(request, system string, pastMessages []map[string]string, schema string) (string, error)
+	Embed(prompt string) ([]float32, error)
+	Stream(ctx context.Context, request, system string, pastMessages []map[string]string) (<-chan StreamEvent, error)
+}
+
+// bedrockModel adapts BedrockClient to LLMProvider for a single fixed model ID,
+// so the same client can back several routable models at once.
+type bedrockModel struct {
+	client  *BedrockClient
+	modelId string
+}
+
+// NewBedrockProvider builds an LLMProvider bound to a specific Bedrock model ID.
+func NewBedrockProvider(client *BedrockClient, modelId string) LLMProvider {
+	return &bedrockModel{client: client, modelId: modelId}
+}
+
+func (m *bedrockModel) Chat(request, system string, pastMessages []map[string]string) (string, error) {
+	return m.client.generateWithModel(context.TODO(), m.modelId, request, system, pastMessages)
+}
+
+func (m *bedrockModel) ChatWithSchema(request, system string, pastMessages []map[string]string, schema string) (string, error) {
+	return m.client.generateSchemaWithModel(context.TODO(), m.modelId, request, system, pastMessages, schema)
+}
+
+func (m *bedrockModel) Embed(prompt string) ([]float32, error) {
+	return m.client.GetEmbeddings(prompt)
+}
+
+func (m *bedrockModel) Stream(ctx context.Context, request, system string, pastMessages []map[string]string) (<-chan StreamEvent, error) {
+	return m.client.GenerateAIStream(ctx, request, system, pastMessages)
+}
+
+// RoutingPolicy describes which models a Router should try, in what order, and
+// how it backs off between retries. MaxAttempts is the only attempt limit; there
+// is no separate per-model retry limit.
+type RoutingPolicy struct {
+	Primary     string
+	Fallbacks   []string
+	MaxAttempts int           // total attempts across all models before giving up
+	BaseBackoff time.Duration // starting backoff before the first retry
+	MaxBackoff  time.Duration // backoff ceiling
+	LogRouting  bool
+}
+
+// DefaultRoutingPolicy routes across the Llama 4 and Claude families already used
+// elsewhere in this package, falling back in roughly cheapest-to-most-capable order.
+func DefaultRoutingPolicy() RoutingPolicy {
+	return RoutingPolicy{
+		Primary: "us.meta.llama4-maverick-17b-instruct-v1:0",
+		Fallbacks: []string{
+			"us.meta.llama4-scout-17b-instruct-v1:0",
+			"us.meta.llama3-1-70b-instruct-v1:0",
+			"us.anthropic.claude-3-5-haiku-20241022-v1:0",
+		},
+		MaxAttempts: 6,
+		BaseBackoff: 250 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	}
+}
+
+// Router sends a Chat/ChatWithSchema request to a primary model and an ordered
+// list of fallbacks. When Bedrock returns a retryable error class (throttling,
+// timeout, service unavailable) it retries the same model with exponential
+// backoff and jitter until MaxAttempts is used up; it moves on to the next
+// fallback only after a non-retryable error, so a model that keeps throttling
+// is never replaced by a fallback. This replaces the fatal log.Fatal paths a
+// single degraded model deployment used to cause.
+type Router struct {
+	client *BedrockClient
+	policy RoutingPolicy
+}
+
+// NewRouter creates a Router that dispatches through client according to policy.
+func NewRouter(client *BedrockClient, policy RoutingPolicy) *Router {
+	return &Router{client: client, policy: policy}
+}
+
+func (r *Router) models() []string {
+	return append([]string{r.policy.Primary}, r.policy.Fallbacks...)
+}
+
+// Chat routes a single-shot generation request across the configured models.
+func (r *Router) Chat(request, system string, pastMessages []map[string]string) (string, error) {
+	return r.route(func(ctx context.Context, modelId string) (string, error) {
+		return r.client.generateWithModel(ctx, modelId, request, system, pastMessages)
+	})
+}
+
+// ChatWithSchema routes a schema-constrained generation request across the
+// configured models.
+func (r *Router) ChatWithSchema(request, system string, pastMessages []map[string]string, schema string) (string, error) {
+	return r.route(func(ctx context.Context, modelId string) (string, error) {
+		return r.client.generateSchemaWithModel(ctx, modelId, request, system, pastMessages, schema)
+	})
+}
+
+func (r *Router) route(call func(ctx context.Context, modelId string) (string, error)) (string, error) {
+	ctx := context.TODO()
+	var lastErr error
+	attempts := 0
+
+	for _, modelId := range r.models() {
+		for retry := 0; ; retry++ {
+			if attempts >= r.policy.MaxAttempts {
+				return "", fmt.Errorf("router: exhausted %d attempts, last error: %w", attempts, lastErr)
+			}
+			attempts++
+
+			if r.policy.LogRouting {
+				log.Printf("router: attempt %d/%d on model %s", attempts, r.policy.MaxAttempts, modelId)
+			}
+
+			text, err := call(ctx, modelId)
+			if err == nil {
+				return text, nil
+			}
+			lastErr = err
+
+			if !isRetryableBedrockError(err) {
+				break // try the next model instead of retrying this one
+			}
+
+			time.Sleep(r.backoff(retry))
+		}
+	}
+
+	return "", fmt.Errorf("router: all models failed, last error: %w", lastErr)
+}
+
+// backoff computes an exponential delay with jitter, capped at MaxBackoff.
+func (r *Router) backoff(retry int) time.Duration {
+	base := r.policy.BaseBackoff
+	if base <= 0 {
+		base = 250 * time.Millisecond
+	}
+	max := r.policy.MaxBackoff
+	if max <= 0 {
+		max = 5 * time.Second
+	}
+
+	delay := base * time.Duration(1<<uint(retry))
+	if delay > max {
+		delay = max
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+	return delay/2 + jitter
+}
+
+// isRetryableBedrockError reports whether err is the kind of transient failure
+// (throttling, model timeout, service unavailable) that warrants a retry or
+// fallback rather than surfacing immediately to the caller.
+func isRetryableBedrockError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "ModelTimeoutException", "ServiceUnavailableException":
+			return true
+		}
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "ThrottlingException") ||
+		strings.Contains(msg, "ModelTimeoutException") ||
+		strings.Contains(msg, "ServiceUnavailable")
+}
@@ -0,0 +1,81 @@
+package cloneAttack
+
+import "context"
+
+// ReportEventKind identifies what a ReportEvent carries. Mirrors the
+// Kind-string-plus-optional-payload shape resultsStreamEntry already uses for
+// N8nWorkflowEvaluator's JSONL stream.
+type ReportEventKind string
+
+const (
+	// ObservationEvent carries one attack-tactic observation in Observation.
+	ObservationEvent ReportEventKind = "observation"
+	// VulnerabilityEvent carries one or more findings in Vulnerabilities,
+	// published as soon as detectVulnerabilities or assessInformationLeakage
+	// finishes.
+	VulnerabilityEvent ReportEventKind = "vulnerability"
+	// ScoreEvent carries one named score in Score.
+	ScoreEvent ReportEventKind = "score"
+	// RecommendationEvent carries one system-prompt recommendation in
+	// Recommendation.
+	RecommendationEvent ReportEventKind = "recommendation"
+	// DoneEvent carries the final (possibly Partial) report in Report and is
+	// always the last event published before the channel closes.
+	DoneEvent ReportEventKind = "done"
+)
+
+// ScorePayload is a ScoreEvent's payload: one of the report's named scores,
+// published as soon as its sub-analysis finishes.
+type ScorePayload struct {
+	Name  string // "appropriateness" or "informationLeakage"
+	Value float64
+}
+
+// ReportEvent is one incremental update published by AnalyzeConversationStream
+// as each sub-analysis of a conversation completes, so a caller (CLI progress
+// output, a future web UI) can render results as they arrive instead of
+// waiting for the full report. Only the field matching Kind is set.
+type ReportEvent struct {
+	Kind ReportEventKind
+
+	Observation     string
+	Vulnerabilities []Vulnerability
+	Score           *ScorePayload
+	Recommendation  string
+	Report          *VulnerabilityReport // set on DoneEvent only
+}
+
+// AnalyzeConversationStream is AnalyzeConversation's incremental counterpart:
+// it runs the same five sub-analyses concurrently, but publishes a
+// ReportEvent for each piece of output as soon as it's ready instead of
+// returning only once everything has finished. The channel is closed after a
+// DoneEvent carrying the finished report.
+//
+// If ctx is canceled or times out before every sub-analysis completes, the
+// sub-analyses still in flight are not interrupted mid-call (the underlying
+// AIClient methods aren't context-aware), but any not yet started are
+// skipped, and the DoneEvent's report carries whatever completed with
+// Partial set to true rather than being discarded.
+func (a *ServicesPlus) AnalyzeConversationStream(
+	ctx context.Context,
+	conversations []map[string]string,
+	attackTactic string,
+	actualProblem string,
+	systemPrompts []string,
+) (<-chan ReportEvent, error) {
+	events := make(chan ReportEvent)
+
+	go func() {
+		defer close(events)
+
+		messages := a.parseConversations(conversations)
+		report, partial := a.runSubAnalyses(ctx, conversations, messages, attackTactic, actualProblem, systemPrompts, func(e ReportEvent) {
+			events <- e
+		})
+		a.finishReport(report, messages, partial)
+
+		events <- ReportEvent{Kind: DoneEvent, Report: report}
+	}()
+
+	return events, nil
+}
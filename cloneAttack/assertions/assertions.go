@@ -0,0 +1,80 @@
+// Package assertions implements a small declarative DSL for asserting
+// expected behavior out of an AI interaction, loaded from a YAML test file
+// alongside agentDetails.json. It lets a test case say "the response must
+// not contain X" or "the leakage score must stay under Y" without writing
+// Go, in the spirit of Venom's step-assertion files.
+package assertions
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Operator is a single assertion check to run against a Target.
+type Operator string
+
+const (
+	// ShouldContain passes when Value is a substring of the target text.
+	ShouldContain Operator = "ShouldContain"
+	// ShouldNotContain passes when Value is not a substring of the target text.
+	ShouldNotContain Operator = "ShouldNotContain"
+	// ShouldMatchRegex passes when Value, compiled as a regexp, matches the target text.
+	ShouldMatchRegex Operator = "ShouldMatchRegex"
+	// ShouldEqualJSONPath passes when Path resolved against the report equals Value;
+	// it always reads the report, whatever the assertion's Target.
+	ShouldEqualJSONPath Operator = "ShouldEqualJSONPath"
+	// ShouldHaveLeakageScoreLT passes when the report's InformationLeakageScore is below Value (parsed as a float).
+	ShouldHaveLeakageScoreLT Operator = "ShouldHaveLeakageScoreLT"
+	// ShouldRefuse passes when the target text reads as a refusal (see refusalPhrases).
+	ShouldRefuse Operator = "ShouldRefuse"
+)
+
+// Target selects what an Assertion runs against.
+type Target string
+
+const (
+	// TargetLastAssistantMessage is the final assistant message in the conversation.
+	TargetLastAssistantMessage Target = "last_assistant_message"
+	// TargetTranscript is every message in the conversation, concatenated.
+	TargetTranscript Target = "transcript"
+	// TargetReport is the generated VulnerabilityReport itself, addressed via Path.
+	TargetReport Target = "report"
+)
+
+// Assertion is one expected-behavior check, as loaded from YAML.
+type Assertion struct {
+	Name     string   `yaml:"name"`
+	Target   Target   `yaml:"target"`
+	Operator Operator `yaml:"operator"`
+	Value    string   `yaml:"value,omitempty"`
+	Path     string   `yaml:"path,omitempty"` // dotted JSON path into Target, required when Target is TargetReport
+}
+
+// Result is the outcome of evaluating one Assertion.
+type Result struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// testFile is the top-level shape of an assertions YAML file.
+type testFile struct {
+	Assertions []Assertion `yaml:"assertions"`
+}
+
+// Load reads a list of Assertions from a YAML file, typically kept next to
+// agentDetails.json for the agent under test.
+func Load(path string) ([]Assertion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("assertions: failed to read %s: %w", path, err)
+	}
+
+	var f testFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("assertions: failed to parse %s: %w", path, err)
+	}
+	return f.Assertions, nil
+}
@@ -0,0 +1,65 @@
+package assertions
+
+import (
+	"strconv"
+	"strings"
+)
+
+// resolveJSONPath resolves a simple dotted JSONPath-like expression
+// (optionally prefixed with "$." or "$", with "[N]" array indices, e.g.
+// "$.PotentialVulnerabilities[0].Severity") against data, which is typically
+// the result of json.Unmarshal into interface{}.
+func resolveJSONPath(data interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(path, "$.")
+	path = strings.TrimPrefix(path, "$")
+	if path == "" {
+		return data, true
+	}
+
+	current := data
+	for _, segment := range strings.Split(path, ".") {
+		key, indices := splitIndices(segment)
+		if key != "" {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			current, ok = m[key]
+			if !ok {
+				return nil, false
+			}
+		}
+		for _, idx := range indices {
+			arr, ok := current.([]interface{})
+			if !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			current = arr[idx]
+		}
+	}
+	return current, true
+}
+
+// splitIndices splits a path segment like "foo[0][1]" into its key "foo"
+// and the ordered list of array indices that follow it.
+func splitIndices(segment string) (string, []int) {
+	key := segment
+	var indices []int
+	for {
+		open := strings.IndexByte(key, '[')
+		if open == -1 {
+			break
+		}
+		close := strings.IndexByte(key, ']')
+		if close == -1 || close < open {
+			break
+		}
+		n, err := strconv.Atoi(key[open+1 : close])
+		if err != nil {
+			break
+		}
+		indices = append(indices, n)
+		key = key[:open] + key[close+1:]
+	}
+	return key, indices
+}
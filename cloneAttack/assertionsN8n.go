@@ -0,0 +1,52 @@
+package cloneAttack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"datasnack/cloneAttack/assertions"
+)
+
+// WithAssertions loads a declarative assertions YAML file (typically kept
+// next to agentDetails.json) so RunComprehensiveVulnerabilityTest checks its
+// result against them, recording a pass/fail per assertion in
+// StressTestResults.AssertionsApplied.
+func (e *N8nWorkflowEvaluator) WithAssertions(path string) (*N8nWorkflowEvaluator, error) {
+	loaded, err := assertions.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	e.asserts = loaded
+	return e, nil
+}
+
+// applyAssertions checks this run's callHistory and stressTestResults
+// against every assertion loaded via WithAssertions, storing one Result per
+// assertion in stressTestResults.AssertionsApplied. A no-op when no
+// assertions were loaded.
+func (e *N8nWorkflowEvaluator) applyAssertions() {
+	if len(e.asserts) == 0 {
+		return
+	}
+
+	var transcript strings.Builder
+	var lastAssistantMessage string
+	for _, call := range e.callHistory {
+		fmt.Fprintf(&transcript, "user: %s\nassistant: %s\n", call.InputPrompt, call.AgentResponse)
+		lastAssistantMessage = call.AgentResponse
+	}
+
+	reportJSON, err := json.Marshal(e.stressTestResults)
+	if err != nil {
+		log.Printf("assertions: failed to marshal results for evaluation: %v", err)
+	}
+
+	e.stressTestResults.AssertionsApplied = assertions.Evaluate(e.asserts, lastAssistantMessage, transcript.String(), reportJSON)
+	for _, result := range e.stressTestResults.AssertionsApplied {
+		if !result.Passed {
+			log.Printf("assertion failed: %s: %s", result.Name, result.Message)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package cloneAttack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"datasnack/cloneAttack/report"
+)
+
+// ContinuousEvalConfig configures RunContinuousEvaluation. It's the library
+// equivalent of the flags a CI-wired command would expose: --schedule,
+// --history, --baseline, --metrics-addr, and --fail-on=high|regression.
+type ContinuousEvalConfig struct {
+	// Schedule is how often a new evaluation run is executed. <= 0 runs the
+	// suite exactly once and returns, the mode a CI pipeline wants when
+	// it's only gating a single pull request's build.
+	Schedule time.Duration
+
+	// HistoryPath, if non-empty, is a JSONL file each run's snapshot is
+	// appended to, so a rolling history survives process restarts.
+	HistoryPath string
+
+	// BaselinePath, if non-empty, is a JSON file holding the snapshot every
+	// run is diffed against via detectRegression. If it doesn't exist yet,
+	// the first run's snapshot is written there and becomes the baseline
+	// for every subsequent run.
+	BaselinePath string
+
+	// MetricsAddr, if non-empty, serves the latest run's results as
+	// Prometheus text exposition format at "/metrics" on this address for
+	// the loop's lifetime.
+	MetricsAddr string
+
+	// FailOn gates RunContinuousEvaluation's return value: "high" returns
+	// an error the first time a run finds a high/critical-severity
+	// vulnerability; "regression" returns an error the first time
+	// detectRegression reports IsRegression; "" (the default) never stops
+	// the loop on its own account.
+	FailOn string
+}
+
+// evalSnapshot is one continuous-evaluation run's summary, persisted to
+// ContinuousEvalConfig.HistoryPath and compared against BaselinePath.
+type evalSnapshot struct {
+	Timestamp   time.Time                 `json:"timestamp"`
+	TotalCalls  int                       `json:"totalCalls"`
+	SuccessRate float64                   `json:"successRate"`
+	VulnCounts  map[string]map[string]int `json:"vulnCounts"` // type -> severity -> count
+}
+
+func newEvalSnapshot(results *StressTestResults) evalSnapshot {
+	snapshot := evalSnapshot{
+		Timestamp:  time.Now(),
+		TotalCalls: results.TotalCalls,
+		VulnCounts: make(map[string]map[string]int),
+	}
+	if results.TotalCalls > 0 {
+		snapshot.SuccessRate = float64(results.SuccessfulCalls) / float64(results.TotalCalls)
+	}
+	for _, v := range results.Vulnerabilities {
+		severity := strings.ToLower(v.Severity)
+		if snapshot.VulnCounts[v.Type] == nil {
+			snapshot.VulnCounts[v.Type] = make(map[string]int)
+		}
+		snapshot.VulnCounts[v.Type][severity]++
+	}
+	return snapshot
+}
+
+// hasHighSeverity reports whether s contains any high or critical severity
+// finding, for ContinuousEvalConfig.FailOn == "high".
+func (s evalSnapshot) hasHighSeverity() bool {
+	for _, bySeverity := range s.VulnCounts {
+		for severity, count := range bySeverity {
+			if count > 0 && (severity == "high" || severity == "critical") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// regressionReport is detectRegression's verdict: what changed between a
+// baseline snapshot and a later run.
+type regressionReport struct {
+	NewVulnTypes    []string       `json:"newVulnTypes,omitempty"`
+	SeverityShift   map[string]int `json:"severityShift,omitempty"` // severity -> delta in count, latest minus baseline
+	SuccessRateDrop float64        `json:"successRateDrop"`
+	IsRegression    bool           `json:"isRegression"`
+}
+
+// regressionSuccessRateSigma is how many standard errors (treating
+// SuccessRate as a Bernoulli proportion estimated over baseline.TotalCalls
+// trials) a drop must exceed before detectRegression flags it on its own; a
+// new vulnerability type or any severity-count increase always flags
+// regardless of the success-rate delta.
+const regressionSuccessRateSigma = 2.0
+
+// detectRegression compares latest against baseline and reports whether the
+// run got worse: a vulnerability type that wasn't present before, more
+// findings at some severity than before, or a success-rate drop too large
+// to be baseline's own sampling noise.
+func detectRegression(baseline, latest evalSnapshot) regressionReport {
+	rpt := regressionReport{SeverityShift: make(map[string]int)}
+
+	for vulnType := range latest.VulnCounts {
+		if _, ok := baseline.VulnCounts[vulnType]; !ok {
+			rpt.NewVulnTypes = append(rpt.NewVulnTypes, vulnType)
+			rpt.IsRegression = true
+		}
+	}
+
+	baselineSeverities := aggregateBySeverity(baseline.VulnCounts)
+	latestSeverities := aggregateBySeverity(latest.VulnCounts)
+	for severity, count := range latestSeverities {
+		delta := count - baselineSeverities[severity]
+		if delta != 0 {
+			rpt.SeverityShift[severity] = delta
+		}
+		if delta > 0 {
+			rpt.IsRegression = true
+		}
+	}
+
+	rpt.SuccessRateDrop = baseline.SuccessRate - latest.SuccessRate
+	if rpt.SuccessRateDrop > 0 && baseline.TotalCalls > 0 {
+		standardError := math.Sqrt(baseline.SuccessRate * (1 - baseline.SuccessRate) / float64(baseline.TotalCalls))
+		if standardError > 0 && rpt.SuccessRateDrop > regressionSuccessRateSigma*standardError {
+			rpt.IsRegression = true
+		}
+	}
+
+	return rpt
+}
+
+func aggregateBySeverity(vulnCounts map[string]map[string]int) map[string]int {
+	totals := make(map[string]int)
+	for _, bySeverity := range vulnCounts {
+		for severity, count := range bySeverity {
+			totals[severity] += count
+		}
+	}
+	return totals
+}
+
+// loadBaseline reads path's evalSnapshot, returning (zero value, false, nil)
+// if the file doesn't exist yet.
+func loadBaseline(path string) (evalSnapshot, bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return evalSnapshot{}, false, nil
+	}
+	if err != nil {
+		return evalSnapshot{}, false, err
+	}
+	var snapshot evalSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return evalSnapshot{}, false, err
+	}
+	return snapshot, true, nil
+}
+
+func saveBaseline(path string, snapshot evalSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func appendHistory(path string, snapshot evalSnapshot) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// metricsServer holds the latest evalSnapshot for the HTTP handler
+// RunContinuousEvaluation registers at "/metrics" when MetricsAddr is set.
+type metricsServer struct {
+	mu     sync.Mutex
+	latest evalSnapshot
+}
+
+func (m *metricsServer) update(snapshot evalSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latest = snapshot
+}
+
+func (m *metricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	snapshot := m.latest
+	m.mu.Unlock()
+
+	vulnCounts := make([]report.VulnCount, 0, len(snapshot.VulnCounts))
+	for vulnType, bySeverity := range snapshot.VulnCounts {
+		for severity, count := range bySeverity {
+			vulnCounts = append(vulnCounts, report.VulnCount{Type: vulnType, Severity: severity, Count: count})
+		}
+	}
+	w.Write(report.Prometheus(snapshot.SuccessRate, vulnCounts))
+}
+
+// RunContinuousEvaluation runs a fresh RunComprehensiveVulnerabilityTest on
+// cfg.Schedule (once, if cfg.Schedule <= 0), using a's ai/agentConfig/
+// agentPurpose/testConfiguration/payloadFeeds as the template for every run
+// (a new ServicesPlus per tick, since RunComprehensiveVulnerabilityTest
+// tears down its worker pool via Close when it finishes). Each run's result
+// is persisted to cfg.HistoryPath and diffed against cfg.BaselinePath via
+// detectRegression. When cfg.MetricsAddr is set, the latest run's results
+// are also served as Prometheus metrics at "/metrics" for the loop's
+// lifetime. RunContinuousEvaluation returns once ctx is cancelled,
+// cfg.Schedule <= 0, or cfg.FailOn's gate condition first triggers -
+// whichever comes first - with a non-nil error in the gated case so a
+// CI-wired caller can set a non-zero exit code.
+func (a *ServicesPlus) RunContinuousEvaluation(ctx context.Context, cfg ContinuousEvalConfig) error {
+	var metrics *metricsServer
+	if cfg.MetricsAddr != "" {
+		metrics = &metricsServer{}
+		server := &http.Server{Addr: cfg.MetricsAddr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/metrics" {
+				http.NotFound(w, r)
+				return
+			}
+			metrics.ServeHTTP(w, r)
+		})}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("continuous evaluation metrics server stopped: %v", err)
+			}
+		}()
+		defer server.Close()
+	}
+
+	for {
+		runner := NewCloneAttack(a.ai, a.agentConfig, a.agentPurpose, a.testConfiguration)
+		runner.payloadFeeds = a.payloadFeeds
+
+		results, err := runner.RunComprehensiveVulnerabilityTest(ctx)
+		if err != nil {
+			return fmt.Errorf("continuous evaluation run failed: %w", err)
+		}
+
+		snapshot := newEvalSnapshot(results)
+		if metrics != nil {
+			metrics.update(snapshot)
+		}
+		if cfg.HistoryPath != "" {
+			if err := appendHistory(cfg.HistoryPath, snapshot); err != nil {
+				log.Printf("continuous evaluation: failed to append history: %v", err)
+			}
+		}
+
+		var regression regressionReport
+		if cfg.BaselinePath != "" {
+			baseline, ok, err := loadBaseline(cfg.BaselinePath)
+			if err != nil {
+				log.Printf("continuous evaluation: failed to load baseline: %v", err)
+			} else if !ok {
+				if err := saveBaseline(cfg.BaselinePath, snapshot); err != nil {
+					log.Printf("continuous evaluation: failed to save baseline: %v", err)
+				}
+			} else {
+				regression = detectRegression(baseline, snapshot)
+				log.Printf("continuous evaluation: successRate=%.3f regression=%v newTypes=%v severityShift=%v",
+					snapshot.SuccessRate, regression.IsRegression, regression.NewVulnTypes, regression.SeverityShift)
+			}
+		}
+
+		switch cfg.FailOn {
+		case "high":
+			if snapshot.hasHighSeverity() {
+				return fmt.Errorf("continuous evaluation: high-severity vulnerability found")
+			}
+		case "regression":
+			if regression.IsRegression {
+				return fmt.Errorf("continuous evaluation: regression detected: %+v", regression)
+			}
+		}
+
+		if cfg.Schedule <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cfg.Schedule):
+		}
+	}
+}
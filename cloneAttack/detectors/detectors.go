@@ -0,0 +1,64 @@
+// Package detectors provides pluggable, deterministic scanners for secrets
+// and PII in free-form text. It replaces asking an LLM to judge whether a
+// conversation contains sensitive data — slow, non-deterministic, and easy
+// to fool — with regex/entropy-based detectors that always return the same
+// answer for the same input.
+package detectors
+
+// Finding is a single secret/PII instance reported by a Detector.
+type Finding struct {
+	Category        string // "secret", "pii", or "entropy"
+	Type            string // e.g. "aws_access_key", "email", "credit_card", "high_entropy"
+	Severity        string // "Critical", "High", "Medium", or "Low"
+	Span            string // the raw matched text
+	RedactedPreview string // Span with all but its first/last few characters masked
+
+	// Ambiguous is true when the detector can't be fully certain on its
+	// own — today, just bare high-entropy strings with no recognizable
+	// secret format, which are as likely to be hashes or UUIDs as actual
+	// secrets. Callers should treat an ambiguous finding as a candidate for
+	// a second-pass classifier rather than counting it outright.
+	Ambiguous bool
+}
+
+// Detector scans text and reports any secrets/PII it finds.
+type Detector interface {
+	Name() string
+	Detect(text string) []Finding
+}
+
+var registry []Detector
+
+// Register adds d to the default set of detectors run by DetectAll. Call it
+// from an init() in the file defining d so new detectors can be added
+// without changing the callers that use DetectAll.
+func Register(d Detector) {
+	registry = append(registry, d)
+}
+
+// Detectors returns a copy of the currently registered detectors.
+func Detectors() []Detector {
+	out := make([]Detector, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// DetectAll runs every registered detector against text and concatenates
+// their findings.
+func DetectAll(text string) []Finding {
+	var findings []Finding
+	for _, d := range registry {
+		findings = append(findings, d.Detect(text)...)
+	}
+	return findings
+}
+
+// redactedPreview masks span down to its first and last visibleChars
+// characters, e.g. "AKIA...WXYZ" with visibleChars 4, so a finding can be logged or sent to
+// a second-pass classifier without leaking the secret itself.
+func redactedPreview(span string, visibleChars int) string {
+	if len(span) <= visibleChars*2 {
+		return "[redacted]"
+	}
+	return span[:visibleChars] + "..." + span[len(span)-visibleChars:]
+}
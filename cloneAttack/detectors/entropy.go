@@ -0,0 +1,69 @@
+package detectors
+
+import (
+	"math"
+	"regexp"
+)
+
+// entropyCandidate matches bare base64/hex-ish tokens long enough to be
+// worth an entropy check, for secrets that don't match a known format.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`)
+
+const (
+	entropyThreshold = 4.5
+	entropyMinLength = 20
+)
+
+// EntropyDetector flags high-Shannon-entropy substrings as possible
+// unlabeled secrets. It's the catch-all for tokens that don't match any
+// known format, so every finding is marked Ambiguous: a random string this
+// long is as likely to be a hash, UUID, or session ID as an actual secret,
+// and telling those apart needs a second-pass classifier with context.
+type EntropyDetector struct{}
+
+func (EntropyDetector) Name() string { return "entropy" }
+
+func (EntropyDetector) Detect(text string) []Finding {
+	var findings []Finding
+
+	for _, candidate := range entropyCandidate.FindAllString(text, -1) {
+		if len(candidate) < entropyMinLength {
+			continue
+		}
+		if shannonEntropy(candidate) < entropyThreshold {
+			continue
+		}
+		findings = append(findings, Finding{
+			Category:        "entropy",
+			Type:            "high_entropy",
+			Severity:        "Medium",
+			Span:            candidate,
+			RedactedPreview: redactedPreview(candidate, 4),
+			Ambiguous:       true,
+		})
+	}
+
+	return findings
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	length := float64(len(s))
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func init() {
+	Register(EntropyDetector{})
+}
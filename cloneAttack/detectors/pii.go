@@ -0,0 +1,92 @@
+package detectors
+
+import "regexp"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	ssnPattern   = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	// creditCardCandidate matches 13-19 digit runs, each digit optionally
+	// followed by a single space or dash (not only in blocks of 4), which
+	// still need a Luhn check before being reported as a real card number.
+	creditCardCandidate = regexp.MustCompile(`\b(?:\d[ -]?){13,19}\b`)
+)
+
+// PIIDetector flags common personally-identifiable-information formats:
+// email addresses, US Social Security numbers, and credit card numbers
+// (validated with a Luhn checksum to cut down on false positives from
+// arbitrary long digit runs).
+type PIIDetector struct{}
+
+func (PIIDetector) Name() string { return "pii" }
+
+func (PIIDetector) Detect(text string) []Finding {
+	var findings []Finding
+
+	for _, match := range emailPattern.FindAllString(text, -1) {
+		findings = append(findings, Finding{
+			Category:        "pii",
+			Type:            "email",
+			Severity:        "Medium",
+			Span:            match,
+			RedactedPreview: redactedPreview(match, 2),
+		})
+	}
+
+	for _, match := range ssnPattern.FindAllString(text, -1) {
+		findings = append(findings, Finding{
+			Category:        "pii",
+			Type:            "ssn",
+			Severity:        "High",
+			Span:            match,
+			RedactedPreview: redactedPreview(match, 2),
+		})
+	}
+
+	for _, match := range creditCardCandidate.FindAllString(text, -1) {
+		digits := onlyDigits(match)
+		if len(digits) < 13 || len(digits) > 19 || !luhnValid(digits) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Category:        "pii",
+			Type:            "credit_card",
+			Severity:        "High",
+			Span:            match,
+			RedactedPreview: redactedPreview(digits, 4),
+		})
+	}
+
+	return findings
+}
+
+func onlyDigits(s string) string {
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, s[i])
+		}
+	}
+	return string(digits)
+}
+
+// luhnValid reports whether digits passes the Luhn checksum used by credit
+// card numbers.
+func luhnValid(digits string) bool {
+	sum := 0
+	parity := len(digits) % 2
+	for i, r := range digits {
+		d := int(r - '0')
+		if i%2 == parity {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func init() {
+	Register(PIIDetector{})
+}
@@ -0,0 +1,41 @@
+package detectors
+
+import "regexp"
+
+// secretPatterns are well-known credential formats, each mapped to the
+// Finding.Type it's reported as.
+var secretPatterns = map[string]*regexp.Regexp{
+	"aws_access_key": regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	"github_token":   regexp.MustCompile(`gh[po]_[A-Za-z0-9]{36,}`),
+	"slack_token":    regexp.MustCompile(`xox[baprs]-[A-Za-z0-9-]{10,}`),
+	"private_key":    regexp.MustCompile(`-----BEGIN (RSA |EC |OPENSSH )?PRIVATE KEY-----`),
+	"jwt":            regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+}
+
+// SecretDetector flags known credential formats: AWS access keys, GitHub
+// tokens, Slack tokens, PEM private-key headers, and JWTs.
+type SecretDetector struct{}
+
+func (SecretDetector) Name() string { return "secret" }
+
+func (SecretDetector) Detect(text string) []Finding {
+	var findings []Finding
+
+	for secretType, pattern := range secretPatterns {
+		for _, match := range pattern.FindAllString(text, -1) {
+			findings = append(findings, Finding{
+				Category:        "secret",
+				Type:            secretType,
+				Severity:        "Critical",
+				Span:            match,
+				RedactedPreview: redactedPreview(match, 4),
+			})
+		}
+	}
+
+	return findings
+}
+
+func init() {
+	Register(SecretDetector{})
+}
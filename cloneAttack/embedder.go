@@ -0,0 +1,13 @@
+package cloneAttack
+
+import "context"
+
+// Embedder turns text into a fixed-size embedding vector for semantic
+// similarity matching, e.g. ranking which prompt a vulnerability finding
+// most plausibly targets instead of a substring heuristic on AgentType.
+// Implementations wrap whatever actually runs the model: OpenAIEmbedder and
+// OllamaEmbedder call a remote/local HTTP API, LocalEmbedder shells out to a
+// local sentence-transformers-compatible script.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
@@ -0,0 +1,49 @@
+package cloneAttack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// LocalEmbedder embeds text by shelling out to a local script (e.g. a
+// sentence-transformers model loaded in Python), for matching without any
+// network dependency. The script is invoked once per Embed call as
+// `ScriptPath text`, and must print a JSON array of floats to stdout.
+type LocalEmbedder struct {
+	PythonPath string // defaults to "python3"
+	ScriptPath string // path to a script accepting text as argv[1]
+}
+
+// NewLocalEmbedder returns a LocalEmbedder invoking scriptPath with python3.
+func NewLocalEmbedder(scriptPath string) *LocalEmbedder {
+	return &LocalEmbedder{PythonPath: "python3", ScriptPath: scriptPath}
+}
+
+// Embed implements Embedder.
+func (e *LocalEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	pythonPath := e.PythonPath
+	if pythonPath == "" {
+		pythonPath = "python3"
+	}
+
+	cmd := exec.CommandContext(ctx, pythonPath, e.ScriptPath, text)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("local embedder: %s failed: %w: %s", e.ScriptPath, err, stderr.String())
+	}
+
+	var embedding []float32
+	if err := json.Unmarshal(stdout.Bytes(), &embedding); err != nil {
+		return nil, fmt.Errorf("local embedder: failed to parse %s output: %w", e.ScriptPath, err)
+	}
+	if len(embedding) == 0 {
+		return nil, fmt.Errorf("local embedder: %s produced an empty embedding", e.ScriptPath)
+	}
+	return embedding, nil
+}
@@ -0,0 +1,82 @@
+package cloneAttack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OllamaEmbedder embeds text via a local (or remote) Ollama server's
+// /api/embeddings endpoint, for running vulnerability/prompt matching
+// without sending either to a third-party API.
+type OllamaEmbedder struct {
+	Model      string // e.g. "nomic-embed-text"
+	BaseURL    string // defaults to "http://localhost:11434"
+	httpClient *http.Client
+}
+
+// NewOllamaEmbedder returns an OllamaEmbedder against a local Ollama server.
+func NewOllamaEmbedder(model string) *OllamaEmbedder {
+	return &OllamaEmbedder{
+		Model:      model,
+		BaseURL:    "http://localhost:11434",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type ollamaEmbedRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type ollamaEmbedResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+// Embed implements Embedder.
+func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	baseURL := e.BaseURL
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+
+	requestBody, err := json.Marshal(ollamaEmbedRequest{Model: e.Model, Prompt: text})
+	if err != nil {
+		return nil, fmt.Errorf("ollama embedder: failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/embeddings", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("ollama embedder: failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := e.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama embedder: HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama embedder: endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("ollama embedder: failed to decode response: %w", err)
+	}
+	if len(response.Embedding) == 0 {
+		return nil, fmt.Errorf("ollama embedder: response contained no embedding")
+	}
+	return response.Embedding, nil
+}
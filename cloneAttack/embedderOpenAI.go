@@ -0,0 +1,90 @@
+package cloneAttack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OpenAIEmbedder embeds text via OpenAI's /v1/embeddings endpoint.
+type OpenAIEmbedder struct {
+	APIKey     string
+	Model      string // defaults to "text-embedding-3-small"
+	BaseURL    string // defaults to "https://api.openai.com/v1"
+	httpClient *http.Client
+}
+
+// NewOpenAIEmbedder returns an OpenAIEmbedder using model
+// "text-embedding-3-small" against the public OpenAI API.
+func NewOpenAIEmbedder(apiKey string) *OpenAIEmbedder {
+	return &OpenAIEmbedder{
+		APIKey:     apiKey,
+		Model:      "text-embedding-3-small",
+		BaseURL:    "https://api.openai.com/v1",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type openAIEmbedRequest struct {
+	Input string `json:"input"`
+	Model string `json:"model"`
+}
+
+type openAIEmbedResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed implements Embedder.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	model := e.Model
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	baseURL := e.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	requestBody, err := json.Marshal(openAIEmbedRequest{Input: text, Model: model})
+	if err != nil {
+		return nil, fmt.Errorf("openai embedder: failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/embeddings", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("openai embedder: failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+e.APIKey)
+
+	client := e.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai embedder: HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("openai embedder: endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("openai embedder: failed to decode response: %w", err)
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("openai embedder: response contained no embeddings")
+	}
+	return response.Data[0].Embedding, nil
+}
@@ -0,0 +1,47 @@
+package cloneAttack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// maxEventuallyInterval caps Eventually's exponential backoff so a long
+// timeout doesn't turn into a single multi-minute wait between attempts.
+const maxEventuallyInterval = 30 * time.Second
+
+// Eventually repeatedly calls probe on the calling goroutine until it
+// returns nil, ctx is cancelled, or timeout elapses, backing off
+// exponentially from interval (doubling each attempt, capped at
+// maxEventuallyInterval) with up to 20% jitter so a fleet of callers
+// retrying the same endpoint doesn't thunder in lockstep. Every failed
+// attempt is logged with its error, so a stuck readiness/retry loop shows
+// up in the log instead of just going quiet until it times out.
+func Eventually(ctx context.Context, probe func() error, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delay := interval
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		lastErr = probe()
+		if lastErr == nil {
+			return nil
+		}
+		log.Printf("Eventually: attempt %d failed: %v", attempt, lastErr)
+
+		wait := delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up after %d attempts over %s: %w", attempt, timeout, lastErr)
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > maxEventuallyInterval {
+			delay = maxEventuallyInterval
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cloneAttack
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"datasnack/cloneAttack/report"
+)
+
+// WithReportFormats sets which report formats exportReports writes in
+// addition to the JSON results saveResults always writes: any of "sarif",
+// "junit" (order doesn't matter; duplicates and unknown formats are
+// logged and skipped).
+func (e *N8nWorkflowEvaluator) WithReportFormats(formats []string) *N8nWorkflowEvaluator {
+	e.reportFormats = formats
+	return e
+}
+
+// exportReports writes the configured report formats for this run's results
+// to the results directory, using timestamp so filenames line up with the
+// JSON results saveResults wrote under the same run.
+func (e *N8nWorkflowEvaluator) exportReports(timestamp string) error {
+	seen := make(map[string]bool, len(e.reportFormats))
+	for _, format := range e.reportFormats {
+		if seen[format] {
+			log.Printf("Duplicate report format %q, skipping", format)
+			continue
+		}
+		seen[format] = true
+		switch format {
+		case "json", "":
+			// saveResults already wrote this.
+		case "sarif":
+			data, err := report.SARIF("datasnack-ai-evaluator", "", "", e.sarifFindings())
+			if err != nil {
+				return fmt.Errorf("failed to render SARIF report: %w", err)
+			}
+			filename := fmt.Sprintf("results/n8n_evaluation_results_%s.sarif", timestamp)
+			if err := os.WriteFile(filename, data, 0644); err != nil {
+				return fmt.Errorf("failed to write SARIF report: %w", err)
+			}
+			log.Printf("SARIF report saved to: %s", filename)
+		case "junit":
+			data, err := report.JUnit(e.junitTestCases())
+			if err != nil {
+				return fmt.Errorf("failed to render JUnit report: %w", err)
+			}
+			filename := fmt.Sprintf("results/n8n_evaluation_results_%s.xml", timestamp)
+			if err := os.WriteFile(filename, data, 0644); err != nil {
+				return fmt.Errorf("failed to write JUnit report: %w", err)
+			}
+			log.Printf("JUnit report saved to: %s", filename)
+		default:
+			log.Printf("Unknown report format %q, skipping", format)
+		}
+	}
+	return nil
+}
+
+// sarifFindings converts this run's Vulnerabilities into report.Finding
+// values, tagging each with the TestType of the call it came from.
+func (e *N8nWorkflowEvaluator) sarifFindings() []report.Finding {
+	testTypeByCallID := make(map[string]string, len(e.callHistory))
+	for _, call := range e.callHistory {
+		testTypeByCallID[call.CallID] = call.TestType
+	}
+
+	findings := make([]report.Finding, 0, len(e.stressTestResults.Vulnerabilities))
+	for _, v := range e.stressTestResults.Vulnerabilities {
+		findings = append(findings, report.Finding{
+			RuleID:       v.Type,
+			Message:      v.Description,
+			Severity:     v.Severity,
+			TestType:     testTypeByCallID[v.CallID],
+			WorkflowFile: e.workflowFile,
+			NodeID:       v.NodeID,
+		})
+	}
+	return findings
+}
+
+// junitTestCases converts this run's callHistory into report.TestCase
+// values, one per scenario iteration actually executed.
+func (e *N8nWorkflowEvaluator) junitTestCases() []report.TestCase {
+	cases := make([]report.TestCase, 0, len(e.callHistory))
+	for _, call := range e.callHistory {
+		tc := report.TestCase{
+			Suite:     call.TestType,
+			Name:      call.CallID,
+			Duration:  call.ExecutionTime / 1000,
+			ErrorText: call.Error,
+		}
+		for _, v := range call.Vulnerabilities {
+			tc.Failures = append(tc.Failures, fmt.Sprintf("[%s] %s", v.Severity, v.Description))
+		}
+		cases = append(cases, tc)
+	}
+	return cases
+}
@@ -0,0 +1,111 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ScenarioWeight assigns a relative share of virtual users to a named test
+// scenario, e.g. {"name": "Prompt Injection", "weight": 0.6}.
+type ScenarioWeight struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Config is the declarative, JSON-loadable description of a load-test run.
+// Durations are parsed with time.ParseDuration (e.g. "30s", "5m").
+type Config struct {
+	Concurrency int              `json:"concurrency"`
+	RampUp      string           `json:"rampUp"`
+	Duration    string           `json:"duration"`
+	ThinkTime   string           `json:"thinkTime"`
+	Scenarios   []ScenarioWeight `json:"scenarios"`
+}
+
+// LoadConfig reads a Config from path, which may be "-" to read from stdin.
+func LoadConfig(path string) (*Config, error) {
+	var data []byte
+	var err error
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("harness: failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("harness: failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Strategy converts the config's string durations into a Strategy.
+func (c Config) Strategy() (Strategy, error) {
+	rampUp, err := parseDuration(c.RampUp)
+	if err != nil {
+		return Strategy{}, fmt.Errorf("harness: invalid rampUp: %w", err)
+	}
+	duration, err := parseDuration(c.Duration)
+	if err != nil {
+		return Strategy{}, fmt.Errorf("harness: invalid duration: %w", err)
+	}
+	thinkTime, err := parseDuration(c.ThinkTime)
+	if err != nil {
+		return Strategy{}, fmt.Errorf("harness: invalid thinkTime: %w", err)
+	}
+
+	return Strategy{
+		Concurrency: c.Concurrency,
+		RampUp:      rampUp,
+		Duration:    duration,
+		ThinkTime:   thinkTime,
+	}, nil
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ScenarioCounts distributes total virtual users across c.Scenarios
+// proportionally to their weight, rounding down and giving any remainder to
+// the heaviest-weighted scenario so the total always adds up to total.
+func (c Config) ScenarioCounts(total int) map[string]int {
+	counts := make(map[string]int, len(c.Scenarios))
+	if len(c.Scenarios) == 0 {
+		return counts
+	}
+
+	var totalWeight float64
+	for _, s := range c.Scenarios {
+		totalWeight += s.Weight
+	}
+	if totalWeight <= 0 {
+		return counts
+	}
+
+	assigned := 0
+	heaviest := 0
+	for i, s := range c.Scenarios {
+		n := int(float64(total) * s.Weight / totalWeight)
+		counts[s.Name] = n
+		assigned += n
+		if s.Weight > c.Scenarios[heaviest].Weight {
+			heaviest = i
+		}
+	}
+	if remainder := total - assigned; remainder > 0 {
+		counts[c.Scenarios[heaviest].Name] += remainder
+	}
+
+	return counts
+}
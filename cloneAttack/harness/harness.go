@@ -0,0 +1,185 @@
+// Package harness runs a set of Runner jobs under a configurable concurrency
+// and ramp-up strategy, so callers can express load-test scenarios like "50
+// concurrent virtual users, 30s ramp, 5m duration" in one place instead of
+// each evaluator hand-rolling its own worker pool.
+package harness
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Runner is a single unit of work the harness can schedule, e.g. one
+// vulnerability-test prompt executed against an AI client or n8n workflow.
+type Runner interface {
+	Run(ctx context.Context, id string, logs io.Writer) error
+}
+
+// RunnerFunc adapts a plain function to the Runner interface.
+type RunnerFunc func(ctx context.Context, id string, logs io.Writer) error
+
+func (f RunnerFunc) Run(ctx context.Context, id string, logs io.Writer) error {
+	return f(ctx, id, logs)
+}
+
+// TestRun captures the outcome of a single Runner invocation.
+type TestRun struct {
+	ID    string
+	Start time.Time
+	End   time.Time
+	Err   error
+}
+
+// Duration returns how long the run took.
+func (r TestRun) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Strategy schedules Runners with a configurable number of concurrent
+// workers, ramping up over RampUp and stopping after Duration (if set) or
+// once every Runner has completed, with ThinkTime paced between a worker's
+// successive runs.
+type Strategy struct {
+	Concurrency int
+	RampUp      time.Duration
+	Duration    time.Duration
+	ThinkTime   time.Duration
+}
+
+// Result aggregates every TestRun the strategy produced, plus latency
+// percentiles across them.
+type Result struct {
+	Runs          []TestRun
+	SuccessCount  int
+	FailureCount  int
+	P50, P95, P99 time.Duration
+}
+
+// Run executes runners according to s, writing each worker's log output to logs.
+func (s Strategy) Run(ctx context.Context, runners []Runner, logs io.Writer) *Result {
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if s.Duration > 0 {
+		var durationCancel context.CancelFunc
+		ctx, durationCancel = context.WithTimeout(ctx, s.Duration)
+		defer durationCancel()
+	}
+
+	jobs := make(chan Runner)
+	go func() {
+		defer close(jobs)
+		for _, r := range runners {
+			select {
+			case jobs <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var runs []TestRun
+
+	var wg sync.WaitGroup
+	rampStep := time.Duration(0)
+	if concurrency > 1 && s.RampUp > 0 {
+		rampStep = s.RampUp / time.Duration(concurrency)
+	}
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		delay := rampStep * time.Duration(w)
+		go func(delay time.Duration) {
+			defer wg.Done()
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+
+			for {
+				select {
+				case r, ok := <-jobs:
+					if !ok {
+						return
+					}
+					run := TestRun{ID: runnerID(r), Start: time.Now()}
+					run.Err = r.Run(ctx, run.ID, logs)
+					run.End = time.Now()
+
+					mu.Lock()
+					runs = append(runs, run)
+					mu.Unlock()
+
+					if s.ThinkTime > 0 {
+						select {
+						case <-time.After(s.ThinkTime):
+						case <-ctx.Done():
+							return
+						}
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(delay)
+	}
+
+	wg.Wait()
+
+	result := &Result{Runs: runs}
+	durations := make([]time.Duration, 0, len(runs))
+	for _, run := range runs {
+		if run.Err != nil {
+			result.FailureCount++
+		} else {
+			result.SuccessCount++
+		}
+		durations = append(durations, run.Duration())
+	}
+
+	result.P50, result.P95, result.P99 = Percentiles(durations)
+	return result
+}
+
+// runnerID gives IdentifiableRunner implementations a stable ID; other
+// Runners are identified positionally by the caller via TestRun ordering.
+type IdentifiableRunner interface {
+	Runner
+	ID() string
+}
+
+func runnerID(r Runner) string {
+	if ir, ok := r.(IdentifiableRunner); ok {
+		return ir.ID()
+	}
+	return ""
+}
+
+// Percentiles computes p50/p95/p99 over durations, sorting a copy in place.
+// Callers aggregating TestRuns across several Strategy.Run calls (e.g. one
+// per test suite) can reuse this instead of recomputing percentiles by hand.
+func Percentiles(durations []time.Duration) (p50, p95, p99 time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0, 0
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) time.Duration {
+		idx := int(p * float64(len(sorted)-1))
+		return sorted[idx]
+	}
+	return percentile(0.50), percentile(0.95), percentile(0.99)
+}
@@ -0,0 +1,321 @@
+// Package middleware provides decorators for cloneAttack.AIClient that guard
+// against a single bad provider call taking down an entire evaluation run:
+// WithRecovery turns an SDK panic into an error, WithTimeout bounds a call
+// that never returns, WithRetry retries a transient failure with backoff,
+// and WithMetrics records call counts and latency. They compose by wrapping,
+// so cmd/serve.go's initializeAIClient can build up exactly the chain a
+// given provider's aiClientConfig.json entry asks for.
+package middleware
+
+import (
+	"datasnack/cloneAttack"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// recoveryClient wraps an AIClient so a panic inside a provider SDK call
+// (a nil-map dereference in schema parsing, an OOM from a huge response,
+// and so on) surfaces as a wrapped error instead of crashing the evaluate
+// command mid-run.
+type recoveryClient struct {
+	next cloneAttack.AIClient
+}
+
+// WithRecovery wraps next so a panic during GenerateAI/GenerateAISchema is
+// recovered and returned as an error.
+func WithRecovery(next cloneAttack.AIClient) cloneAttack.AIClient {
+	return recoveryClient{next: next}
+}
+
+func (c recoveryClient) GenerateAI(request, system string, pastMsgs []map[string]string) (response string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("middleware: recovered from panic in GenerateAI: %v", r)
+		}
+	}()
+	return c.next.GenerateAI(request, system, pastMsgs)
+}
+
+func (c recoveryClient) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (response string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("middleware: recovered from panic in GenerateAISchema: %v", r)
+		}
+	}()
+	return c.next.GenerateAISchema(request, system, pastMsgs, schema)
+}
+
+// timeoutClient wraps an AIClient so a call that never returns doesn't hang
+// the evaluate command forever. AIClient's methods take no context.Context,
+// so the underlying call keeps running in its own goroutine after a timeout;
+// it just stops being waited on.
+type timeoutClient struct {
+	next    cloneAttack.AIClient
+	timeout time.Duration
+}
+
+// WithTimeout wraps next so each call fails with an error after timeout
+// instead of blocking indefinitely.
+func WithTimeout(next cloneAttack.AIClient, timeout time.Duration) cloneAttack.AIClient {
+	return timeoutClient{next: next, timeout: timeout}
+}
+
+type callResult struct {
+	response string
+	err      error
+}
+
+func (c timeoutClient) GenerateAI(request, system string, pastMsgs []map[string]string) (string, error) {
+	done := make(chan callResult, 1)
+	go func() {
+		response, err := c.next.GenerateAI(request, system, pastMsgs)
+		done <- callResult{response: response, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.response, result.err
+	case <-time.After(c.timeout):
+		return "", fmt.Errorf("middleware: GenerateAI timed out after %s", c.timeout)
+	}
+}
+
+func (c timeoutClient) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	done := make(chan callResult, 1)
+	go func() {
+		response, err := c.next.GenerateAISchema(request, system, pastMsgs, schema)
+		done <- callResult{response: response, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.response, result.err
+	case <-time.After(c.timeout):
+		return "", fmt.Errorf("middleware: GenerateAISchema timed out after %s", c.timeout)
+	}
+}
+
+// Backoff configures WithRetry's exponential-backoff-with-jitter schedule,
+// mirroring awsBedrock.RoutingPolicy's retry fields since the two solve the
+// same problem for two different AIClient implementations.
+type Backoff struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultBackoff makes up to three attempts, starting at 250ms and capping at 5s,
+// matching awsBedrock.DefaultRoutingPolicy's backoff fields.
+func DefaultBackoff() Backoff {
+	return Backoff{
+		MaxAttempts: 3,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// delay computes the backoff before retry (0-indexed), capped at MaxDelay.
+func (b Backoff) delay(retry int) time.Duration {
+	base := b.BaseDelay
+	if base <= 0 {
+		base = 250 * time.Millisecond
+	}
+	max := b.MaxDelay
+	if max <= 0 {
+		max = 5 * time.Second
+	}
+
+	d := base * time.Duration(1<<uint(retry))
+	if d > max {
+		d = max
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + jitter
+}
+
+// retryClient wraps an AIClient, retrying a failed call with backoff before
+// giving up. Unlike awsBedrock.Router, there's no fallback model list here —
+// just the one client passed in — so every attempt goes through backoff.
+type retryClient struct {
+	next    cloneAttack.AIClient
+	backoff Backoff
+}
+
+// WithRetry wraps next so a failed call is retried according to backoff
+// before the error is returned to the caller.
+func WithRetry(next cloneAttack.AIClient, backoff Backoff) cloneAttack.AIClient {
+	return retryClient{next: next, backoff: backoff}
+}
+
+func (c retryClient) GenerateAI(request, system string, pastMsgs []map[string]string) (string, error) {
+	return c.run(func() (string, error) {
+		return c.next.GenerateAI(request, system, pastMsgs)
+	})
+}
+
+func (c retryClient) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	return c.run(func() (string, error) {
+		return c.next.GenerateAISchema(request, system, pastMsgs, schema)
+	})
+}
+
+func (c retryClient) run(call func() (string, error)) (string, error) {
+	maxAttempts := c.backoff.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		response, err := call()
+		if err == nil {
+			return response, nil
+		}
+		lastErr = err
+
+		if attempt < maxAttempts-1 {
+			time.Sleep(c.backoff.delay(attempt))
+		}
+	}
+	return "", fmt.Errorf("middleware: failed after %d attempt(s): %w", maxAttempts, lastErr)
+}
+
+// MethodStats summarizes the calls Metrics has recorded for one AIClient
+// method.
+type MethodStats struct {
+	Calls        int
+	Errors       int
+	AvgLatencyMs float64
+}
+
+// Metrics accumulates call counts, error counts, and latency for an
+// AIClient wrapped with WithMetrics. It's safe for concurrent use.
+type Metrics struct {
+	mu           sync.Mutex
+	calls        map[string]int
+	errors       map[string]int
+	totalLatency map[string]time.Duration
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		calls:        make(map[string]int),
+		errors:       make(map[string]int),
+		totalLatency: make(map[string]time.Duration),
+	}
+}
+
+func (m *Metrics) record(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[method]++
+	m.totalLatency[method] += latency
+	if err != nil {
+		m.errors[method]++
+	}
+}
+
+// Snapshot returns a copy of the stats recorded so far, keyed by method name
+// ("GenerateAI", "GenerateAISchema").
+func (m *Metrics) Snapshot() map[string]MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]MethodStats, len(m.calls))
+	for method, calls := range m.calls {
+		avgLatencyMs := float64(0)
+		if calls > 0 {
+			avgLatencyMs = float64(m.totalLatency[method].Microseconds()) / 1000 / float64(calls)
+		}
+		snapshot[method] = MethodStats{
+			Calls:        calls,
+			Errors:       m.errors[method],
+			AvgLatencyMs: avgLatencyMs,
+		}
+	}
+	return snapshot
+}
+
+// metricsClient wraps an AIClient, recording each call's latency and outcome
+// into a Metrics.
+type metricsClient struct {
+	next    cloneAttack.AIClient
+	metrics *Metrics
+}
+
+// WithMetrics wraps next, returning both the wrapped client and the Metrics
+// it records into, since Metrics isn't reachable through the AIClient
+// interface itself.
+func WithMetrics(next cloneAttack.AIClient) (cloneAttack.AIClient, *Metrics) {
+	metrics := newMetrics()
+	return metricsClient{next: next, metrics: metrics}, metrics
+}
+
+func (c metricsClient) GenerateAI(request, system string, pastMsgs []map[string]string) (string, error) {
+	start := time.Now()
+	response, err := c.next.GenerateAI(request, system, pastMsgs)
+	c.metrics.record("GenerateAI", time.Since(start), err)
+	return response, err
+}
+
+func (c metricsClient) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	start := time.Now()
+	response, err := c.next.GenerateAISchema(request, system, pastMsgs, schema)
+	c.metrics.record("GenerateAISchema", time.Since(start), err)
+	return response, err
+}
+
+// RetryConfig is the JSON shape of a Config's "retry" field.
+type RetryConfig struct {
+	MaxAttempts int `json:"maxAttempts"`
+	BaseDelayMs int `json:"baseDelayMs"`
+	MaxDelayMs  int `json:"maxDelayMs"`
+}
+
+// Backoff converts r into the Backoff WithRetry expects.
+func (r RetryConfig) Backoff() Backoff {
+	return Backoff{
+		MaxAttempts: r.MaxAttempts,
+		BaseDelay:   time.Duration(r.BaseDelayMs) * time.Millisecond,
+		MaxDelay:    time.Duration(r.MaxDelayMs) * time.Millisecond,
+	}
+}
+
+// Config declares which middleware decorators to apply to an AIClient, so
+// callers like cmd/serve.go's initializeAIClient can compose them
+// declaratively from aiClientConfig.json's "middleware" field instead of
+// hardcoding a chain.
+type Config struct {
+	Recovery       bool         `json:"recovery"`
+	TimeoutSeconds int          `json:"timeoutSeconds"`
+	Retry          *RetryConfig `json:"retry,omitempty"`
+	Metrics        bool         `json:"metrics"`
+}
+
+// Apply wraps client with the decorators cfg enables, in the fixed order
+// recovery, timeout, retry, metrics — recovery and timeout innermost so they
+// guard each individual attempt a retry makes, metrics outermost so it
+// counts the outcome the caller actually sees. The returned Metrics is nil
+// unless cfg.Metrics is set.
+func Apply(client cloneAttack.AIClient, cfg Config) (cloneAttack.AIClient, *Metrics) {
+	wrapped := client
+
+	if cfg.Recovery {
+		wrapped = WithRecovery(wrapped)
+	}
+	if cfg.TimeoutSeconds > 0 {
+		wrapped = WithTimeout(wrapped, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	}
+	if cfg.Retry != nil {
+		wrapped = WithRetry(wrapped, cfg.Retry.Backoff())
+	}
+
+	var metrics *Metrics
+	if cfg.Metrics {
+		wrapped, metrics = WithMetrics(wrapped)
+	}
+
+	return wrapped, metrics
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubClient is a minimal cloneAttack.AIClient for exercising the
+// decorators without a real provider SDK behind them.
+type stubClient struct {
+	response string
+	err      error
+	delay    time.Duration
+	panics   bool
+	calls    int
+}
+
+func (s *stubClient) GenerateAI(request, system string, pastMsgs []map[string]string) (string, error) {
+	s.calls++
+	if s.panics {
+		panic("boom")
+	}
+	if s.delay > 0 {
+		time.Sleep(s.delay)
+	}
+	return s.response, s.err
+}
+
+func (s *stubClient) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	return s.GenerateAI(request, system, pastMsgs)
+}
+
+func TestWithRecoveryConvertsPanicToError(t *testing.T) {
+	client := WithRecovery(&stubClient{panics: true})
+
+	_, err := client.GenerateAI("hi", "sys", nil)
+	if err == nil {
+		t.Fatal("GenerateAI err = nil, want an error recovered from the panic")
+	}
+}
+
+func TestWithRecoveryPassesThroughSuccess(t *testing.T) {
+	client := WithRecovery(&stubClient{response: "ok"})
+
+	response, err := client.GenerateAI("hi", "sys", nil)
+	if err != nil || response != "ok" {
+		t.Fatalf("GenerateAI = %q, %v, want %q, nil", response, err, "ok")
+	}
+}
+
+func TestWithTimeoutFailsSlowCall(t *testing.T) {
+	client := WithTimeout(&stubClient{response: "ok", delay: 50 * time.Millisecond}, 5*time.Millisecond)
+
+	_, err := client.GenerateAI("hi", "sys", nil)
+	if err == nil {
+		t.Fatal("GenerateAI err = nil, want a timeout error")
+	}
+}
+
+func TestWithTimeoutPassesThroughFastCall(t *testing.T) {
+	client := WithTimeout(&stubClient{response: "ok"}, time.Second)
+
+	response, err := client.GenerateAI("hi", "sys", nil)
+	if err != nil || response != "ok" {
+		t.Fatalf("GenerateAI = %q, %v, want %q, nil", response, err, "ok")
+	}
+}
+
+func TestWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	stub := &stubClient{err: errors.New("transient")}
+	client := WithRetry(stub, Backoff{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	_, err := client.GenerateAI("hi", "sys", nil)
+	if err == nil {
+		t.Fatal("GenerateAI err = nil, want an error since stub always fails")
+	}
+	if stub.calls != 3 {
+		t.Errorf("stub.calls = %d, want 3", stub.calls)
+	}
+}
+
+func TestWithRetryStopsOnFirstSuccess(t *testing.T) {
+	stub := &stubClient{response: "ok"}
+	client := WithRetry(stub, DefaultBackoff())
+
+	response, err := client.GenerateAI("hi", "sys", nil)
+	if err != nil || response != "ok" {
+		t.Fatalf("GenerateAI = %q, %v, want %q, nil", response, err, "ok")
+	}
+	if stub.calls != 1 {
+		t.Errorf("stub.calls = %d, want 1", stub.calls)
+	}
+}
+
+func TestWithMetricsRecordsCallsAndErrors(t *testing.T) {
+	client, metrics := WithMetrics(&stubClient{response: "ok"})
+
+	if _, err := client.GenerateAI("hi", "sys", nil); err != nil {
+		t.Fatalf("GenerateAI: %v", err)
+	}
+
+	failing, failingMetrics := WithMetrics(&stubClient{err: errors.New("boom")})
+	if _, err := failing.GenerateAI("hi", "sys", nil); err == nil {
+		t.Fatal("GenerateAI err = nil, want an error")
+	}
+
+	stats := metrics.Snapshot()["GenerateAI"]
+	if stats.Calls != 1 || stats.Errors != 0 {
+		t.Errorf("metrics stats = %+v, want 1 call, 0 errors", stats)
+	}
+
+	failingStats := failingMetrics.Snapshot()["GenerateAI"]
+	if failingStats.Calls != 1 || failingStats.Errors != 1 {
+		t.Errorf("failing metrics stats = %+v, want 1 call, 1 error", failingStats)
+	}
+}
+
+func TestApplyComposesConfiguredDecorators(t *testing.T) {
+	stub := &stubClient{err: errors.New("transient")}
+
+	client, metrics := Apply(stub, Config{
+		Recovery: true,
+		Retry:    &RetryConfig{MaxAttempts: 2, BaseDelayMs: 1, MaxDelayMs: 1},
+		Metrics:  true,
+	})
+
+	if _, err := client.GenerateAI("hi", "sys", nil); err == nil {
+		t.Fatal("GenerateAI err = nil, want an error since stub always fails")
+	}
+	if stub.calls != 2 {
+		t.Errorf("stub.calls = %d, want 2", stub.calls)
+	}
+	if metrics == nil {
+		t.Fatal("Apply with Config.Metrics = true returned nil Metrics")
+	}
+	if stats := metrics.Snapshot()["GenerateAI"]; stats.Calls != 1 {
+		t.Errorf("metrics stats.Calls = %d, want 1 (one call through the outermost decorator)", stats.Calls)
+	}
+}
+
+func TestApplyWithNoDecoratorsReturnsClientUnchanged(t *testing.T) {
+	stub := &stubClient{response: "ok"}
+
+	client, metrics := Apply(stub, Config{})
+	if metrics != nil {
+		t.Errorf("Apply with no decorators enabled returned non-nil Metrics")
+	}
+
+	response, err := client.GenerateAI("hi", "sys", nil)
+	if err != nil || response != "ok" {
+		t.Fatalf("GenerateAI = %q, %v, want %q, nil", response, err, "ok")
+	}
+}
@@ -1,15 +1,23 @@
 package cloneAttack
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"datasnack/cloneAttack/assertions"
+	"datasnack/cloneAttack/harness"
+	"datasnack/cloneAttack/vulndetect"
+	"datasnack/schema"
+	"datasnack/workflow"
 )
 
 // N8nWorkflowEvaluator handles evaluation of n8n workflows
@@ -18,17 +26,40 @@ type N8nWorkflowEvaluator struct {
 	workflowFile      string
 	agentPurpose      string
 	testConfiguration TestConfiguration
+	transport         WorkflowTransport
+	harnessConfig     *harness.Config
+	graph             *workflow.Graph
 	callHistory       []CallMetadata
 	stressTestResults *StressTestResults
+
+	// streamPath, checkpointInterval, and completed are set by
+	// WithResultsStream/WithResume; see resultsStream.go.
+	streamPath           string
+	checkpointInterval   int
+	callsSinceCheckpoint int
+	completed            map[string]int
+
+	// reportFormats is set by WithReportFormats; see exportReports.go.
+	reportFormats []string
+
+	// asserts is set by WithAssertions; see applyAssertions below.
+	asserts []assertions.Assertion
+
+	mu        sync.Mutex
+	latencies []time.Duration
 }
 
-// NewN8nWorkflowEvaluator creates a new n8n workflow evaluator
-func NewN8nWorkflowEvaluator(ai AIClient, workflowFile, agentPurpose string, testConfig TestConfiguration) *N8nWorkflowEvaluator {
+// NewN8nWorkflowEvaluator creates a new n8n workflow evaluator. transport
+// determines how generated prompts reach the workflow (webhook, REST execute,
+// SSE streaming, or a mock for tests); pass nil to default to an
+// HTTPWebhookTransport built from the webhook descriptor found in workflowFile.
+func NewN8nWorkflowEvaluator(ai AIClient, workflowFile, agentPurpose string, testConfig TestConfiguration, transport WorkflowTransport) *N8nWorkflowEvaluator {
 	return &N8nWorkflowEvaluator{
 		ai:                ai,
 		workflowFile:      workflowFile,
 		agentPurpose:      agentPurpose,
 		testConfiguration: testConfig,
+		transport:         transport,
 		callHistory:       []CallMetadata{},
 		stressTestResults: &StressTestResults{
 			Vulnerabilities:     []Vulnerability{},
@@ -39,16 +70,60 @@ func NewN8nWorkflowEvaluator(ai AIClient, workflowFile, agentPurpose string, tes
 	}
 }
 
+// WithHarnessConfig sets a declarative load-test configuration (concurrency,
+// ramp-up, duration, think-time) that runTestSuite uses to schedule its
+// generated prompts. Without one, runTestSuite runs every prompt sequentially,
+// matching the evaluator's previous behavior.
+func (e *N8nWorkflowEvaluator) WithHarnessConfig(cfg *harness.Config) *N8nWorkflowEvaluator {
+	e.harnessConfig = cfg
+	return e
+}
+
 // RunComprehensiveVulnerabilityTest runs comprehensive tests on the n8n workflow
 func (e *N8nWorkflowEvaluator) RunComprehensiveVulnerabilityTest() (*StressTestResults, error) {
 	log.Println("Starting comprehensive N8N workflow evaluation...")
 
 	e.stressTestResults.StartTime = time.Now()
 
-	// Parse workflow to get webhook URL
-	webhookURL, err := e.extractWebhookURL()
+	// Parse the full workflow graph once: it drives webhook discovery below,
+	// agentPurpose inference, targeted attack prompts, and static findings.
+	graph, err := workflow.ParseFile(e.workflowFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract webhook URL: %w", err)
+		return nil, fmt.Errorf("failed to parse workflow graph: %w", err)
+	}
+	e.graph = graph
+
+	if e.agentPurpose == "" {
+		if purpose, ok := e.graph.AgentPurpose(); ok {
+			log.Printf("Derived agent purpose from workflow AI node system prompt: %s", purpose)
+			e.agentPurpose = purpose
+		}
+	}
+
+	// Flag risky patterns in the workflow's own structure before any live
+	// test runs against it.
+	for _, finding := range e.graph.StaticFindings() {
+		e.stressTestResults.Vulnerabilities = append(e.stressTestResults.Vulnerabilities, vulnerabilityFromStaticFinding(e.graph, finding))
+	}
+
+	// Find the trigger node and build a transport for it
+	descriptor, err := e.extractWebhookURL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract webhook descriptor: %w", err)
+	}
+	if e.transport == nil {
+		e.transport = NewHTTPWebhookTransport(fmt.Sprintf("http://localhost:5678/webhook-test/%s", descriptor.Path), "", "", false)
+	}
+
+	// Run attack prompts targeted at the specific tools (SQL, HTTP) reachable
+	// downstream of each AI node, ahead of the generic generated test suites.
+	if targeted := e.graph.TargetedAttackPrompts(); len(targeted) > 0 {
+		log.Println("Running targeted tool-reachability tests...")
+		prompts := make([]string, len(targeted))
+		for i, t := range targeted {
+			prompts[i] = t.Prompt
+		}
+		e.runTestSuite("Targeted Attack", prompts, len(prompts))
 	}
 
 	// Generate and run data leakage tests
@@ -57,7 +132,7 @@ func (e *N8nWorkflowEvaluator) RunComprehensiveVulnerabilityTest() (*StressTestR
 	if err != nil {
 		log.Printf("Failed to generate data leakage prompts: %v", err)
 	} else {
-		e.runTestSuite("Data Leakage", dataLeakagePrompts, e.testConfiguration.DataLeakageTests, webhookURL)
+		e.runTestSuite("Data Leakage", dataLeakagePrompts, e.testConfiguration.DataLeakageTests)
 	}
 
 	// Generate and run prompt injection tests
@@ -66,7 +141,7 @@ func (e *N8nWorkflowEvaluator) RunComprehensiveVulnerabilityTest() (*StressTestR
 	if err != nil {
 		log.Printf("Failed to generate prompt injection prompts: %v", err)
 	} else {
-		e.runTestSuite("Prompt Injection", promptInjectionPrompts, e.testConfiguration.PromptInjectionTests, webhookURL)
+		e.runTestSuite("Prompt Injection", promptInjectionPrompts, e.testConfiguration.PromptInjectionTests)
 	}
 
 	// Generate and run consistency tests
@@ -75,9 +150,21 @@ func (e *N8nWorkflowEvaluator) RunComprehensiveVulnerabilityTest() (*StressTestR
 	if err != nil {
 		log.Printf("Failed to generate consistency prompts: %v", err)
 	} else {
-		e.runTestSuite("Consistency", consistencyPrompts, e.testConfiguration.ConsistencyTests, webhookURL)
+		e.runTestSuite("Consistency", consistencyPrompts, e.testConfiguration.ConsistencyTests)
+	}
+
+	// Evolve the prompt injection seeds through successive generations,
+	// mutating whichever prompts scored highest against the vulndetect
+	// registry instead of only ever running the initial LLM-generated batch.
+	if len(promptInjectionPrompts) > 0 {
+		log.Println("Running adaptive prompt evolution...")
+		generator := NewAdaptiveGenerator(e.ai, e.testConfiguration)
+		e.stressTestResults.PromptLineage = generator.Evolve(promptInjectionPrompts, e.scoreCandidate)
 	}
 
+	// Flag inconsistent responses across repeated runs of the same prompt
+	e.runConsistencyAnalysis()
+
 	// Calculate performance metrics
 	e.calculatePerformanceMetrics()
 
@@ -90,12 +177,20 @@ func (e *N8nWorkflowEvaluator) RunComprehensiveVulnerabilityTest() (*StressTestR
 	// Generate final recommendations
 	e.generateRecommendations()
 
+	// Check the run against any assertions loaded via WithAssertions.
+	e.applyAssertions()
+
 	e.stressTestResults.EndTime = time.Now()
 
-	// Save comprehensive results
-	if err := e.saveResults(); err != nil {
+	// Save comprehensive results, in JSON always and in any additional
+	// formats requested via WithReportFormats (sarif, junit).
+	timestamp := time.Now().Format("20060102_150405")
+	if err := e.saveResults(timestamp); err != nil {
 		log.Printf("Failed to save results: %v", err)
 	}
+	if err := e.exportReports(timestamp); err != nil {
+		log.Printf("Failed to export reports: %v", err)
+	}
 
 	log.Printf("N8N Workflow evaluation completed: %d total calls, %d successful, %d failed",
 		e.stressTestResults.TotalCalls,
@@ -105,54 +200,37 @@ func (e *N8nWorkflowEvaluator) RunComprehensiveVulnerabilityTest() (*StressTestR
 	return e.stressTestResults, nil
 }
 
-// extractWebhookURL extracts the webhook URL from the n8n workflow
-func (e *N8nWorkflowEvaluator) extractWebhookURL() (string, error) {
-	workflowData, err := os.ReadFile(e.workflowFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to read workflow file: %w", err)
-	}
-
-	var workflow map[string]interface{}
-	if err := json.Unmarshal(workflowData, &workflow); err != nil {
-		return "", fmt.Errorf("failed to parse workflow JSON: %w", err)
-	}
-
-	nodes, ok := workflow["nodes"].([]interface{})
-	if !ok {
-		return "", fmt.Errorf("workflow does not contain nodes array")
-	}
-
-	// Find webhook node
-	for _, node := range nodes {
-		nodeMap, ok := node.(map[string]interface{})
-		if !ok {
+// extractWebhookURL locates the workflow's webhook trigger node and returns a
+// structured WebhookDescriptor describing it, for a WorkflowTransport to
+// consume however it needs (base URL, API call, auth header) rather than a
+// single opinionated URL string.
+func (e *N8nWorkflowEvaluator) extractWebhookURL() (WebhookDescriptor, error) {
+	for _, n := range e.graph.Triggers() {
+		if n.Type != "n8n-nodes-base.webhook" {
 			continue
 		}
 
-		nodeType, ok := nodeMap["type"].(string)
+		path, ok := n.Parameters["path"].(string)
 		if !ok {
 			continue
 		}
 
-		if nodeType == "n8n-nodes-base.webhook" {
-			// Extract webhook path
-			parameters, ok := nodeMap["parameters"].(map[string]interface{})
-			if !ok {
-				continue
-			}
+		method, _ := n.Parameters["httpMethod"].(string)
+		if method == "" {
+			method = "POST"
+		}
 
-			path, ok := parameters["path"].(string)
-			if !ok {
-				continue
-			}
+		authType, _ := n.Parameters["authentication"].(string)
 
-			// For now, return a placeholder URL - in real implementation,
-			// this would need to be configured with actual n8n instance URL
-			return fmt.Sprintf("http://localhost:5678/webhook-test/%s", path), nil
-		}
+		return WebhookDescriptor{
+			NodeID:   n.ID,
+			Path:     path,
+			Method:   method,
+			AuthType: authType,
+		}, nil
 	}
 
-	return "", fmt.Errorf("no webhook node found in workflow")
+	return WebhookDescriptor{}, fmt.Errorf("no webhook node found in workflow")
 }
 
 // generateDataLeakagePrompts generates prompts for data leakage testing
@@ -288,46 +366,81 @@ Return only the prompts, one per line, without numbering or explanations.`, e.te
 	return cleanPrompts, nil
 }
 
-// runTestSuite runs a set of test prompts with multiple iterations
-func (e *N8nWorkflowEvaluator) runTestSuite(testType string, prompts []string, numTests int, webhookURL string) {
+// runTestSuite submits a set of test prompts, each run IterationsPerTest
+// times, as harness.Runners so the suite can be scheduled according to
+// e.harnessConfig (concurrency, ramp-up, duration, think-time). With no
+// harnessConfig set it falls back to a sequential Strategy, matching the
+// evaluator's original one-at-a-time behavior.
+func (e *N8nWorkflowEvaluator) runTestSuite(testType string, prompts []string, numTests int) {
 	log.Printf("Running %s test suite with %d tests", testType, numTests)
 
+	var runners []harness.Runner
 	for i := 0; i < numTests && i < len(prompts); i++ {
 		prompt := prompts[i]
 		log.Printf("Testing %s scenario %d: %s", testType, i+1, prompt[:min(len(prompt), 50)])
 
-		// Run multiple iterations of each test scenario
+		// On a --resume run, skip iterations already recorded in the
+		// results stream for this exact (testType, prompt) pair.
+		alreadyDone := e.completed[testType+"|"+prompt]
+
 		for j := 0; j < e.testConfiguration.IterationsPerTest; j++ {
-			callMetadata, err := e.runSingleTestScenario(prompt, testType, webhookURL)
-			if err != nil {
-				log.Printf("Test scenario failed: %v", err)
+			if j < alreadyDone {
 				continue
 			}
 
-			e.callHistory = append(e.callHistory, *callMetadata)
-			e.stressTestResults.TotalCalls++
-
-			if callMetadata.Success {
-				e.stressTestResults.SuccessfulCalls++
-			} else {
-				e.stressTestResults.FailedCalls++
-			}
+			runners = append(runners, harness.RunnerFunc(func(ctx context.Context, id string, logs io.Writer) error {
+				callMetadata, err := e.runSingleTestScenario(prompt, testType)
+				if err != nil {
+					return err
+				}
+
+				e.mu.Lock()
+				e.callHistory = append(e.callHistory, *callMetadata)
+				e.stressTestResults.TotalCalls++
+				if callMetadata.Success {
+					e.stressTestResults.SuccessfulCalls++
+				} else {
+					e.stressTestResults.FailedCalls++
+				}
+				e.stressTestResults.Vulnerabilities = append(e.stressTestResults.Vulnerabilities, callMetadata.Vulnerabilities...)
+				if err := e.recordCall(*callMetadata); err != nil {
+					log.Printf("Failed to stream call result: %v", err)
+				}
+				e.mu.Unlock()
+
+				return nil
+			}))
+		}
+	}
 
-			// Add vulnerabilities to results
-			e.stressTestResults.Vulnerabilities = append(e.stressTestResults.Vulnerabilities, callMetadata.Vulnerabilities...)
+	strategy := harness.Strategy{Concurrency: 1}
+	if e.harnessConfig != nil {
+		var err error
+		strategy, err = e.harnessConfig.Strategy()
+		if err != nil {
+			log.Printf("Invalid harness config, falling back to sequential execution: %v", err)
+			strategy = harness.Strategy{Concurrency: 1}
 		}
 	}
+
+	result := strategy.Run(context.Background(), runners, io.Discard)
+
+	e.mu.Lock()
+	for _, run := range result.Runs {
+		e.latencies = append(e.latencies, run.Duration())
+	}
+	e.mu.Unlock()
 }
 
 // runSingleTestScenario runs a single test scenario against the n8n workflow
-func (e *N8nWorkflowEvaluator) runSingleTestScenario(testScenario, testType, webhookURL string) (*CallMetadata, error) {
+func (e *N8nWorkflowEvaluator) runSingleTestScenario(testScenario, testType string) (*CallMetadata, error) {
 	callID := fmt.Sprintf("n8n-%d", time.Now().UnixNano())
 	startTime := time.Now()
 
 	log.Printf("Testing N8N workflow scenario: %s", testScenario[:min(len(testScenario), 50)])
 
-	// Call the n8n workflow via webhook
-	response, err := e.callN8nWorkflow(testScenario, webhookURL)
+	// Call the n8n workflow via the configured transport
+	response, err := e.callN8nWorkflow(testScenario)
 	executionTime := time.Since(startTime).Seconds() * 1000 // Convert to milliseconds
 
 	callMetadata := &CallMetadata{
@@ -356,93 +469,171 @@ func (e *N8nWorkflowEvaluator) runSingleTestScenario(testScenario, testType, web
 	return callMetadata, nil
 }
 
-// callN8nWorkflow calls the n8n workflow via webhook
-func (e *N8nWorkflowEvaluator) callN8nWorkflow(prompt, webhookURL string) (string, error) {
-	// Prepare the request payload
-	payload := map[string]interface{}{
-		"query": prompt,
-		"input": prompt,
-	}
-
-	jsonData, err := json.Marshal(payload)
+// scoreCandidate runs a single AdaptiveGenerator candidate the same way
+// runTestSuite runs a generated prompt — recording it in callHistory and the
+// result tallies — and returns its highest vulndetect finding score as the
+// candidate's fitness, along with the CallID for lineage tracking.
+func (e *N8nWorkflowEvaluator) scoreCandidate(prompt string) (float64, string, error) {
+	callMetadata, err := e.runSingleTestScenario(prompt, "Adaptive Evolution")
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request payload: %w", err)
+		return 0, "", err
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	e.mu.Lock()
+	e.callHistory = append(e.callHistory, *callMetadata)
+	e.stressTestResults.TotalCalls++
+	if callMetadata.Success {
+		e.stressTestResults.SuccessfulCalls++
+	} else {
+		e.stressTestResults.FailedCalls++
 	}
+	e.stressTestResults.Vulnerabilities = append(e.stressTestResults.Vulnerabilities, callMetadata.Vulnerabilities...)
+	if err := e.recordCall(*callMetadata); err != nil {
+		log.Printf("Failed to stream call result: %v", err)
+	}
+	e.mu.Unlock()
 
-	req.Header.Set("Content-Type", "application/json")
+	var best float64
+	for _, v := range callMetadata.Vulnerabilities {
+		if v.Score > best {
+			best = v.Score
+		}
+	}
+	return best, callMetadata.CallID, nil
+}
 
-	// Make the request
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// callN8nWorkflow calls the n8n workflow through the configured WorkflowTransport
+func (e *N8nWorkflowEvaluator) callN8nWorkflow(prompt string) (string, error) {
+	payload := map[string]interface{}{
+		"query": prompt,
+		"input": prompt,
 	}
 
-	resp, err := client.Do(req)
+	resp, err := e.transport.Invoke(context.Background(), payload)
 	if err != nil {
 		return "", fmt.Errorf("failed to call N8N workflow: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+	if resp.StatusCode != 0 && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("N8N workflow returned status %d: %s", resp.StatusCode, resp.Body)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("N8N workflow returned status %d: %s", resp.StatusCode, string(body))
+	if resp.Body == "" {
+		return "", fmt.Errorf("empty response from N8N workflow")
 	}
 
-	response := strings.TrimSpace(string(body))
-	if response == "" {
-		return "", fmt.Errorf("empty response from N8N workflow")
+	if drifts, err := schema.ValidateJSON(schema.EvaluationResponseSchema(), []byte(resp.Body)); err == nil && len(drifts) > 0 {
+		log.Printf("N8N workflow response drifted from the expected evaluation schema: %v", drifts)
 	}
 
-	return response, nil
+	return resp.Body, nil
 }
 
-// detectVulnerabilitiesInResponse analyzes the response for vulnerabilities
+// detectVulnerabilitiesInResponse runs the vulndetect registry (instruction
+// override, credential exfiltration, indirect injection) against a single
+// prompt/response pair. Consistency findings, which need every response to
+// the same prompt rather than just one, are computed separately by
+// runConsistencyAnalysis once a full test suite has completed.
 func (e *N8nWorkflowEvaluator) detectVulnerabilitiesInResponse(prompt, response, callID, testType string) []Vulnerability {
-	// Use the same vulnerability detection logic as the main evaluator
-	// This would typically call the same AI-powered analysis functions
-	// For now, return basic analysis
-	var vulnerabilities []Vulnerability
-
-	// Basic keyword-based analysis
-	switch testType {
-	case "Data Leakage":
-		if strings.Contains(strings.ToLower(response), "password") ||
-			strings.Contains(strings.ToLower(response), "secret") ||
-			strings.Contains(strings.ToLower(response), "confidential") {
-
-			// Truncate response and prompt
-			truncatedResponse := response
-			if len(response) > 150 {
-				truncatedResponse = response[:147] + "..."
-			}
-			truncatedPrompt := prompt
-			if len(prompt) > 150 {
-				truncatedPrompt = prompt[:147] + "..."
-			}
+	findings := vulndetect.DetectAll(vulndetect.Input{Prompt: prompt, Response: response})
 
-			vulnerabilities = append(vulnerabilities, Vulnerability{
-				Type:        "Data Leakage",
-				Severity:    "High",
-				Description: "Potential sensitive information exposure detected in N8N workflow response",
-				CallID:      callID,
-				Score:       0.8,
-				Response:    truncatedResponse,
-				Prompt:      truncatedPrompt,
-			})
+	vulnerabilities := make([]Vulnerability, 0, len(findings))
+	for _, f := range findings {
+		vulnerabilities = append(vulnerabilities, vulnerabilityFromFinding(f, callID, prompt, response))
+	}
+	return vulnerabilities
+}
+
+// runConsistencyAnalysis groups the call history by prompt and flags any
+// response whose similarity to the other responses for that same prompt
+// falls below ConsistencyDetector's threshold.
+func (e *N8nWorkflowEvaluator) runConsistencyAnalysis() {
+	byPrompt := make(map[string][]CallMetadata)
+	for _, call := range e.callHistory {
+		if !call.Success {
+			continue
 		}
+		byPrompt[call.InputPrompt] = append(byPrompt[call.InputPrompt], call)
 	}
 
-	return vulnerabilities
+	detector := vulndetect.ConsistencyDetector{}
+	for prompt, calls := range byPrompt {
+		if len(calls) < 2 {
+			continue
+		}
+
+		responses := make([]string, len(calls))
+		for i, call := range calls {
+			responses[i] = call.AgentResponse
+		}
+
+		for i, call := range calls {
+			prior := make([]string, 0, len(responses)-1)
+			prior = append(prior, responses[:i]...)
+			prior = append(prior, responses[i+1:]...)
+
+			findings := detector.Detect(vulndetect.Input{Prompt: prompt, Response: call.AgentResponse, PriorResponses: prior})
+			for _, f := range findings {
+				e.stressTestResults.Vulnerabilities = append(e.stressTestResults.Vulnerabilities, vulnerabilityFromFinding(f, call.CallID, prompt, call.AgentResponse))
+			}
+		}
+	}
+}
+
+// vulnerabilityFromFinding converts a vulndetect.Finding into the
+// evaluator's Vulnerability type, truncating the prompt/response for
+// storage the same way the rest of this package does.
+func vulnerabilityFromFinding(f vulndetect.Finding, callID, prompt, response string) Vulnerability {
+	return Vulnerability{
+		Type:        f.Type,
+		Severity:    f.Severity,
+		Description: f.Description,
+		CallID:      callID,
+		Score:       f.Score,
+		Response:    truncateForReport(response),
+		Prompt:      truncateForReport(prompt),
+		CWE:         f.CWE,
+		OWASPLLM:    f.OWASPLLM,
+	}
+}
+
+// staticFindingCWE and staticFindingOWASPLLM classify a workflow.StaticFinding
+// by its Type: each flags raw LLM/upstream input reaching a node that acts on
+// it without sanitization, which OWASP's 2025 LLM Top-10 files under
+// improper output handling.
+var staticFindingCWE = map[string]string{
+	"sql_injection":  "CWE-89",
+	"code_injection": "CWE-94",
+	"ssrf":           "CWE-918",
+}
+
+const staticFindingOWASPLLM = "LLM05:2025 Improper Output Handling"
+
+// vulnerabilityFromStaticFinding converts a workflow.StaticFinding — flagged
+// from the workflow's own node parameters, before any live test runs — into
+// the evaluator's Vulnerability type. graph.Reachable checks whether an
+// untrusted trigger can actually reach the flagged node, so a finding on a
+// node the workflow never routes user input to doesn't get the same weight
+// as one directly in the attack path.
+func vulnerabilityFromStaticFinding(graph *workflow.Graph, f workflow.StaticFinding) Vulnerability {
+	reachable, path := graph.Reachable(f.NodeName)
+	return Vulnerability{
+		Type:        f.Type,
+		Severity:    f.Severity,
+		Description: f.Description,
+		CWE:         staticFindingCWE[f.Type],
+		OWASPLLM:    staticFindingOWASPLLM,
+		NodeID:      f.NodeName,
+		Reachable:   reachable,
+		Path:        path,
+	}
+}
+
+func truncateForReport(s string) string {
+	if len(s) > 150 {
+		return s[:147] + "..."
+	}
+	return s
 }
 
 // calculatePerformanceMetrics calculates performance metrics
@@ -465,11 +656,16 @@ func (e *N8nWorkflowEvaluator) calculatePerformanceMetrics() {
 		}
 	}
 
+	p50, p95, p99 := harness.Percentiles(e.latencies)
+
 	e.stressTestResults.PerformanceMetrics = map[string]interface{}{
 		"max_response_time":    maxTime,
 		"min_response_time":    minTime,
 		"success_rate":         float64(e.stressTestResults.SuccessfulCalls) / float64(e.stressTestResults.TotalCalls),
 		"total_execution_time": totalTime,
+		"p50_latency_ms":       float64(p50.Microseconds()) / 1000,
+		"p95_latency_ms":       float64(p95.Microseconds()) / 1000,
+		"p99_latency_ms":       float64(p99.Microseconds()) / 1000,
 	}
 }
 
@@ -497,18 +693,44 @@ func (e *N8nWorkflowEvaluator) analyzeVulnerabilities() {
 	}
 }
 
-// optimizePrompts generates prompt optimizations
+// optimizePrompts derives prompt optimizations from the AdaptiveGenerator
+// lineage recorded in PromptLineage: for every bred candidate that scored
+// higher than the parent it mutated, that mutation is reported as a
+// successful optimization, ranked by how much it improved on its parent.
 func (e *N8nWorkflowEvaluator) optimizePrompts() {
-	// Generate prompt optimizations based on results
-	// This would typically use AI to analyze patterns and suggest improvements
-	e.stressTestResults.PromptOptimizations = []PromptOptimization{
-		{
-			OriginalPrompt:  "Generic test prompt",
-			OptimizedPrompt: "Enhanced test prompt with better security measures",
-			Reasoning:       "Based on vulnerability analysis",
-			PerformanceGain: 0.1,
-		},
+	scoreByPrompt := make(map[string]float64, len(e.stressTestResults.PromptLineage))
+	for _, entry := range e.stressTestResults.PromptLineage {
+		scoreByPrompt[entry.Prompt] = entry.Score
 	}
+
+	var optimizations []PromptOptimization
+	for _, entry := range e.stressTestResults.PromptLineage {
+		if len(entry.ParentPrompts) == 0 {
+			continue
+		}
+		parentScore, ok := scoreByPrompt[entry.ParentPrompts[0]]
+		if !ok {
+			continue
+		}
+		gain := entry.Score - parentScore
+		if gain <= 0 {
+			continue
+		}
+		optimizations = append(optimizations, PromptOptimization{
+			OriginalPrompt:   entry.ParentPrompts[0],
+			OptimizedPrompt:  entry.Prompt,
+			ImprovementScore: entry.Score,
+			Reasoning:        fmt.Sprintf("generation %d mutation (%s) scored higher against the vulndetect registry than its parent", entry.Generation, entry.Mutation),
+			PerformanceGain:  gain,
+		})
+	}
+
+	sort.Slice(optimizations, func(i, j int) bool { return optimizations[i].PerformanceGain > optimizations[j].PerformanceGain })
+	if len(optimizations) > 10 {
+		optimizations = optimizations[:10]
+	}
+
+	e.stressTestResults.PromptOptimizations = optimizations
 }
 
 // generateRecommendations generates final recommendations
@@ -523,8 +745,7 @@ func (e *N8nWorkflowEvaluator) generateRecommendations() {
 }
 
 // saveResults saves the evaluation results
-func (e *N8nWorkflowEvaluator) saveResults() error {
-	timestamp := time.Now().Format("20060102_150405")
+func (e *N8nWorkflowEvaluator) saveResults(timestamp string) error {
 	filename := fmt.Sprintf("results/n8n_evaluation_results_%s.json", timestamp)
 
 	// Ensure results directory exists
@@ -0,0 +1,250 @@
+package cloneAttack
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Response is the result of a single WorkflowTransport.Invoke call.
+type Response struct {
+	Body       string
+	StatusCode int
+	Latency    time.Duration
+}
+
+// Chunk is one piece of a streaming WorkflowTransport.Stream response. Done is
+// set on the final value sent on the channel, possibly alongside the last Data.
+type Chunk struct {
+	Data string
+	Err  error
+	Done bool
+}
+
+// WorkflowTransport abstracts how a test payload reaches an n8n workflow, so
+// N8nWorkflowEvaluator can swap webhook calls for REST execute calls or
+// chat-trigger streaming without changing its test-generation or
+// vulnerability-detection logic.
+type WorkflowTransport interface {
+	Invoke(ctx context.Context, payload map[string]interface{}) (Response, error)
+	Stream(ctx context.Context, payload map[string]interface{}) (<-chan Chunk, error)
+}
+
+// WebhookDescriptor is what extractWebhookURL now returns: enough structured
+// information about a trigger node for any WorkflowTransport to decide how to
+// call it, rather than a single opinionated URL string.
+type WebhookDescriptor struct {
+	NodeID   string
+	Path     string
+	Method   string
+	AuthType string // "", "header", "apiKey"
+}
+
+// HTTPWebhookTransport calls an n8n webhook-trigger workflow over HTTP(S).
+type HTTPWebhookTransport struct {
+	BaseURL     string
+	AuthHeader  string // e.g. "Authorization"; empty means no auth header is sent
+	AuthValue   string
+	InsecureTLS bool
+
+	client *http.Client
+}
+
+// NewHTTPWebhookTransport builds an HTTPWebhookTransport for the webhook at
+// baseURL. authHeader/authValue are optional; insecureTLS skips certificate
+// verification for self-signed n8n deployments.
+func NewHTTPWebhookTransport(baseURL, authHeader, authValue string, insecureTLS bool) *HTTPWebhookTransport {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if insecureTLS {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return &HTTPWebhookTransport{
+		BaseURL:     baseURL,
+		AuthHeader:  authHeader,
+		AuthValue:   authValue,
+		InsecureTLS: insecureTLS,
+		client:      client,
+	}
+}
+
+func (t *HTTPWebhookTransport) newRequest(ctx context.Context, payload map[string]interface{}) (*http.Request, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.BaseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.AuthHeader != "" {
+		req.Header.Set(t.AuthHeader, t.AuthValue)
+	}
+	return req, nil
+}
+
+func (t *HTTPWebhookTransport) Invoke(ctx context.Context, payload map[string]interface{}) (Response, error) {
+	req, err := t.newRequest(ctx, payload)
+	if err != nil {
+		return Response{}, err
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to call n8n webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return Response{
+		Body:       strings.TrimSpace(string(body)),
+		StatusCode: resp.StatusCode,
+		Latency:    time.Since(start),
+	}, nil
+}
+
+// Stream calls the webhook with Accept: text/event-stream and relays each
+// "data:" line as a Chunk, for chat-trigger workflows that stream tokens.
+func (t *HTTPWebhookTransport) Stream(ctx context.Context, payload map[string]interface{}) (<-chan Chunk, error) {
+	req, err := t.newRequest(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call n8n webhook: %w", err)
+	}
+
+	chunks := make(chan Chunk)
+	go streamSSEBody(resp.Body, chunks)
+	return chunks, nil
+}
+
+// streamSSEBody reads an SSE/chunked HTTP response body line by line, emitting
+// one Chunk per "data:" line, and closes chunks once the body is exhausted.
+func streamSSEBody(body io.ReadCloser, chunks chan<- Chunk) {
+	defer close(chunks)
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			chunks <- Chunk{Done: true}
+			return
+		}
+		chunks <- Chunk{Data: data}
+	}
+	if err := scanner.Err(); err != nil {
+		chunks <- Chunk{Err: err}
+	}
+}
+
+// RESTExecuteTransport calls n8n's REST API to execute a workflow by ID
+// (POST /workflows/:id/execute) using an API key, for deployments that run
+// workflows directly rather than exposing a production webhook URL.
+type RESTExecuteTransport struct {
+	BaseURL    string
+	WorkflowID string
+	APIKey     string
+
+	client *http.Client
+}
+
+// NewRESTExecuteTransport builds a RESTExecuteTransport against n8n's REST
+// API at baseURL for the given workflow ID.
+func NewRESTExecuteTransport(baseURL, workflowID, apiKey string) *RESTExecuteTransport {
+	return &RESTExecuteTransport{
+		BaseURL:    baseURL,
+		WorkflowID: workflowID,
+		APIKey:     apiKey,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (t *RESTExecuteTransport) url() string {
+	return fmt.Sprintf("%s/workflows/%s/execute", strings.TrimRight(t.BaseURL, "/"), t.WorkflowID)
+}
+
+func (t *RESTExecuteTransport) Invoke(ctx context.Context, payload map[string]interface{}) (Response, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{"workflowData": payload})
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to marshal execute payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-N8N-API-KEY", t.APIKey)
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to execute n8n workflow: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return Response{
+		Body:       strings.TrimSpace(string(body)),
+		StatusCode: resp.StatusCode,
+		Latency:    time.Since(start),
+	}, nil
+}
+
+// Stream is unsupported: n8n's execute-workflow REST endpoint is request/response only.
+func (t *RESTExecuteTransport) Stream(ctx context.Context, payload map[string]interface{}) (<-chan Chunk, error) {
+	return nil, fmt.Errorf("RESTExecuteTransport does not support streaming")
+}
+
+// MockTransport is a WorkflowTransport for tests: it cycles through canned
+// responses/chunks instead of making any network calls.
+type MockTransport struct {
+	Responses []Response
+	Chunks    []Chunk
+
+	calls int
+}
+
+func (t *MockTransport) Invoke(ctx context.Context, payload map[string]interface{}) (Response, error) {
+	if len(t.Responses) == 0 {
+		return Response{}, fmt.Errorf("mock transport has no configured responses")
+	}
+	resp := t.Responses[t.calls%len(t.Responses)]
+	t.calls++
+	return resp, nil
+}
+
+func (t *MockTransport) Stream(ctx context.Context, payload map[string]interface{}) (<-chan Chunk, error) {
+	chunks := make(chan Chunk, len(t.Chunks))
+	for _, c := range t.Chunks {
+		chunks <- c
+	}
+	close(chunks)
+	return chunks, nil
+}
@@ -0,0 +1,183 @@
+// Package osvfeed loads curated prompt-injection/jailbreak payload corpora
+// (HackAPrompt, Garak probes, OWASP LLM Top-10 samples, etc.) kept as
+// versioned data files in an OSV-like JSON schema, so they can be refreshed
+// and interleaved with cloneAttack's AI-generated test prompts instead of
+// being hardcoded into the binary. A source is either a local directory of
+// one *.json advisory per file, or an HTTPS URL serving a JSON array of
+// entries; HTTP sources are re-fetched with conditional GETs (ETag) so a
+// periodic refresh only pays the cost of an actual change.
+package osvfeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Package identifies the affected software in an Affected entry, following
+// OSV's {ecosystem, name} convention.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// Affected names one package/ecosystem an Entry's payload targets, mirroring
+// OSV's "affected" array.
+type Affected struct {
+	Package Package `json:"package"`
+}
+
+// Reference is a link to further detail about an Entry, following OSV's
+// {type, url} convention (e.g. type "ADVISORY", "ARTICLE", "WEB").
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Entry is one advisory in an OSV-style payload feed, extended with two
+// fields this package's callers care about: PromptPayload (the literal
+// attack prompt to run against an agent) and AttackClass (a free-form
+// category like "prompt_injection" or "jailbreak", matched case-insensitively
+// by Feed callers filtering down to a subset of entries).
+type Entry struct {
+	ID         string      `json:"id"`
+	Summary    string      `json:"summary"`
+	Details    string      `json:"details"`
+	Affected   []Affected  `json:"affected,omitempty"`
+	References []Reference `json:"references,omitempty"`
+
+	PromptPayload string `json:"prompt_payload"`
+	AttackClass   string `json:"attack_class"`
+}
+
+// Feed is a refreshable handle onto one payload source, either a local
+// directory or an HTTPS URL. Construct one with NewFeed and call Entries to
+// read its current contents, refreshing automatically once refreshInterval
+// has elapsed since the last successful load.
+type Feed struct {
+	source          string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mu        sync.Mutex
+	entries   []Entry
+	etag      string
+	lastFetch time.Time
+}
+
+// NewFeed builds a Feed reading from source, which is either a local
+// directory path or an "http://"/"https://" URL. refreshInterval is the
+// minimum time between re-fetches from Entries; <= 0 means "fetch once and
+// never refresh".
+func NewFeed(source string, refreshInterval time.Duration) *Feed {
+	return &Feed{
+		source:          source,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Entries returns f's current entries, refreshing from source first if this
+// is the first call or refreshInterval has elapsed since the last successful
+// fetch. A failed refresh logs nothing itself. If nothing has been loaded
+// yet it returns the error; otherwise it returns the previously-loaded
+// entries with a nil error, so a transient outage doesn't empty out an
+// already-primed feed.
+func (f *Feed) Entries(ctx context.Context) ([]Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.lastFetch.IsZero() || (f.refreshInterval > 0 && time.Since(f.lastFetch) >= f.refreshInterval) {
+		if err := f.refreshLocked(ctx); err != nil {
+			if f.lastFetch.IsZero() {
+				return nil, err
+			}
+			return append([]Entry(nil), f.entries...), nil
+		}
+	}
+
+	return append([]Entry(nil), f.entries...), nil
+}
+
+func (f *Feed) refreshLocked(ctx context.Context) error {
+	if strings.HasPrefix(f.source, "http://") || strings.HasPrefix(f.source, "https://") {
+		return f.refreshHTTPLocked(ctx)
+	}
+	return f.refreshDirLocked()
+}
+
+func (f *Feed) refreshHTTPLocked(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.source, nil)
+	if err != nil {
+		return fmt.Errorf("osvfeed: failed to create request for %s: %w", f.source, err)
+	}
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("osvfeed: failed to fetch %s: %w", f.source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		f.lastFetch = time.Now()
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("osvfeed: %s returned status %d", f.source, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("osvfeed: failed to read response from %s: %w", f.source, err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return fmt.Errorf("osvfeed: failed to parse response from %s: %w", f.source, err)
+	}
+
+	f.entries = entries
+	f.etag = resp.Header.Get("ETag")
+	f.lastFetch = time.Now()
+	return nil
+}
+
+func (f *Feed) refreshDirLocked() error {
+	dirEntries, err := os.ReadDir(f.source)
+	if err != nil {
+		return fmt.Errorf("osvfeed: failed to read directory %s: %w", f.source, err)
+	}
+
+	var entries []Entry
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(f.source, dirEntry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("osvfeed: failed to read %s: %w", path, err)
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("osvfeed: failed to parse %s: %w", path, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	f.entries = entries
+	f.lastFetch = time.Now()
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package outputwriter renders a *cloneAttack.StressTestResults run into the
+// body of a single pull-request review comment, the same shape frogbot and
+// similar CI security bots post back to a PR, and posts (or updates) that
+// comment via a small VCSClient abstraction so adapters for go-github,
+// go-gitlab, etc. can be written without pulling those SDKs into this
+// package. A hidden HTML marker embedded in the comment body lets
+// PostPRComments find and overwrite its own previous comment on repeated CI
+// runs instead of flooding the PR with a new one every time.
+package outputwriter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"datasnack/cloneAttack"
+)
+
+// commentMarker is the hidden HTML marker every Writer embeds in its
+// comment body, so PostPRComments can recognize and update a comment it
+// posted on a previous run.
+const commentMarker = "<!-- datasnack-ai:vulnerability-report -->"
+
+// Writer renders a StressTestResults run into the body of a single PR
+// comment. StandardWriter and SimplifiedWriter are the two built-in
+// implementations; both embed commentMarker so PostPRComments can find
+// their output on a later run regardless of which Writer produced it.
+type Writer interface {
+	WriteComment(results *cloneAttack.StressTestResults) string
+}
+
+// Repo identifies a repository on whatever VCS host vcsClient talks to.
+type Repo struct {
+	Owner string
+	Name  string
+}
+
+// Comment is the subset of a PR comment PostPRComments needs to find and
+// update its own previous comment.
+type Comment struct {
+	ID   int64
+	Body string
+}
+
+// VCSClient abstracts the handful of operations PostPRComments needs from a
+// hosting provider's PR comment API. Implementations wrap a concrete SDK
+// such as go-github or go-gitlab/go-gitlab.
+type VCSClient interface {
+	// ListPRComments returns every comment currently on pr, so
+	// PostPRComments can find one it posted on a previous run.
+	ListPRComments(ctx context.Context, repo Repo, pr int) ([]Comment, error)
+
+	// UpdateComment overwrites the body of an existing comment.
+	UpdateComment(ctx context.Context, repo Repo, commentID int64, body string) error
+
+	// CreateComment posts a new PR-level comment.
+	CreateComment(ctx context.Context, repo Repo, pr int, body string) error
+}
+
+// PostPRComments renders results with w and posts the result as a comment
+// on pr in repo via vcsClient. If a previous comment carrying w's
+// commentMarker already exists, it's updated in place; otherwise a new
+// comment is created. This keeps repeated CI runs against the same PR down
+// to one comment instead of one per run.
+func PostPRComments(ctx context.Context, vcsClient VCSClient, repo Repo, pr int, w Writer, results *cloneAttack.StressTestResults) error {
+	body := w.WriteComment(results)
+
+	comments, err := vcsClient.ListPRComments(ctx, repo, pr)
+	if err != nil {
+		return fmt.Errorf("outputwriter: failed to list PR comments: %w", err)
+	}
+
+	for _, c := range comments {
+		if strings.Contains(c.Body, commentMarker) {
+			if err := vcsClient.UpdateComment(ctx, repo, c.ID, body); err != nil {
+				return fmt.Errorf("outputwriter: failed to update PR comment %d: %w", c.ID, err)
+			}
+			return nil
+		}
+	}
+
+	if err := vcsClient.CreateComment(ctx, repo, pr, body); err != nil {
+		return fmt.Errorf("outputwriter: failed to create PR comment: %w", err)
+	}
+	return nil
+}
+
+// severityOrder ranks severities for sorting findings worst-first; unknown
+// severities sort last, after "Info".
+var severityOrder = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"info":     4,
+}
+
+func severityRank(severity string) int {
+	if rank, ok := severityOrder[strings.ToLower(severity)]; ok {
+		return rank
+	}
+	return len(severityOrder)
+}
+
+// applicabilityLabel renders a Vulnerability.Applicability value for
+// display, with a neutral label for findings that predate applicability
+// scoring (empty string).
+func applicabilityLabel(applicability string) string {
+	switch applicability {
+	case cloneAttack.ApplicabilityApplicable:
+		return "applicable"
+	case cloneAttack.ApplicabilityNotApplicable:
+		return "not applicable"
+	case cloneAttack.ApplicabilityUndetermined:
+		return "undetermined"
+	default:
+		return "not reviewed"
+	}
+}
+
+// truncate shortens s to max characters, same convention as the rest of
+// cloneAttack's "...(truncated)" snippets: short enough to keep a PR
+// comment readable without hiding the key evidence.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "... (truncated)"
+}
+
+// severityCounts tallies vulnerabilities by severity, for a summary table.
+func severityCounts(vulnerabilities []cloneAttack.Vulnerability) map[string]int {
+	counts := make(map[string]int)
+	for _, v := range vulnerabilities {
+		counts[v.Severity]++
+	}
+	return counts
+}
@@ -0,0 +1,83 @@
+package outputwriter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"datasnack/cloneAttack"
+)
+
+func TestStandardWriterNoVulnerabilitiesSummary(t *testing.T) {
+	body := StandardWriter{}.WriteComment(&cloneAttack.StressTestResults{})
+	if !strings.Contains(body, "No vulnerabilities found") {
+		t.Errorf("WriteComment() = %q, want a no-vulnerabilities summary", body)
+	}
+	if !strings.Contains(body, commentMarker) {
+		t.Errorf("WriteComment() missing commentMarker")
+	}
+}
+
+func TestSimplifiedWriterIncludesFindingDetails(t *testing.T) {
+	results := &cloneAttack.StressTestResults{
+		Vulnerabilities: []cloneAttack.Vulnerability{
+			{Type: "Data Leakage", Severity: "High", Description: "leaked a secret", Prompt: "p", Response: "r"},
+		},
+	}
+	body := SimplifiedWriter{}.WriteComment(results)
+	for _, want := range []string{"Data Leakage", "High", "leaked a secret"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("WriteComment() missing %q, got: %s", want, body)
+		}
+	}
+}
+
+// stubVCSClient is a minimal in-memory VCSClient for exercising
+// PostPRComments without a real go-github/go-gitlab backend.
+type stubVCSClient struct {
+	comments []Comment
+	nextID   int64
+	updated  map[int64]string
+}
+
+func (s *stubVCSClient) ListPRComments(ctx context.Context, repo Repo, pr int) ([]Comment, error) {
+	return s.comments, nil
+}
+
+func (s *stubVCSClient) UpdateComment(ctx context.Context, repo Repo, commentID int64, body string) error {
+	if s.updated == nil {
+		s.updated = make(map[int64]string)
+	}
+	s.updated[commentID] = body
+	return nil
+}
+
+func (s *stubVCSClient) CreateComment(ctx context.Context, repo Repo, pr int, body string) error {
+	s.nextID++
+	s.comments = append(s.comments, Comment{ID: s.nextID, Body: body})
+	return nil
+}
+
+func TestPostPRCommentsCreatesThenUpdatesInPlace(t *testing.T) {
+	client := &stubVCSClient{}
+	repo := Repo{Owner: "o", Name: "r"}
+	results := &cloneAttack.StressTestResults{}
+
+	if err := PostPRComments(context.Background(), client, repo, 1, StandardWriter{}, results); err != nil {
+		t.Fatalf("PostPRComments() first call error = %v", err)
+	}
+	if len(client.comments) != 1 {
+		t.Fatalf("len(client.comments) = %d, want 1 after first run", len(client.comments))
+	}
+
+	results.Vulnerabilities = []cloneAttack.Vulnerability{{Type: "X", Severity: "Low"}}
+	if err := PostPRComments(context.Background(), client, repo, 1, StandardWriter{}, results); err != nil {
+		t.Fatalf("PostPRComments() second call error = %v", err)
+	}
+	if len(client.comments) != 1 {
+		t.Errorf("len(client.comments) = %d, want still 1 after second run (should update, not create)", len(client.comments))
+	}
+	if !strings.Contains(client.updated[1], "X") {
+		t.Errorf("updated comment = %q, want it to reflect the new findings", client.updated[1])
+	}
+}
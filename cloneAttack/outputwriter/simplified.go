@@ -0,0 +1,39 @@
+package outputwriter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"datasnack/cloneAttack"
+)
+
+// SimplifiedWriter renders plain text with no HTML or markdown tables, for
+// VCS providers whose PR comments don't render collapsible <details>
+// blocks. StandardWriter is the richer alternative where that's supported.
+type SimplifiedWriter struct{}
+
+func (SimplifiedWriter) WriteComment(results *cloneAttack.StressTestResults) string {
+	var b strings.Builder
+	b.WriteString(commentMarker + "\n")
+	b.WriteString("AI Agent Vulnerability Report\n\n")
+
+	if len(results.Vulnerabilities) == 0 {
+		b.WriteString("No vulnerabilities found in this run.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d finding(s), %d applicable.\n\n",
+		len(results.Vulnerabilities), len(results.ApplicableVulnerabilities))
+
+	findings := append([]cloneAttack.Vulnerability(nil), results.Vulnerabilities...)
+	sort.SliceStable(findings, func(i, j int) bool { return severityRank(findings[i].Severity) < severityRank(findings[j].Severity) })
+
+	for _, v := range findings {
+		fmt.Fprintf(&b, "- [%s] %s (%s): %s\n", v.Severity, v.Type, applicabilityLabel(v.Applicability), v.Description)
+		fmt.Fprintf(&b, "  Prompt: %s\n", truncate(v.Prompt, 200))
+		fmt.Fprintf(&b, "  Response: %s\n\n", truncate(v.Response, 200))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package outputwriter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"datasnack/cloneAttack"
+)
+
+// StandardWriter renders GitHub-flavored markdown: a severity summary
+// table followed by one collapsible <details> block per finding, each
+// holding the truncated prompt/response as fenced code snippets. Suited to
+// providers that render HTML inside markdown (GitHub, GitLab); for ones
+// that don't, use SimplifiedWriter instead.
+type StandardWriter struct{}
+
+func (StandardWriter) WriteComment(results *cloneAttack.StressTestResults) string {
+	var b strings.Builder
+	b.WriteString(commentMarker + "\n")
+	b.WriteString("## 🛡️ AI Agent Vulnerability Report\n\n")
+
+	if len(results.Vulnerabilities) == 0 {
+		b.WriteString("No vulnerabilities found in this run. ✅\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("%d finding(s), %d applicable.\n\n",
+		len(results.Vulnerabilities), len(results.ApplicableVulnerabilities)))
+
+	b.WriteString("| Severity | Count |\n")
+	b.WriteString("|---|---|\n")
+	counts := severityCounts(results.Vulnerabilities)
+	severities := make([]string, 0, len(counts))
+	for severity := range counts {
+		severities = append(severities, severity)
+	}
+	sort.Slice(severities, func(i, j int) bool { return severityRank(severities[i]) < severityRank(severities[j]) })
+	for _, severity := range severities {
+		fmt.Fprintf(&b, "| %s | %d |\n", severity, counts[severity])
+	}
+	b.WriteString("\n")
+
+	findings := append([]cloneAttack.Vulnerability(nil), results.Vulnerabilities...)
+	sort.SliceStable(findings, func(i, j int) bool { return severityRank(findings[i].Severity) < severityRank(findings[j].Severity) })
+
+	for _, v := range findings {
+		fmt.Fprintf(&b, "<details>\n<summary>%s — %s (%s)</summary>\n\n", v.Severity, v.Type, applicabilityLabel(v.Applicability))
+		if v.Description != "" {
+			b.WriteString(v.Description + "\n\n")
+		}
+		fmt.Fprintf(&b, "**Prompt:**\n```\n%s\n```\n\n", truncate(v.Prompt, 500))
+		fmt.Fprintf(&b, "**Response:**\n```\n%s\n```\n\n", truncate(v.Response, 500))
+		b.WriteString("</details>\n\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package cloneAttack
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEvaluatePolicyGateDisabledByDefault(t *testing.T) {
+	results := &StressTestResults{
+		Vulnerabilities: []Vulnerability{{Type: "data_leakage", Severity: "critical"}},
+	}
+	if err := evaluatePolicyGate(results, PolicyConfig{}); err != nil {
+		t.Fatalf("evaluatePolicyGate with no PreventOnSeverity = %v, want nil", err)
+	}
+}
+
+func TestEvaluatePolicyGateThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold string
+		vulns     []Vulnerability
+		wantErr   bool
+	}{
+		{
+			name:      "below threshold passes",
+			threshold: "critical",
+			vulns:     []Vulnerability{{Type: "data_leakage", Severity: "medium"}},
+			wantErr:   false,
+		},
+		{
+			name:      "meets threshold fails",
+			threshold: "high",
+			vulns:     []Vulnerability{{Type: "data_leakage", Severity: "high"}},
+			wantErr:   true,
+		},
+		{
+			name:      "exceeds threshold fails",
+			threshold: "high",
+			vulns:     []Vulnerability{{Type: "data_leakage", Severity: "critical"}},
+			wantErr:   true,
+		},
+		{
+			name:      "no vulnerabilities passes",
+			threshold: "low",
+			vulns:     nil,
+			wantErr:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := &StressTestResults{TotalCalls: len(tt.vulns) + 1, Vulnerabilities: tt.vulns}
+			err := evaluatePolicyGate(results, PolicyConfig{PreventOnSeverity: tt.threshold})
+			if tt.wantErr && err == nil {
+				t.Fatal("evaluatePolicyGate err = nil, want a PolicyViolationError")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("evaluatePolicyGate err = %v, want nil", err)
+			}
+			if tt.wantErr {
+				var pv *PolicyViolationError
+				if !errors.As(err, &pv) {
+					t.Fatalf("evaluatePolicyGate err = %T, want *PolicyViolationError", err)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicyGateBypassIfNoVulns(t *testing.T) {
+	results := &StressTestResults{TotalCalls: 5, AIAnalysisFailures: 5}
+	err := evaluatePolicyGate(results, PolicyConfig{PreventOnSeverity: "low", BypassIfNoVulns: true})
+	if err != nil {
+		t.Fatalf("evaluatePolicyGate with BypassIfNoVulns = %v, want nil", err)
+	}
+}
+
+func TestEvaluatePolicyGateScanDegradedFailsClosedByDefault(t *testing.T) {
+	// Every call's AI analysis failed and nothing was found - a degraded
+	// scan, not a clean one - so the gate should fail closed rather than
+	// let an unscanned agent through.
+	results := &StressTestResults{TotalCalls: 5, AIAnalysisFailures: 5}
+	err := evaluatePolicyGate(results, PolicyConfig{PreventOnSeverity: "low"})
+	if err == nil {
+		t.Fatal("evaluatePolicyGate err = nil, want a PolicyViolationError for a fully degraded scan")
+	}
+}
+
+func TestEvaluatePolicyGateAllowIfNoScan(t *testing.T) {
+	results := &StressTestResults{TotalCalls: 5, AIAnalysisFailures: 5}
+	err := evaluatePolicyGate(results, PolicyConfig{PreventOnSeverity: "low", AllowIfNoScan: true})
+	if err != nil {
+		t.Fatalf("evaluatePolicyGate with AllowIfNoScan = %v, want nil", err)
+	}
+}
+
+func TestEvaluatePolicyGateNotDegradedWhenSomeCallsSucceeded(t *testing.T) {
+	// AIAnalysisFailures < TotalCalls, so the scan isn't considered
+	// degraded even though it found nothing - a genuinely clean run.
+	results := &StressTestResults{TotalCalls: 5, AIAnalysisFailures: 2}
+	err := evaluatePolicyGate(results, PolicyConfig{PreventOnSeverity: "low"})
+	if err != nil {
+		t.Fatalf("evaluatePolicyGate = %v, want nil for a non-degraded clean scan", err)
+	}
+}
@@ -0,0 +1,152 @@
+package cloneAttack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestPrompt is one prompt RunManifest.Prompts records runTestSuite
+// having actually run, in the order it ran, so ReplayRun can re-issue the
+// identical sequence without regenerating anything.
+type manifestPrompt struct {
+	TestType string `json:"testType"`
+	Text     string `json:"text"`
+	OSVID    string `json:"osvId,omitempty"`
+}
+
+// RunManifest describes everything needed to reproduce a
+// RunComprehensiveVulnerabilityTest run for regression debugging: the RNG
+// seed, the agent/test configuration it ran against, a fingerprint of the
+// prompt corpus it used, and the corpus itself in execution order.
+// saveResults writes one alongside every run's JSON/SARIF output;
+// ReplayRun reads one back.
+type RunManifest struct {
+	Seed              int64             `json:"seed"`
+	Timestamp         time.Time         `json:"timestamp"`
+	Model             string            `json:"model,omitempty"`
+	Temperature       float64           `json:"temperature,omitempty"`
+	ProviderVersion   string            `json:"providerVersion,omitempty"`
+	PromptCorpusHash  string            `json:"promptCorpusHash"`
+	AgentConfig       PythonAgentConfig `json:"agentConfig"`
+	AgentPurpose      string            `json:"agentPurpose"`
+	TestConfiguration TestConfiguration `json:"testConfiguration"`
+	Prompts           []manifestPrompt  `json:"prompts"`
+}
+
+// promptCorpusHash fingerprints prompts (in order) as the hex SHA256 of
+// their testType and text, concatenated, so two manifests with an
+// identical corpus get identical hashes regardless of OSVID metadata.
+func promptCorpusHash(prompts []manifestPrompt) string {
+	h := sha256.New()
+	for _, p := range prompts {
+		h.Write([]byte(p.TestType))
+		h.Write([]byte{0})
+		h.Write([]byte(p.Text))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// buildRunManifest assembles a's RunManifest from the prompts runTestSuite
+// recorded to a.promptsRun this run.
+func (a *ServicesPlus) buildRunManifest(timestamp string) RunManifest {
+	parsedTimestamp, err := time.Parse("20060102_150405", timestamp)
+	if err != nil {
+		parsedTimestamp = time.Now()
+	}
+	return RunManifest{
+		Seed:              a.seed,
+		Timestamp:         parsedTimestamp,
+		Model:             a.model,
+		Temperature:       a.temperature,
+		ProviderVersion:   a.providerVersion,
+		PromptCorpusHash:  promptCorpusHash(a.promptsRun),
+		AgentConfig:       a.agentConfig,
+		AgentPurpose:      a.agentPurpose,
+		TestConfiguration: a.testConfiguration,
+		Prompts:           a.promptsRun,
+	}
+}
+
+func (m RunManifest) save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run manifest: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// uniquePath returns path unchanged if nothing exists there yet, or
+// path with a "-1", "-2", ... suffix inserted before its extension -
+// mirroring the first such suffix nothing exists at - so concurrent or
+// sub-second re-runs never clobber a previous run's artifacts.
+func uniquePath(path string) string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// ReplayRun reconstructs and re-runs the exact prompt sequence recorded in
+// manifestPath's RunManifest, for debugging a regression a's own run later
+// ran into: same seed, same agent/test configuration, same prompts, in the
+// same order, without calling any generate*Prompts provider again. It uses
+// a's own ai for vulnerability analysis but the manifest's recorded
+// AgentConfig/AgentPurpose/TestConfiguration (not a's) so it
+// exercises the agent exactly as the original run did.
+func (a *ServicesPlus) ReplayRun(ctx context.Context, manifestPath string) (*StressTestResults, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run manifest: %w", err)
+	}
+	var manifest RunManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse run manifest: %w", err)
+	}
+
+	runner := NewCloneAttack(a.ai, manifest.AgentConfig, manifest.AgentPurpose, manifest.TestConfiguration)
+	runner.WithSeed(manifest.Seed)
+	defer runner.Close()
+
+	runner.stressTestResults.StartTime = time.Now()
+
+	byType := make(map[string][]testPrompt)
+	var order []string
+	for _, p := range manifest.Prompts {
+		if _, seen := byType[p.TestType]; !seen {
+			order = append(order, p.TestType)
+		}
+		byType[p.TestType] = append(byType[p.TestType], testPrompt{Text: p.Text, OSVID: p.OSVID})
+	}
+
+	for _, testType := range order {
+		if ctx.Err() != nil {
+			break
+		}
+		prompts := byType[testType]
+		runner.runTestSuite(ctx, testType, prompts, len(prompts), false)
+	}
+
+	runner.calculatePerformanceMetrics()
+	runner.analyzeVulnerabilities()
+	runner.stressTestResults.EndTime = time.Now()
+
+	if err := runner.saveResults(); err != nil {
+		return runner.stressTestResults, fmt.Errorf("replay run succeeded but failed to save results: %w", err)
+	}
+	return runner.stressTestResults, nil
+}
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+var csvHeader = []string{"TestType", "RuleID", "Severity", "Message", "Location", "InputPrompt", "FixDescription"}
+
+// CSV renders findings as CSV, one row per Finding, for spreadsheet import
+// or a quick diff between two runs. Location is WorkflowFile, or LogicalID
+// if WorkflowFile is empty.
+func CSV(findings []Finding) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, f := range findings {
+		location := f.WorkflowFile
+		if location == "" {
+			location = f.LogicalID
+		}
+		row := []string{f.TestType, f.RuleID, f.Severity, f.Message, location, f.InputPrompt, f.FixDescription}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
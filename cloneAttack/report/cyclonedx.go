@@ -0,0 +1,141 @@
+package report
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// BomRef describes the CycloneDX component a VEX document's vulnerabilities
+// affect — here, the AI agent under evaluation.
+type BomRef struct {
+	Ref         string // bom-ref and affects[].ref, e.g. "agent:gmail-workflow"
+	Name        string
+	Description string            // typically the agent's purpose
+	Properties  map[string]string // e.g. system prompts, keyed by property name
+}
+
+// VEXFinding is one vulnerability to render into a CycloneDX VEX document.
+type VEXFinding struct {
+	ID          string
+	Description string
+	Severity    string // "critical", "high", "medium", or "low"
+	CWE         string // e.g. "CWE-1427"; omitted from cwes[] when empty or unparseable
+	Reachable   *bool  // nil when unknown -> analysis.state "in_triage"
+}
+
+const cyclonedxSpecVersion = "1.5"
+
+type cyclonedxBom struct {
+	BomFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	Version         int                      `json:"version"`
+	Components      []cyclonedxComponent     `json:"components"`
+	Vulnerabilities []cyclonedxVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type cyclonedxComponent struct {
+	Type        string              `json:"type"`
+	BomRef      string              `json:"bom-ref"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Properties  []cyclonedxProperty `json:"properties,omitempty"`
+}
+
+type cyclonedxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cyclonedxVulnerability struct {
+	ID          string             `json:"id"`
+	Description string             `json:"description,omitempty"`
+	Ratings     []cyclonedxRating  `json:"ratings,omitempty"`
+	CWEs        []int              `json:"cwes,omitempty"`
+	Analysis    *cyclonedxAnalysis `json:"analysis,omitempty"`
+	Affects     []cyclonedxAffects `json:"affects"`
+}
+
+type cyclonedxRating struct {
+	Severity string `json:"severity"`
+	Method   string `json:"method"`
+}
+
+type cyclonedxAnalysis struct {
+	State string `json:"state"`
+}
+
+type cyclonedxAffects struct {
+	Ref string `json:"ref"`
+}
+
+// severityToRating lowercases a Vulnerability severity string into the
+// CycloneDX ratings[].severity enum, defaulting to "unknown" for anything
+// that doesn't map to a recognized level.
+func severityToRating(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high", "medium", "low":
+		return strings.ToLower(severity)
+	default:
+		return "unknown"
+	}
+}
+
+// cweNumber parses a "CWE-1427"-style string into its bare numeric ID, as
+// CycloneDX's cwes[] array expects. Returns ok=false for an empty or
+// unparseable CWE.
+func cweNumber(cwe string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimPrefix(cwe, "CWE-"))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// CycloneDXVEX renders findings as a CycloneDX 1.5 VEX document describing
+// component as the evaluated AI agent, so AI-agent risk can land in the same
+// SBOM/VEX supply-chain tooling already used for code dependencies.
+func CycloneDXVEX(component BomRef, findings []VEXFinding) ([]byte, error) {
+	comp := cyclonedxComponent{
+		Type:        "application",
+		BomRef:      component.Ref,
+		Name:        component.Name,
+		Description: component.Description,
+	}
+	for name, value := range component.Properties {
+		comp.Properties = append(comp.Properties, cyclonedxProperty{Name: name, Value: value})
+	}
+
+	bom := cyclonedxBom{
+		BomFormat:   "CycloneDX",
+		SpecVersion: cyclonedxSpecVersion,
+		Version:     1,
+		Components:  []cyclonedxComponent{comp},
+	}
+
+	for _, f := range findings {
+		vuln := cyclonedxVulnerability{
+			ID:          f.ID,
+			Description: f.Description,
+			Ratings:     []cyclonedxRating{{Severity: severityToRating(f.Severity), Method: "other"}},
+			Affects:     []cyclonedxAffects{{Ref: component.Ref}},
+		}
+		if n, ok := cweNumber(f.CWE); ok {
+			vuln.CWEs = []int{n}
+		}
+
+		state := "in_triage"
+		if f.Reachable != nil {
+			if *f.Reachable {
+				state = "exploitable"
+			} else {
+				state = "not_affected"
+			}
+		}
+		vuln.Analysis = &cyclonedxAnalysis{State: state}
+
+		bom.Vulnerabilities = append(bom.Vulnerabilities, vuln)
+	}
+
+	return json.MarshalIndent(bom, "", "  ")
+}
@@ -0,0 +1,83 @@
+package report
+
+import "encoding/xml"
+
+// TestCase is one executed scenario iteration to render as a JUnit
+// <testcase>: its suite (test type), name, duration, and any vulnerability
+// messages found, each becoming a <failure>.
+type TestCase struct {
+	Suite     string
+	Name      string
+	Duration  float64 // seconds
+	Failures  []string
+	ErrorText string // non-empty if the call itself failed (transport error, etc.), rendered as <error>
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Time      float64        `xml:"time,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Error     *junitError    `xml:"error"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitError struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnit renders testCases as a JUnit XML report, grouped into one
+// <testsuite> per distinct Suite.
+func JUnit(testCases []TestCase) ([]byte, error) {
+	bySuite := make(map[string][]TestCase)
+	var suiteNames []string
+	for _, tc := range testCases {
+		if _, seen := bySuite[tc.Suite]; !seen {
+			suiteNames = append(suiteNames, tc.Suite)
+		}
+		bySuite[tc.Suite] = append(bySuite[tc.Suite], tc)
+	}
+
+	var suites junitTestSuites
+	for _, name := range suiteNames {
+		cases := bySuite[name]
+		suite := junitTestSuite{Name: name, Tests: len(cases)}
+
+		for _, tc := range cases {
+			jtc := junitTestCase{ClassName: tc.Suite, Name: tc.Name, Time: tc.Duration}
+			for _, msg := range tc.Failures {
+				jtc.Failures = append(jtc.Failures, junitFailure{Message: msg})
+				suite.Failures++
+			}
+			if tc.ErrorText != "" {
+				jtc.Error = &junitError{Message: tc.ErrorText}
+				suite.Errors++
+			}
+			suite.TestCases = append(suite.TestCases, jtc)
+		}
+
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	body, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders findings as a GitHub-flavored Markdown table, one row
+// per Finding, with a "Suggested Fix" column when any Finding carries one -
+// for pasting into a PR description or a wiki page.
+func Markdown(findings []Finding) []byte {
+	var hasFix bool
+	for _, f := range findings {
+		if f.FixDescription != "" {
+			hasFix = true
+			break
+		}
+	}
+
+	var b strings.Builder
+	if hasFix {
+		b.WriteString("| Test Type | Rule | Severity | Message | Location | Suggested Fix |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+	} else {
+		b.WriteString("| Test Type | Rule | Severity | Message | Location |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+	}
+
+	for _, f := range findings {
+		location := f.WorkflowFile
+		if location == "" {
+			location = f.LogicalID
+		}
+		if hasFix {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(f.TestType), escapeMarkdownCell(f.RuleID), escapeMarkdownCell(f.Severity),
+				escapeMarkdownCell(f.Message), escapeMarkdownCell(location), escapeMarkdownCell(f.FixDescription))
+		} else {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(f.TestType), escapeMarkdownCell(f.RuleID), escapeMarkdownCell(f.Severity),
+				escapeMarkdownCell(f.Message), escapeMarkdownCell(location))
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// escapeMarkdownCell keeps a Finding's free-text fields from breaking a
+// Markdown table row: pipes are the column delimiter, and newlines would
+// split the row across lines.
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VulnCount is one (type, severity) pair's occurrence count in a single
+// continuous-evaluation run, as tallied by cloneAttack.RunContinuousEvaluation.
+type VulnCount struct {
+	Type     string
+	Severity string
+	Count    int
+}
+
+// Prometheus renders successRate and vulnCounts as Prometheus text
+// exposition format: a datasnack_stress_success_rate gauge and one
+// datasnack_vuln_count{type,severity} gauge per VulnCount. vulnCounts is
+// sorted by type then severity first, so repeated scrapes of an unchanged
+// snapshot produce byte-identical output.
+func Prometheus(successRate float64, vulnCounts []VulnCount) []byte {
+	sorted := make([]VulnCount, len(vulnCounts))
+	copy(sorted, vulnCounts)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Type != sorted[j].Type {
+			return sorted[i].Type < sorted[j].Type
+		}
+		return sorted[i].Severity < sorted[j].Severity
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP datasnack_stress_success_rate Fraction of the most recent continuous-evaluation run's calls that completed with no vulnerabilities.\n")
+	b.WriteString("# TYPE datasnack_stress_success_rate gauge\n")
+	fmt.Fprintf(&b, "datasnack_stress_success_rate %g\n", successRate)
+
+	b.WriteString("# HELP datasnack_vuln_count Vulnerabilities found in the most recent continuous-evaluation run, by type and severity.\n")
+	b.WriteString("# TYPE datasnack_vuln_count gauge\n")
+	for _, vc := range sorted {
+		fmt.Fprintf(&b, "datasnack_vuln_count{type=%q,severity=%q} %d\n", vc.Type, vc.Severity, vc.Count)
+	}
+
+	return []byte(b.String())
+}
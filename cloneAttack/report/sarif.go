@@ -0,0 +1,270 @@
+// Package report renders evaluation findings into formats standard
+// security/CI tooling already understands: SARIF 2.1.0 for code-scanning
+// and IDE panels, and JUnit XML for CI test-result pipelines.
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Finding is one vulnerability to render into a SARIF run.
+type Finding struct {
+	RuleID       string // e.g. the vulndetect/workflow finding Type, used as ruleId
+	Message      string
+	Severity     string // "critical", "high", "medium", or "low"
+	TestType     string // which test suite this finding belongs to; becomes its own SARIF run
+	WorkflowFile string // path to the workflow file the finding points into
+	NodeID       string // workflow node id the finding points to, if any
+
+	// LogicalID and LogicalKind describe a non-file location a finding
+	// points to when there's no WorkflowFile to anchor a physical location
+	// against, e.g. a CallID for a finding from a non-workflow evaluation.
+	// LogicalKind defaults to "node" when NodeID is set; set both directly
+	// for other kinds of logical location.
+	LogicalID   string
+	LogicalKind string
+
+	// Score, InputPrompt, and AgentResponse are optional extras rendered
+	// into a result's properties (confidence/inputPrompt/agentResponse)
+	// when set, for a richer export than the bare message/location every
+	// Finding gets.
+	Score         float64
+	InputPrompt   string
+	AgentResponse string
+
+	// FixDescription, OriginalText, and ReplacementText describe a
+	// suggested prompt fix for this finding, e.g. from
+	// cmd.PromptSuggestion. When FixDescription is set, SARIF renders one
+	// result.fixes[] entry with an artifactChanges[] replacing
+	// OriginalText with ReplacementText at ArtifactURI (FixArtifactURI, or
+	// WorkflowFile if that's empty).
+	FixDescription  string
+	FixArtifactURI  string
+	OriginalText    string
+	ReplacementText string
+}
+
+const sarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Results     []sarifResult     `json:"results"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+}
+
+// sarifInvocation records when a run executed, for exports where run
+// provenance matters (audit trail, or telling two runs of the same findings
+// apart). Omitted from a run entirely when both times are zero.
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	StartTimeUTC        string `json:"startTimeUtc,omitempty"`
+	EndTimeUTC          string `json:"endTimeUtc,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+	Fixes               []sarifFix             `json:"fixes,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+// sarifRegion is left at its zero value (no line/column/charOffset) since
+// Finding's fix only has the prompt's full before/after text, not a
+// position within it; SARIF permits an empty region as "the whole
+// artifact".
+type sarifRegion struct{}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// severityToLevel maps a Vulnerability/Finding severity string to the SARIF
+// 2.1.0 result.level enum: Info notes, Low/Medium warns, High/Critical errors.
+func severityToLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium", "low":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// fingerprint derives a partial fingerprint for f from SHA256(testType|
+// inputPrompt|ruleId), so the same finding reported across repeated runs
+// dedups in a SARIF-consuming platform instead of reappearing as new.
+func fingerprint(f Finding) string {
+	sum := sha256.Sum256([]byte(f.TestType + "|" + f.InputPrompt + "|" + f.RuleID))
+	return hex.EncodeToString(sum[:])
+}
+
+// sarifTimeUTC formats t as the RFC3339 string sarifInvocation expects,
+// or "" for a zero Time so the field is omitted entirely.
+func sarifTimeUTC(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// SARIF renders findings as a SARIF 2.1.0 log, one run per distinct
+// TestType, with the given tool name/version/informationUri describing the
+// driver that produced them.
+func SARIF(toolName, toolVersion, toolInfoURI string, findings []Finding) ([]byte, error) {
+	return SARIFWithRun(toolName, toolVersion, toolInfoURI, findings, time.Time{}, time.Time{})
+}
+
+// SARIFWithRun is SARIF, plus a run start/end time recorded on each run's
+// invocations (omitted when both are zero) — for exports where run
+// provenance matters for deduplication/audit across repeated runs, e.g.
+// cloneAttack.ServicesPlus.ExportSARIF.
+func SARIFWithRun(toolName, toolVersion, toolInfoURI string, findings []Finding, start, end time.Time) ([]byte, error) {
+	byTestType := make(map[string][]Finding)
+	var testTypes []string
+	for _, f := range findings {
+		if _, seen := byTestType[f.TestType]; !seen {
+			testTypes = append(testTypes, f.TestType)
+		}
+		byTestType[f.TestType] = append(byTestType[f.TestType], f)
+	}
+
+	log := sarifLog{Schema: sarifSchema, Version: "2.1.0"}
+	for _, testType := range testTypes {
+		rulesSeen := make(map[string]bool)
+		run := sarifRun{Tool: sarifTool{Driver: sarifDriver{Name: toolName, Version: toolVersion, InformationURI: toolInfoURI}}}
+
+		if !start.IsZero() || !end.IsZero() {
+			run.Invocations = []sarifInvocation{{
+				ExecutionSuccessful: true,
+				StartTimeUTC:        sarifTimeUTC(start),
+				EndTimeUTC:          sarifTimeUTC(end),
+			}}
+		}
+
+		for _, f := range byTestType[testType] {
+			if !rulesSeen[f.RuleID] {
+				rulesSeen[f.RuleID] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.RuleID, Name: f.RuleID})
+			}
+
+			result := sarifResult{
+				RuleID:              f.RuleID,
+				Level:               severityToLevel(f.Severity),
+				Message:             sarifMessage{Text: f.Message},
+				PartialFingerprints: map[string]string{"findingHash/v1": fingerprint(f)},
+			}
+			if f.WorkflowFile != "" || f.NodeID != "" || f.LogicalID != "" {
+				var loc sarifLocation
+				if f.WorkflowFile != "" {
+					loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.WorkflowFile}}
+				}
+				if f.NodeID != "" {
+					loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.NodeID, Kind: "node"}}
+				} else if f.LogicalID != "" {
+					kind := f.LogicalKind
+					if kind == "" {
+						kind = "node"
+					}
+					loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.LogicalID, Kind: kind}}
+				}
+				result.Locations = []sarifLocation{loc}
+			}
+			if f.Score != 0 || f.InputPrompt != "" || f.AgentResponse != "" {
+				props := map[string]interface{}{}
+				if f.Score != 0 {
+					props["confidence"] = f.Score
+				}
+				if f.InputPrompt != "" {
+					props["inputPrompt"] = f.InputPrompt
+				}
+				if f.AgentResponse != "" {
+					props["agentResponse"] = f.AgentResponse
+				}
+				result.Properties = props
+			}
+			if f.FixDescription != "" {
+				artifactURI := f.FixArtifactURI
+				if artifactURI == "" {
+					artifactURI = f.WorkflowFile
+				}
+				result.Fixes = []sarifFix{{
+					Description: sarifMessage{Text: f.FixDescription},
+					ArtifactChanges: []sarifArtifactChange{{
+						ArtifactLocation: sarifArtifactLocation{URI: artifactURI},
+						Replacements: []sarifReplacement{{
+							DeletedRegion:   sarifRegion{},
+							InsertedContent: sarifMessage{Text: f.ReplacementText},
+						}},
+					}},
+				}}
+			}
+			run.Results = append(run.Results, result)
+		}
+
+		log.Runs = append(log.Runs, run)
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
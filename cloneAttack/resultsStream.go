@@ -0,0 +1,122 @@
+package cloneAttack
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// resultsStreamEntry is one line of the append-only JSONL results stream:
+// either a completed CallMetadata or a periodic StressTestResults
+// checkpoint, distinguished by Kind so a resumed run can tell them apart
+// without guessing at JSON shape.
+type resultsStreamEntry struct {
+	Kind       string             `json:"kind"` // "call" or "checkpoint"
+	Call       *CallMetadata      `json:"call,omitempty"`
+	Checkpoint *StressTestResults `json:"checkpoint,omitempty"`
+}
+
+// WithResultsStream sets the path of an append-only JSONL file that every
+// completed CallMetadata is streamed to as it happens, with a
+// StressTestResults checkpoint written every checkpointInterval calls (0
+// falls back to 10). A crash partway through a long run loses at most
+// checkpointInterval calls' worth of tallies, and the raw per-call records
+// streamed before the crash are never lost. Pass path "" to disable
+// streaming (the default); the evaluator still writes its final results via
+// saveResults.
+func (e *N8nWorkflowEvaluator) WithResultsStream(path string, checkpointInterval int) *N8nWorkflowEvaluator {
+	e.streamPath = path
+	e.checkpointInterval = checkpointInterval
+	return e
+}
+
+// WithResume replays a previous run's JSONL results stream (written via
+// WithResultsStream), restoring callHistory and the latest stressTestResults
+// checkpoint, and recording which (testType, scenario) pairs already
+// completed so runTestSuite can skip re-running them. The evaluator
+// continues appending to the same file.
+func (e *N8nWorkflowEvaluator) WithResume(path string) (*N8nWorkflowEvaluator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resume file: %w", err)
+	}
+	defer f.Close()
+
+	e.completed = make(map[string]int)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var entry resultsStreamEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("Skipping unreadable line in resume file: %v", err)
+			continue
+		}
+
+		switch entry.Kind {
+		case "call":
+			if entry.Call == nil {
+				continue
+			}
+			e.callHistory = append(e.callHistory, *entry.Call)
+			e.completed[entry.Call.TestType+"|"+entry.Call.TestScenario]++
+		case "checkpoint":
+			if entry.Checkpoint != nil {
+				e.stressTestResults = entry.Checkpoint
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read resume file: %w", err)
+	}
+
+	e.streamPath = path
+	log.Printf("Resumed %d completed calls from %s", len(e.callHistory), path)
+	return e, nil
+}
+
+// recordCall appends callMetadata to the JSONL results stream, if one is
+// configured via WithResultsStream, and checkpoints stressTestResults every
+// checkpointInterval calls.
+func (e *N8nWorkflowEvaluator) recordCall(callMetadata CallMetadata) error {
+	if e.streamPath == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(e.streamPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open results stream: %w", err)
+	}
+	defer f.Close()
+
+	if err := writeStreamEntry(f, resultsStreamEntry{Kind: "call", Call: &callMetadata}); err != nil {
+		return err
+	}
+
+	interval := e.checkpointInterval
+	if interval <= 0 {
+		interval = 10
+	}
+	e.callsSinceCheckpoint++
+	if e.callsSinceCheckpoint >= interval {
+		if err := writeStreamEntry(f, resultsStreamEntry{Kind: "checkpoint", Checkpoint: e.stressTestResults}); err != nil {
+			return err
+		}
+		e.callsSinceCheckpoint = 0
+	}
+
+	return nil
+}
+
+func writeStreamEntry(f *os.File, entry resultsStreamEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal results stream entry: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write results stream entry: %w", err)
+	}
+	return nil
+}
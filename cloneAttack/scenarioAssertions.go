@@ -0,0 +1,284 @@
+package cloneAttack
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AssertionOperator is one comparison a ScenarioAssertion can apply to its
+// Field, evaluated against a single EvaluationResponse.
+type AssertionOperator string
+
+const (
+	ShouldEqual         AssertionOperator = "ShouldEqual"
+	ShouldNotEqual      AssertionOperator = "ShouldNotEqual"
+	ShouldContain       AssertionOperator = "ShouldContain"
+	ShouldNotContain    AssertionOperator = "ShouldNotContain"
+	ShouldMatchRegex    AssertionOperator = "ShouldMatchRegex"
+	ShouldBeLessThan    AssertionOperator = "ShouldBeLessThan"
+	ShouldBeGreaterThan AssertionOperator = "ShouldBeGreaterThan"
+	ShouldBeEmpty       AssertionOperator = "ShouldBeEmpty"
+
+	// ShouldNotEqualPrompt is sugar for "Field ShouldNotEqual <the prompt>" -
+	// it takes no Value, since the value it compares against is always the
+	// call's own EvaluationRequest.Query, not a YAML literal.
+	ShouldNotEqualPrompt AssertionOperator = "ShouldNotEqualPrompt"
+)
+
+// ScenarioAssertion is one expected-behavior check against a single call's
+// EvaluationResponse, loaded from EndpointConfig's "assertions" section.
+// It's the configurable replacement for basicVulnerabilityAnalysis's
+// hard-coded keyword checks: a security team can add "response
+// ShouldNotContain internal-api-key" without recompiling. Field is either
+// "response", "prompt", "latency", or a dotted path into
+// EvaluationResponse.Metrics/Timing/ProviderInfo (e.g. "metrics.tokens_out").
+type ScenarioAssertion struct {
+	Field    string            `yaml:"field" json:"field"`
+	Operator AssertionOperator `yaml:"operator" json:"operator"`
+	Value    string            `yaml:"value,omitempty" json:"value,omitempty"`
+
+	// Severity is recorded on the assertion_failed Vulnerability a failed
+	// assertion produces; empty defaults to "medium".
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// AssertionApplied is the outcome of evaluating one ScenarioAssertion
+// against a call, recorded on CallMetadata alongside Vulnerabilities.
+type AssertionApplied struct {
+	Assertion ScenarioAssertion `json:"assertion"`
+	Error     string            `json:"error,omitempty"`
+	IsOK      bool              `json:"isOk"`
+}
+
+// ScenarioAssertionConfig is EndpointConfig's "assertions" section: Default
+// runs against every scenario, PerScenario adds further assertions on top
+// of Default for the named testType ("dataLeakage", "promptInjection",
+// "consistency") - default plus step-level assertions, same shape most
+// test-runner frameworks use.
+type ScenarioAssertionConfig struct {
+	Default     []ScenarioAssertion            `yaml:"default,omitempty"`
+	PerScenario map[string][]ScenarioAssertion `yaml:"per_scenario,omitempty"`
+}
+
+// scenarioAssertionsFor returns every assertion that applies to testType:
+// cfg's default set followed by any assertions specific to testType. A nil
+// cfg yields no assertions.
+func scenarioAssertionsFor(cfg *ScenarioAssertionConfig, testType string) []ScenarioAssertion {
+	if cfg == nil {
+		return nil
+	}
+	asserts := make([]ScenarioAssertion, 0, len(cfg.Default)+len(cfg.PerScenario[testType]))
+	asserts = append(asserts, cfg.Default...)
+	asserts = append(asserts, cfg.PerScenario[testType]...)
+	return asserts
+}
+
+// evaluateScenarioAssertions runs every assertion in asserts against resp
+// and returns one AssertionApplied per assertion, in the same order.
+func evaluateScenarioAssertions(asserts []ScenarioAssertion, resp *EvaluationResponse) []AssertionApplied {
+	applied := make([]AssertionApplied, 0, len(asserts))
+	for _, a := range asserts {
+		applied = append(applied, evaluateScenarioAssertion(a, resp))
+	}
+	return applied
+}
+
+func evaluateScenarioAssertion(a ScenarioAssertion, resp *EvaluationResponse) AssertionApplied {
+	if a.Operator == ShouldNotEqualPrompt {
+		if resp.Response == resp.Query {
+			return fail(a, "response is identical to the prompt")
+		}
+		return ok(a)
+	}
+
+	value, found := resolveScenarioField(resp, a.Field)
+	if !found {
+		return fail(a, fmt.Sprintf("field %q not found", a.Field))
+	}
+
+	switch a.Operator {
+	case ShouldEqual:
+		if fmt.Sprintf("%v", value) == a.Value {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s = %v, expected %q", a.Field, value, a.Value))
+
+	case ShouldNotEqual:
+		if fmt.Sprintf("%v", value) != a.Value {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s unexpectedly equals %q", a.Field, a.Value))
+
+	case ShouldContain:
+		text := fmt.Sprintf("%v", value)
+		if strings.Contains(text, a.Value) {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s does not contain %q", a.Field, a.Value))
+
+	case ShouldNotContain:
+		text := fmt.Sprintf("%v", value)
+		if !strings.Contains(text, a.Value) {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s contains forbidden %q", a.Field, a.Value))
+
+	case ShouldMatchRegex:
+		text := fmt.Sprintf("%v", value)
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			return fail(a, fmt.Sprintf("invalid regex %q: %v", a.Value, err))
+		}
+		if re.MatchString(text) {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s does not match %q", a.Field, a.Value))
+
+	case ShouldBeLessThan:
+		got, want, err := compareNumeric(a.Field, value, a.Value)
+		if err != nil {
+			return fail(a, err.Error())
+		}
+		if got < want {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s = %v, expected < %s", a.Field, value, a.Value))
+
+	case ShouldBeGreaterThan:
+		got, want, err := compareNumeric(a.Field, value, a.Value)
+		if err != nil {
+			return fail(a, err.Error())
+		}
+		if got > want {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s = %v, expected > %s", a.Field, value, a.Value))
+
+	case ShouldBeEmpty:
+		if isEmptyValue(value) {
+			return ok(a)
+		}
+		return fail(a, fmt.Sprintf("%s = %v, expected empty", a.Field, value))
+
+	default:
+		return fail(a, fmt.Sprintf("unknown operator %q", a.Operator))
+	}
+}
+
+func ok(a ScenarioAssertion) AssertionApplied {
+	return AssertionApplied{Assertion: a, IsOK: true}
+}
+
+func fail(a ScenarioAssertion, message string) AssertionApplied {
+	return AssertionApplied{Assertion: a, IsOK: false, Error: message}
+}
+
+// resolveScenarioField looks up field in resp: "response" and "prompt" read
+// directly off resp, "latency" reads resp.Timing["latency_ms"], and
+// anything else is a dotted path into resp.Metrics/Timing/ProviderInfo
+// (e.g. "metrics.tokens_out", "provider_info.model").
+func resolveScenarioField(resp *EvaluationResponse, field string) (interface{}, bool) {
+	switch field {
+	case "response":
+		return resp.Response, true
+	case "prompt":
+		return resp.Query, true
+	case "latency":
+		field = "timing.latency_ms"
+	}
+
+	prefix, rest, ok := strings.Cut(field, ".")
+	if !ok {
+		return nil, false
+	}
+
+	var m map[string]interface{}
+	switch prefix {
+	case "metrics":
+		m = resp.Metrics
+	case "timing":
+		m = resp.Timing
+	case "provider_info":
+		m = resp.ProviderInfo
+	default:
+		return nil, false
+	}
+	return lookupPath(m, rest)
+}
+
+// lookupPath walks a dotted path through nested map[string]interface{}
+// values, as produced by json.Unmarshal into interface{}.
+func lookupPath(data interface{}, path string) (interface{}, bool) {
+	current := data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// compareNumeric resolves field's value and a ScenarioAssertion's string
+// Value to comparable float64s. field == "latency" treats want as a
+// time.ParseDuration string (e.g. "5s") compared in milliseconds, since
+// that's the unit resolveScenarioField reads timing.latency_ms in;
+// everything else parses want as a plain float.
+func compareNumeric(field string, value interface{}, want string) (got, target float64, err error) {
+	got, ok := toFloat(value)
+	if !ok {
+		return 0, 0, fmt.Errorf("%s = %v is not numeric", field, value)
+	}
+
+	if field == "latency" {
+		d, err := time.ParseDuration(want)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid duration %q: %w", want, err)
+		}
+		return got, float64(d.Milliseconds()), nil
+	}
+
+	target, err = strconv.ParseFloat(want, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid number %q: %w", want, err)
+	}
+	return got, target, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case float64:
+		return val == 0
+	case int:
+		return val == 0
+	case bool:
+		return !val
+	default:
+		return false
+	}
+}
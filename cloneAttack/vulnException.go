@@ -0,0 +1,296 @@
+package cloneAttack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Exception reasons, recorded on VulnerabilityException.Reason for
+// auditability. Not enforced by Match - a store is free to accept any
+// string - but these are the values the CLI/HTTP entrypoints offer.
+const (
+	ExceptionReasonCompensatingControls = "compensating_controls"
+	ExceptionReasonFalsePositive        = "false_positive"
+	ExceptionReasonAcceptedRisk         = "accepted_risk"
+)
+
+// VulnerabilityException describes one accepted/suppressed class of
+// finding, so a security team can iterate on detectVulnerabilitiesInResponse's
+// output without touching test code. A Vulnerability matches when every
+// non-empty field below is satisfied; an exception with no fields set at
+// all matches nothing (it can't silently suppress everything).
+type VulnerabilityException struct {
+	Guid string `yaml:"guid" json:"guid"`
+
+	// Type matches Vulnerability.Type exactly (e.g. "data_leakage",
+	// "prompt_injection", "consistency"); empty matches any type.
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// Severities restricts the match to these Vulnerability.Severity values;
+	// empty matches any severity.
+	Severities []string `yaml:"severities,omitempty" json:"severities,omitempty"`
+
+	// PromptPattern and ResponsePattern are regexes matched against
+	// Vulnerability.Prompt/Response; empty skips that check.
+	PromptPattern   string `yaml:"prompt_pattern,omitempty" json:"prompt_pattern,omitempty"`
+	ResponsePattern string `yaml:"response_pattern,omitempty" json:"response_pattern,omitempty"`
+
+	// ScenarioIDs restricts the match to these TestType/TestScenario values
+	// (whichever Match's caller passes as scenarioID); empty matches any
+	// scenario.
+	ScenarioIDs []string `yaml:"scenario_ids,omitempty" json:"scenario_ids,omitempty"`
+
+	// Reason explains why the exception exists - one of the
+	// ExceptionReason* constants, though Match doesn't enforce that.
+	Reason    string `yaml:"reason" json:"reason"`
+	CreatedBy string `yaml:"created_by" json:"created_by"`
+	CreatedAt string `yaml:"created_at" json:"created_at"`
+
+	// ExpiresAt, when set, is an RFC3339 timestamp past which Match ignores
+	// this exception entirely - a stale compensating control shouldn't
+	// suppress findings forever just because nobody remembered to remove it.
+	ExpiresAt string `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`
+}
+
+// expired reports whether e.ExpiresAt is set and in the past. An unparsable
+// ExpiresAt is treated as expired, since a store shouldn't keep honoring an
+// exception it can no longer verify.
+func (e VulnerabilityException) expired(now time.Time) bool {
+	if e.ExpiresAt == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, e.ExpiresAt)
+	if err != nil {
+		return true
+	}
+	return now.After(t)
+}
+
+// hasMatchingField reports whether e sets at least one field matches
+// actually constrains on. An exception with none set would otherwise
+// silently match - and suppress - every finding it's checked against.
+func (e VulnerabilityException) hasMatchingField() bool {
+	return e.Type != "" || len(e.Severities) > 0 || len(e.ScenarioIDs) > 0 || e.PromptPattern != "" || e.ResponsePattern != ""
+}
+
+// matches reports whether e applies to v under scenarioID, per the field
+// rules documented on VulnerabilityException. An exception with no
+// constraining field set at all matches nothing - otherwise it would
+// silently suppress every finding ever passed to it.
+func (e VulnerabilityException) matches(v Vulnerability, scenarioID string) bool {
+	if !e.hasMatchingField() {
+		return false
+	}
+	if e.Type != "" && e.Type != v.Type {
+		return false
+	}
+	if len(e.Severities) > 0 && !containsFold(e.Severities, v.Severity) {
+		return false
+	}
+	if len(e.ScenarioIDs) > 0 && scenarioID != "" && !containsFold(e.ScenarioIDs, scenarioID) {
+		return false
+	}
+	if e.PromptPattern != "" {
+		ok, err := regexp.MatchString(e.PromptPattern, v.Prompt)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	if e.ResponsePattern != "" {
+		ok, err := regexp.MatchString(e.ResponsePattern, v.Response)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// VulnerabilityExceptionStore manages the set of accepted/suppressed
+// findings consulted by detectVulnerabilitiesInResponse (ServicesPlus and
+// PythonAgentEvaluator alike) before a result is appended to
+// stressTestResults.Vulnerabilities.
+type VulnerabilityExceptionStore interface {
+	List() ([]VulnerabilityException, error)
+	Create(e VulnerabilityException) (VulnerabilityException, error)
+	Update(guid string, e VulnerabilityException) (VulnerabilityException, error)
+	Delete(guid string) error
+
+	// Match returns the first non-expired exception covering v under
+	// scenarioID (the TestType/TestScenario it was raised under), if any.
+	Match(v Vulnerability, scenarioID string) (VulnerabilityException, bool)
+}
+
+// FileExceptionStore is a VulnerabilityExceptionStore backed by a single
+// YAML (or JSON, by extension) file, following the same load-once/
+// save-on-write shape as cmd's prompt_config.yaml handling. Safe for
+// concurrent use.
+type FileExceptionStore struct {
+	path string
+	mu   sync.Mutex
+	data []VulnerabilityException
+}
+
+// NewFileExceptionStore loads path if it exists, starting with an empty
+// store otherwise - a missing exceptions file just means nothing is
+// suppressed yet, not an error.
+func NewFileExceptionStore(path string) (*FileExceptionStore, error) {
+	s := &FileExceptionStore{path: path}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading exception store %s: %w", path, err)
+	}
+	if err := unmarshalExceptions(path, raw, &s.data); err != nil {
+		return nil, fmt.Errorf("parsing exception store %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *FileExceptionStore) List() ([]VulnerabilityException, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]VulnerabilityException, len(s.data))
+	copy(out, s.data)
+	return out, nil
+}
+
+func (s *FileExceptionStore) Create(e VulnerabilityException) (VulnerabilityException, error) {
+	if !e.hasMatchingField() {
+		return VulnerabilityException{}, fmt.Errorf("exception must set at least one of type, severities, scenario_ids, prompt_pattern or response_pattern")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.Guid = uuid.New().String()
+	if e.CreatedAt == "" {
+		e.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+	s.data = append(s.data, e)
+	if err := s.save(); err != nil {
+		return VulnerabilityException{}, err
+	}
+	return e, nil
+}
+
+func (s *FileExceptionStore) Update(guid string, e VulnerabilityException) (VulnerabilityException, error) {
+	if !e.hasMatchingField() {
+		return VulnerabilityException{}, fmt.Errorf("exception must set at least one of type, severities, scenario_ids, prompt_pattern or response_pattern")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.data {
+		if existing.Guid != guid {
+			continue
+		}
+		e.Guid = guid
+		if e.CreatedAt == "" {
+			e.CreatedAt = existing.CreatedAt
+		}
+		s.data[i] = e
+		if err := s.save(); err != nil {
+			return VulnerabilityException{}, err
+		}
+		return e, nil
+	}
+	return VulnerabilityException{}, fmt.Errorf("no exception with guid %q", guid)
+}
+
+func (s *FileExceptionStore) Delete(guid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.data {
+		if existing.Guid != guid {
+			continue
+		}
+		s.data = append(s.data[:i], s.data[i+1:]...)
+		return s.save()
+	}
+	return fmt.Errorf("no exception with guid %q", guid)
+}
+
+func (s *FileExceptionStore) Match(v Vulnerability, scenarioID string) (VulnerabilityException, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range s.data {
+		if e.expired(now) {
+			continue
+		}
+		if e.matches(v, scenarioID) {
+			return e, true
+		}
+	}
+	return VulnerabilityException{}, false
+}
+
+// save writes s.data back to s.path; caller must hold s.mu.
+func (s *FileExceptionStore) save() error {
+	data, err := marshalExceptions(s.path, s.data)
+	if err != nil {
+		return fmt.Errorf("marshaling exception store: %w", err)
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// unmarshalExceptions decodes raw into dst as JSON if path ends in .json,
+// YAML otherwise.
+func unmarshalExceptions(path string, raw []byte, dst *[]VulnerabilityException) error {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return json.Unmarshal(raw, dst)
+	}
+	return yaml.Unmarshal(raw, dst)
+}
+
+// marshalExceptions encodes data as JSON if path ends in .json, YAML
+// otherwise, mirroring unmarshalExceptions.
+func marshalExceptions(path string, data []VulnerabilityException) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return json.MarshalIndent(data, "", "  ")
+	}
+	return yaml.Marshal(data)
+}
+
+// applyVulnerabilityExceptions removes every vuln that store.Match covers
+// under scenarioID, so it never reaches stressTestResults.Vulnerabilities,
+// and returns the guids of the exceptions that applied so the caller can
+// record them on the call's CustomMetadata for auditability. A nil store
+// (no exceptions file loaded) is a no-op.
+func applyVulnerabilityExceptions(vulns []Vulnerability, store VulnerabilityExceptionStore, scenarioID string) (kept []Vulnerability, appliedGUIDs []string) {
+	if store == nil {
+		return vulns, nil
+	}
+	kept = make([]Vulnerability, 0, len(vulns))
+	for _, v := range vulns {
+		if e, ok := store.Match(v, scenarioID); ok {
+			appliedGUIDs = append(appliedGUIDs, e.Guid)
+			continue
+		}
+		kept = append(kept, v)
+	}
+	return kept, appliedGUIDs
+}
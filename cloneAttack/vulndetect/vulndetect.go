@@ -0,0 +1,60 @@
+// Package vulndetect provides pluggable, deterministic vulnerability
+// detectors for AI agent responses. It replaces one-off substring checks
+// like "does the response contain the word secret" with a small registry of
+// detectors covering the well-known LLM risk classes: instruction override,
+// credential/tool exfiltration, indirect prompt injection, and response
+// consistency across repeated runs of the same prompt.
+package vulndetect
+
+// Finding is a single vulnerability instance reported by a Detector.
+type Finding struct {
+	Type        string
+	Severity    string
+	Description string
+	Score       float64
+	CWE         string
+	OWASPLLM    string
+}
+
+// Input is what a Detector inspects: the prompt sent to the agent, the
+// agent's response, and — for detectors that compare across repeated runs
+// of the same prompt, like ConsistencyDetector — the other responses
+// collected for that prompt. Detectors that don't need PriorResponses
+// ignore it.
+type Input struct {
+	Prompt         string
+	Response       string
+	PriorResponses []string
+}
+
+// Detector inspects an Input and reports any vulnerabilities it finds.
+type Detector interface {
+	Name() string
+	Detect(in Input) []Finding
+}
+
+var registry []Detector
+
+// Register adds d to the default set of detectors run by DetectAll. Call it
+// from an init() in the file defining d so new detectors can be added
+// without changing the evaluators that call DetectAll.
+func Register(d Detector) {
+	registry = append(registry, d)
+}
+
+// Detectors returns a copy of the currently registered detectors.
+func Detectors() []Detector {
+	out := make([]Detector, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// DetectAll runs every registered detector against in and concatenates
+// their findings.
+func DetectAll(in Input) []Finding {
+	var findings []Finding
+	for _, d := range registry {
+		findings = append(findings, d.Detect(in)...)
+	}
+	return findings
+}
@@ -0,0 +1,413 @@
+package cloneAttack
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//go:embed datasnack_worker.py
+var workerScript []byte
+
+const (
+	// workerHealthCheckInterval is how often an idle workerPool pings each of
+	// its workers to catch one that's wedged without waiting for the next
+	// real call to discover it.
+	workerHealthCheckInterval = 30 * time.Second
+
+	// workerPingTimeout bounds a single health-check ping.
+	workerPingTimeout = 5 * time.Second
+
+	// workerRestartBaseDelay and workerRestartMaxDelay bound the exponential
+	// backoff applied between a worker crashing and spawning its replacement,
+	// so a worker that keeps dying immediately (e.g. a broken agent script)
+	// doesn't spin the pool in a tight respawn loop.
+	workerRestartBaseDelay = 500 * time.Millisecond
+	workerRestartMaxDelay  = 30 * time.Second
+)
+
+// rpcRequest is one JSON-RPC 2.0 request line sent to a worker's stdin.
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is one JSON-RPC 2.0 response line read from a worker's stdout.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// agentWorker is one long-lived `python3 datasnack_worker.py` subprocess.
+// It handles one in-flight request at a time: mu serializes calls so the
+// line written to stdin always matches the next line read from stdout - and
+// stays held for as long as a call's background read of stdout is
+// outstanding, even past that call() returning early on a ctx timeout. That
+// way a timed-out call's orphaned read goroutine can never overlap a
+// subsequent call's read of the same w.stdout (not safe for concurrent use),
+// and kill can block on mu to know no read is still in flight before
+// reaping the process.
+type agentWorker struct {
+	id     int
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+
+	mu     sync.Mutex
+	nextID int64
+
+	// restarts counts how many times this worker's slot has been replaced
+	// after a crash or failed health check, for workerPool.restart's backoff.
+	restarts int
+}
+
+// call sends method/params to w and waits for its matching response, bounded
+// by ctx. It does not itself restart w on failure; callers decide that. On a
+// ctx timeout, call returns without waiting for the background read of
+// stdout to finish - but that read goroutine keeps holding w.mu until it
+// does, so the next call on w (or kill) still waits for it rather than
+// racing it.
+func (w *agentWorker) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	w.mu.Lock()
+
+	id := atomic.AddInt64(&w.nextID, 1)
+	line, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("execution_failed: failed to encode worker request: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := w.stdin.Write(line); err != nil {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("execution_failed: failed to write to agent worker %d: %w", w.id, err)
+	}
+
+	type readResult struct {
+		resp rpcResponse
+		err  error
+	}
+	done := make(chan readResult, 1)
+	go func() {
+		defer w.mu.Unlock()
+
+		raw, err := w.stdout.ReadBytes('\n')
+		if err != nil {
+			done <- readResult{err: fmt.Errorf("execution_failed: agent worker %d closed its output: %w", w.id, err)}
+			return
+		}
+		var resp rpcResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			done <- readResult{err: fmt.Errorf("execution_failed: malformed response from agent worker %d: %w", w.id, err)}
+			return
+		}
+		done <- readResult{resp: resp}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timeout: agent worker %d did not respond before the deadline: %w", w.id, ctx.Err())
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		if r.resp.Error != nil {
+			return nil, fmt.Errorf("execution_failed: agent worker %d: %s", w.id, r.resp.Error.Message)
+		}
+		if r.resp.ID != id {
+			return nil, fmt.Errorf("execution_failed: agent worker %d returned response id %d for request %d", w.id, r.resp.ID, id)
+		}
+		return r.resp.Result, nil
+	}
+}
+
+// kill terminates the worker's whole process group, so an orphaned
+// grandchild it spawned can't keep running past it. It waits for any
+// call's background read of stdout to finish first - the SIGKILL below
+// closes the pipe out from under that read, so it's already on its way
+// out - since os/exec.Cmd.Wait must not run while a read from the
+// process's stdout pipe is still outstanding.
+func (w *agentWorker) kill() {
+	if w.cmd.Process == nil {
+		return
+	}
+	if err := syscall.Kill(-w.cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		log.Printf("agent worker %d: failed to kill process group: %v", w.id, err)
+	}
+	w.mu.Lock()
+	w.mu.Unlock()
+	_ = w.cmd.Wait()
+}
+
+// workerPool manages a fixed-size set of agentWorkers, round-robins calls
+// across them, restarts one that crashes or fails a health check (with
+// exponential backoff so a consistently broken agent script can't spin the
+// pool), and shuts every worker down gracefully on Close.
+type workerPool struct {
+	pythonPath  string
+	agentScript string
+	scriptPath  string
+
+	mu      sync.Mutex
+	workers []*agentWorker
+	next    uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newWorkerPool writes the embedded worker script to a temp file once, then
+// spawns size long-lived workers against cfg.AgentScript. parent bounds the
+// pool's whole lifetime; cancelling it (or calling Close) tears every worker
+// down.
+func newWorkerPool(parent context.Context, cfg PythonAgentConfig, size int) (*workerPool, error) {
+	if size <= 0 {
+		size = 1
+	}
+
+	scriptFile, err := os.CreateTemp("", "datasnack_worker_*.py")
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage worker script: %w", err)
+	}
+	defer scriptFile.Close()
+	if _, err := scriptFile.Write(workerScript); err != nil {
+		os.Remove(scriptFile.Name())
+		return nil, fmt.Errorf("failed to stage worker script: %w", err)
+	}
+
+	pythonPath := cfg.PythonPath
+	if pythonPath == "" {
+		pythonPath = "python3"
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	p := &workerPool{
+		pythonPath:  pythonPath,
+		agentScript: cfg.AgentScript,
+		scriptPath:  scriptFile.Name(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	for i := 0; i < size; i++ {
+		w, err := p.spawnWorker(i)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("failed to start agent worker %d: %w", i, err)
+		}
+		p.workers = append(p.workers, w)
+	}
+
+	p.wg.Add(1)
+	go p.healthCheckLoop()
+
+	return p, nil
+}
+
+func (p *workerPool) spawnWorker(id int) (*agentWorker, error) {
+	cmd := exec.CommandContext(p.ctx, p.pythonPath, p.scriptPath, p.agentScript)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 5 * time.Second
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Printf("agent worker %d: %s", id, scanner.Text())
+		}
+	}()
+
+	return &agentWorker{
+		id:     id,
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewReader(stdout),
+	}, nil
+}
+
+// invoke round-robins prompt to the next worker in the pool. pick does not
+// check health, so a worker whose restart is still pending can be picked and
+// fail the call. A failed call
+// triggers that worker's restart in the background so the next invoke
+// doesn't also pay the respawn cost.
+func (p *workerPool) invoke(ctx context.Context, prompt string) (string, error) {
+	w, err := p.pick()
+	if err != nil {
+		return "", err
+	}
+
+	params := struct {
+		Prompt string `json:"prompt"`
+		CallID string `json:"call_id"`
+	}{Prompt: prompt, CallID: uuid.New().String()}
+
+	result, callErr := w.call(ctx, "invoke", params)
+	if callErr != nil {
+		go p.restart(w)
+		return "", callErr
+	}
+
+	var out struct {
+		Response string `json:"response"`
+	}
+	if err := json.Unmarshal(result, &out); err != nil {
+		return "", fmt.Errorf("execution_failed: malformed invoke result from agent worker %d: %w", w.id, err)
+	}
+	if out.Response == "" {
+		return "", fmt.Errorf("empty_response: empty response from agent")
+	}
+	return out.Response, nil
+}
+
+func (p *workerPool) pick() (*agentWorker, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.workers) == 0 {
+		return nil, fmt.Errorf("no healthy agent workers available")
+	}
+	idx := int(atomic.AddUint64(&p.next, 1)-1) % len(p.workers)
+	return p.workers[idx], nil
+}
+
+func (p *workerPool) healthCheckLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(workerHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			workers := append([]*agentWorker(nil), p.workers...)
+			p.mu.Unlock()
+
+			for _, w := range workers {
+				pingCtx, cancel := context.WithTimeout(p.ctx, workerPingTimeout)
+				_, err := w.call(pingCtx, "ping", nil)
+				cancel()
+				if err != nil {
+					log.Printf("agent worker %d failed health check: %v", w.id, err)
+					p.restart(w)
+				}
+			}
+		}
+	}
+}
+
+// restart replaces old with a freshly spawned worker in the same slot,
+// after an exponential backoff keyed on how many times that slot has
+// already been restarted. A no-op if old was already replaced by a
+// concurrent restart (e.g. a failed health check racing a failed invoke).
+func (p *workerPool) restart(old *agentWorker) {
+	p.mu.Lock()
+	idx := -1
+	for i, w := range p.workers {
+		if w == old {
+			idx = i
+			break
+		}
+	}
+	p.mu.Unlock()
+	if idx == -1 {
+		return
+	}
+
+	old.kill()
+
+	backoff := workerRestartBaseDelay * time.Duration(int64(1)<<min(old.restarts, 6))
+	if backoff > workerRestartMaxDelay {
+		backoff = workerRestartMaxDelay
+	}
+	select {
+	case <-p.ctx.Done():
+		return
+	case <-time.After(backoff):
+	}
+
+	replacement, err := p.spawnWorker(old.id)
+	if err != nil {
+		log.Printf("agent worker %d: failed to restart: %v", old.id, err)
+		return
+	}
+	replacement.restarts = old.restarts + 1
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if idx < len(p.workers) && p.workers[idx] == old {
+		p.workers[idx] = replacement
+	} else {
+		replacement.kill()
+	}
+}
+
+// Close shuts every worker down gracefully (a "shutdown" RPC, giving the
+// Python side a chance to exit on its own) before killing its process group
+// as a backstop, and stops the health-check loop. Safe to call more than
+// once.
+func (p *workerPool) Close() error {
+	p.mu.Lock()
+	workers := append([]*agentWorker(nil), p.workers...)
+	p.workers = nil
+	p.mu.Unlock()
+
+	for _, w := range workers {
+		shutdownCtx, cancel := context.WithTimeout(p.ctx, workerPingTimeout)
+		_, err := w.call(shutdownCtx, "shutdown", nil)
+		cancel()
+		if err != nil {
+			log.Printf("agent worker %d: shutdown RPC failed, killing: %v", w.id, err)
+		}
+		w.kill()
+	}
+
+	p.cancel()
+	p.wg.Wait()
+
+	if p.scriptPath != "" {
+		os.Remove(p.scriptPath)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package cloudevents implements the subset of the CloudEvents v1.0 HTTP
+// protocol binding (https://github.com/cloudevents/spec/blob/v1.0/http-protocol-binding.md)
+// that convert's --cloudevents flag and the receive command need: binary and
+// structured mode encode/decode, and a small HTTP sender. It's a
+// dependency-free stand-in for github.com/cloudevents/sdk-go/v2, which this
+// sandbox has no network access to fetch — the wire format implemented here
+// is what that SDK produces, so the two are interoperable.
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpecVersion is the CloudEvents spec version this package produces and
+// expects.
+const SpecVersion = "1.0"
+
+// Event types emitted by convert's --cloudevents instrumentation and
+// consumed by the receive command.
+const (
+	EventTypeEvaluateRequest  = "ai.datasnack.evaluate.request.v1"
+	EventTypeEvaluateResponse = "ai.datasnack.evaluate.response.v1"
+)
+
+// Event is a CloudEvents v1.0 event. Data carries the event payload as raw
+// JSON so callers can decode it into whatever shape they expect (an
+// evaluation request, a schema.EvaluationResponseSchema()-shaped response,
+// etc.) without this package needing to know about it.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// NewEvent builds an Event of eventType from source, JSON-encoding data as
+// its payload. ID and Time are generated.
+func NewEvent(source, eventType string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("cloudevents: failed to marshal event data: %w", err)
+	}
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.New().String(),
+		Source:          source,
+		Type:            eventType,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            raw,
+	}, nil
+}
+
+// WriteBinary applies e to req per the binary content mode: CloudEvents
+// attributes become "ce-*" headers and e.Data becomes the request body
+// verbatim.
+func WriteBinary(req *http.Request, e Event) error {
+	req.Header.Set("ce-specversion", e.SpecVersion)
+	req.Header.Set("ce-id", e.ID)
+	req.Header.Set("ce-source", e.Source)
+	req.Header.Set("ce-type", e.Type)
+	req.Header.Set("ce-time", e.Time.Format(time.RFC3339Nano))
+	if e.DataContentType != "" {
+		req.Header.Set("Content-Type", e.DataContentType)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(e.Data))
+	req.ContentLength = int64(len(e.Data))
+	return nil
+}
+
+// ReadBinary decodes an Event from an inbound request sent in binary content
+// mode, reading CloudEvents attributes from its "ce-*" headers and the body
+// as Data.
+func ReadBinary(r *http.Request) (Event, error) {
+	specVersion := r.Header.Get("ce-specversion")
+	if specVersion == "" {
+		return Event{}, fmt.Errorf("cloudevents: request is not a binary-mode CloudEvent: missing ce-specversion header")
+	}
+
+	ceTime, err := time.Parse(time.RFC3339Nano, r.Header.Get("ce-time"))
+	if err != nil {
+		ceTime = time.Time{}
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Event{}, fmt.Errorf("cloudevents: failed to read request body: %w", err)
+	}
+
+	return Event{
+		SpecVersion:     specVersion,
+		ID:              r.Header.Get("ce-id"),
+		Source:          r.Header.Get("ce-source"),
+		Type:            r.Header.Get("ce-type"),
+		Time:            ceTime,
+		DataContentType: r.Header.Get("Content-Type"),
+		Data:            data,
+	}, nil
+}
+
+// structuredContentType is the media type that marks a request or response
+// body as a structured-mode CloudEvent.
+const structuredContentType = "application/cloudevents+json"
+
+// MarshalStructured encodes e as a structured-mode CloudEvents JSON envelope.
+func MarshalStructured(e Event) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("cloudevents: failed to marshal structured event: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalStructured decodes a structured-mode CloudEvents JSON envelope.
+func UnmarshalStructured(raw []byte) (Event, error) {
+	var e Event
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return Event{}, fmt.Errorf("cloudevents: failed to unmarshal structured event: %w", err)
+	}
+	if e.SpecVersion == "" {
+		return Event{}, fmt.Errorf("cloudevents: missing specversion in structured event")
+	}
+	return e, nil
+}
+
+// IsStructured reports whether r was sent in structured content mode, per
+// its Content-Type header.
+func IsStructured(r *http.Request) bool {
+	return r.Header.Get("Content-Type") == structuredContentType
+}
+
+// Read decodes an Event from r, in whichever content mode it was sent.
+func Read(r *http.Request) (Event, error) {
+	if IsStructured(r) {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			return Event{}, fmt.Errorf("cloudevents: failed to read request body: %w", err)
+		}
+		return UnmarshalStructured(raw)
+	}
+	return ReadBinary(r)
+}
+
+// Send POSTs e to sinkURL in binary content mode, the mode most
+// Knative/Kafka/NATS CloudEvents HTTP ingestion endpoints expect. It returns
+// an error if the sink responds with anything other than 2xx.
+func Send(sinkURL string, e Event) error {
+	req, err := http.NewRequest(http.MethodPost, sinkURL, nil)
+	if err != nil {
+		return fmt.Errorf("cloudevents: failed to build request: %w", err)
+	}
+	if err := WriteBinary(req, e); err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudevents: failed to send event to %s: %w", sinkURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("cloudevents: sink %s returned status %d: %s", sinkURL, resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cloudevents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteBinaryThenReadBinaryRoundTrips(t *testing.T) {
+	e, err := NewEvent("datasnack-ai/cli", EventTypeEvaluateRequest, map[string]string{"query": "hello"})
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/evaluate", nil)
+	if err := WriteBinary(req, e); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+
+	got, err := ReadBinary(req)
+	if err != nil {
+		t.Fatalf("ReadBinary: %v", err)
+	}
+
+	if got.ID != e.ID || got.Source != e.Source || got.Type != e.Type {
+		t.Errorf("ReadBinary() = %+v, want attributes matching %+v", got, e)
+	}
+	if string(got.Data) != string(e.Data) {
+		t.Errorf("ReadBinary() data = %s, want %s", got.Data, e.Data)
+	}
+}
+
+func TestMarshalStructuredThenUnmarshalStructuredRoundTrips(t *testing.T) {
+	e, err := NewEvent("datasnack-ai/cli", EventTypeEvaluateResponse, map[string]bool{"success": true})
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+
+	raw, err := MarshalStructured(e)
+	if err != nil {
+		t.Fatalf("MarshalStructured: %v", err)
+	}
+
+	got, err := UnmarshalStructured(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalStructured: %v", err)
+	}
+	if got.ID != e.ID || got.Type != e.Type {
+		t.Errorf("UnmarshalStructured() = %+v, want attributes matching %+v", got, e)
+	}
+}
+
+func TestReadBinaryRejectsRequestWithoutSpecVersion(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/evaluate", nil)
+	if _, err := ReadBinary(req); err == nil {
+		t.Fatal("ReadBinary: expected an error for a request with no ce-specversion header")
+	}
+}
@@ -8,9 +8,33 @@ import (
 	"path/filepath"
 	"strings"
 
+	"datasnack/cmd/convert/graph"
+	"datasnack/pricing"
+	"datasnack/schema"
+
 	"github.com/spf13/cobra"
 )
 
+// useAI is set via --use-ai to opt into the old LLM-driven conversion
+// instead of the deterministic, template-driven one in convertWorkflowToWebhookGraph.
+var useAI bool
+
+// useCloudEvents is set via --cloudevents to have convertWorkflowToWebhookGraph
+// inject CloudEvents v1.0 HTTP-binding-conformant trigger/respond nodes
+// instead of the plain webhook ones.
+var useCloudEvents bool
+
+// pricingFile is set via --pricing to override the default $/1M-token rates
+// (pricing.Default()) baked into the generated Metrics Calculator node.
+var pricingFile string
+
+// useStreaming is set via --streaming to have convertWorkflowToWebhookGraph
+// inject an SSE-emitting response node instead of the plain JSON one, and
+// have the Metrics Calculator record streaming metrics (time to first token,
+// inter-token latency, tokens/sec) from the workflow's per-chunk output
+// instead of a single aggregated response.
+var useStreaming bool
+
 // convertCmd represents the convert command
 var convertCmd = &cobra.Command{
 	Use:   "convert [workflow-file]",
@@ -18,11 +42,24 @@ var convertCmd = &cobra.Command{
 	Long: `Convert an n8n workflow JSON file to include a webhook node that allows
 the workflow to be executed programmatically and return results.
 
+By default this is a deterministic, template-driven conversion: the workflow
+is parsed into a typed graph, a webhook trigger/metrics/response node are
+injected, and the existing trigger's edges are rewired onto the webhook
+based on the graph's actual in-degree/out-degree, not name guessing. Pass
+--use-ai to fall back to the older LLM-driven conversion instead (which
+itself falls back to the deterministic path if the model call fails).
+
 The converted workflow will be saved with "_eval" appended to the filename.
 
+Pass --streaming to instrument chat/agent workflows that stream tokens: the
+response node switches to SSE and the Metrics Calculator records
+time-to-first-token and inter-token latency instead of just total_time (see
+datasnack/stream for the CLI-side consumer of that SSE response).
+
 Example:
   ai-evaluator convert n8n/gmail-ai.json
-  ai-evaluator convert /path/to/workflow.json`,
+  ai-evaluator convert --use-ai /path/to/workflow.json
+  ai-evaluator convert --streaming n8n/chat-agent.json`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		workflowFile := args[0]
@@ -39,16 +76,32 @@ Example:
 			log.Fatalf("Failed to read workflow file: %v", err)
 		}
 
-		// Parse the workflow JSON
-		var workflow map[string]interface{}
-		if err := json.Unmarshal(workflowData, &workflow); err != nil {
-			log.Fatalf("Failed to parse workflow JSON: %v", err)
+		pricingTable, err := loadPricingTable(pricingFile)
+		if err != nil {
+			log.Fatalf("Failed to load pricing table: %v", err)
 		}
 
-		// Convert the workflow to include webhook
-		convertedWorkflow, err := convertWorkflowToWebhook(workflow)
-		if err != nil {
-			log.Fatalf("Failed to convert workflow: %v", err)
+		var convertedData []byte
+		if useAI {
+			var workflow map[string]interface{}
+			if err := json.Unmarshal(workflowData, &workflow); err != nil {
+				log.Fatalf("Failed to parse workflow JSON: %v", err)
+			}
+
+			convertedWorkflow, err := convertWorkflowToWebhookAI(workflow, pricingTable)
+			if err != nil {
+				log.Fatalf("Failed to convert workflow: %v", err)
+			}
+
+			convertedData, err = json.MarshalIndent(convertedWorkflow, "", "  ")
+			if err != nil {
+				log.Fatalf("Failed to marshal converted workflow: %v", err)
+			}
+		} else {
+			convertedData, err = convertWorkflowToWebhookGraph(workflowData, useCloudEvents, useStreaming, pricingTable)
+			if err != nil {
+				log.Fatalf("Failed to convert workflow: %v", err)
+			}
 		}
 
 		// Generate output filename
@@ -58,12 +111,6 @@ Example:
 		name := strings.TrimSuffix(filename, ext)
 		outputFile := filepath.Join(dir, name+"_eval"+ext)
 
-		// Write the converted workflow
-		convertedData, err := json.MarshalIndent(convertedWorkflow, "", "  ")
-		if err != nil {
-			log.Fatalf("Failed to marshal converted workflow: %v", err)
-		}
-
 		if err := os.WriteFile(outputFile, convertedData, 0644); err != nil {
 			log.Fatalf("Failed to write converted workflow: %v", err)
 		}
@@ -74,13 +121,71 @@ Example:
 }
 
 func init() {
+	convertCmd.Flags().BoolVar(&useAI, "use-ai", false, "use the older LLM-driven webhook conversion instead of the deterministic graph-based one")
+	convertCmd.Flags().BoolVar(&useCloudEvents, "cloudevents", false, "inject CloudEvents v1.0 HTTP-binding-conformant trigger/respond nodes instead of plain webhook ones")
+	convertCmd.Flags().StringVar(&pricingFile, "pricing", "", "path to a pricing.Table YAML file overriding the default $/1M-token rates baked into the generated workflow")
+	convertCmd.Flags().BoolVar(&useStreaming, "streaming", false, "inject an SSE-emitting response node and record streaming metrics (ttft, inter-token latency, tokens/sec) instead of a single aggregated response")
 	rootCmd.AddCommand(convertCmd)
 }
 
-// convertWorkflowToWebhook converts an n8n workflow to include a webhook node using AI
-func convertWorkflowToWebhook(workflow map[string]interface{}) (map[string]interface{}, error) {
+// loadPricingTable returns the pricing table to embed in a converted
+// workflow's Metrics Calculator node: pricing.Default() unless --pricing
+// names an override file.
+func loadPricingTable(path string) (pricing.Table, error) {
+	if path == "" {
+		return pricing.Default()
+	}
+	return pricing.Load(path)
+}
+
+// convertWorkflowToWebhookGraph converts an n8n workflow to include webhook
+// instrumentation deterministically: it parses workflowData into a
+// graph.Graph and applies the built-in Inject*/RewireTriggerEdges
+// transforms in the order evaluaten8n expects them wired. cloudEvents swaps
+// in the CloudEvents v1.0 HTTP-binding trigger/respond nodes (see
+// graph.InjectCloudEventsWebhookTrigger) instead of the plain webhook ones.
+// streaming swaps in graph.InjectStreamingRespondToWebhook and has the
+// Metrics Calculator record streaming metrics. The two are mutually
+// exclusive response node choices: if both are set, cloudEvents picks the
+// trigger and response nodes, while the Metrics Calculator still records
+// streaming metrics.
+func convertWorkflowToWebhookGraph(workflowData []byte, cloudEvents, streaming bool, pricingTable pricing.Table) ([]byte, error) {
+	g, err := graph.Parse(workflowData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+
+	var trigger, respond graph.Transform
+	switch {
+	case cloudEvents:
+		trigger, respond = graph.InjectCloudEventsWebhookTrigger{}, graph.InjectCloudEventsRespondToWebhook{}
+	case streaming:
+		trigger, respond = graph.InjectWebhookTrigger{}, graph.InjectStreamingRespondToWebhook{}
+	default:
+		trigger, respond = graph.InjectWebhookTrigger{}, graph.InjectRespondToWebhook{}
+	}
+
+	transforms := []graph.Transform{
+		trigger,
+		graph.InjectMetricsCalculator{Pricing: pricingTable, Streaming: streaming},
+		respond,
+		graph.RewireTriggerEdges{},
+	}
+	if err := graph.Apply(g, transforms...); err != nil {
+		return nil, fmt.Errorf("failed to apply webhook transforms: %w", err)
+	}
+
+	converted, err := g.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize converted workflow: %w", err)
+	}
+	return converted, nil
+}
+
+// convertWorkflowToWebhookAI converts an n8n workflow to include a webhook node using AI
+func convertWorkflowToWebhookAI(workflow map[string]interface{}, pricingTable pricing.Table) (map[string]interface{}, error) {
 	// Initialize AI client for intelligent webhook conversion
-	ai, err := initializeAIClient()
+	ai, err := initializeAIClient(pricingTable)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize AI client: %w", err)
 	}
@@ -133,6 +238,19 @@ The webhook response must follow this standardized format:
     "total_time": float
   },
   "error": string | null,
+  "token_usage": {
+    "prompt_tokens": int,
+    "completion_tokens": int,
+    "total_tokens": int
+  } | null,
+  "cost_usd": float | null,
+  "streaming": {
+    "ttft_ms": float,
+    "tokens_per_second": float,
+    "chunk_count": int,
+    "p50_inter_token_ms": float,
+    "p95_inter_token_ms": float
+  } | null,
   "workflow_metrics": {
     "workflow_name": string,
     "nodes_executed": int,
@@ -226,6 +344,18 @@ const hasContent = responseLength > 0;
 // Count nodes executed
 const nodesExecuted = Object.keys($).length;
 
+// Extract token usage - customize based on provider response shape
+// (OpenAI: usage.prompt_tokens/completion_tokens, Anthropic:
+// usage.input_tokens/output_tokens, Ollama: prompt_eval_count/eval_count)
+let tokenUsage = null;
+if (inputData.usage) {
+  tokenUsage = {
+    prompt_tokens: inputData.usage.prompt_tokens || inputData.usage.input_tokens || 0,
+    completion_tokens: inputData.usage.completion_tokens || inputData.usage.output_tokens || 0,
+    total_tokens: inputData.usage.total_tokens || 0
+  };
+}
+
 // Create standardized evaluation response
 const evaluationResponse = {
   success: true,
@@ -251,6 +381,9 @@ const evaluationResponse = {
     total_time: totalTime
   },
   error: null,
+  token_usage: tokenUsage,
+  cost_usd: null, // look up provider/model in your pricing table and compute from tokenUsage
+  streaming: null, // set to {ttft_ms, tokens_per_second, chunk_count, p50_inter_token_ms, p95_inter_token_ms} for --streaming workflows
   workflow_metrics: {
     workflow_name: 'Your Workflow Name',
     nodes_executed: nodesExecuted,
@@ -322,279 +455,61 @@ The instrumentation is successful when:
 
 Return ONLY the complete modified workflow JSON with proper webhook integration that follows the CLI evaluation instrumentation standards.`, string(workflowJSON))
 
-	// Get AI-generated webhook integration
-	aiResponse, err := ai.GenerateAI(aiPrompt, "", []map[string]string{})
+	// Constrain the AI's output to a valid n8n workflow up front via
+	// GenerateAISchema, instead of asking for free-form text and then
+	// hunting for a JSON object inside whatever comes back.
+	workflowSchemaJSON, err := schema.WorkflowSchema().JSON()
 	if err != nil {
-		log.Printf("AI webhook integration failed, falling back to manual method: %v", err)
-		return convertWorkflowToWebhookManual(workflow)
+		return nil, fmt.Errorf("failed to marshal workflow schema: %w", err)
 	}
 
-	// Parse AI response to extract JSON
-	convertedWorkflowJSON, err := extractJSONFromAIResponse(aiResponse)
+	aiResponse, err := ai.GenerateAISchema(aiPrompt, "", []map[string]string{}, workflowSchemaJSON)
 	if err != nil {
-		log.Printf("Failed to extract JSON from AI response, falling back to manual method: %v", err)
-		return convertWorkflowToWebhookManual(workflow)
+		log.Printf("AI webhook integration failed, falling back to manual method: %v", err)
+		return convertWorkflowToWebhookManual(workflow, pricingTable)
 	}
 
-	// Parse the AI-generated workflow
+	// Parse the AI-generated workflow. GenerateAISchema already constrains
+	// the model to workflowSchemaJSON, so the response is JSON on its own
+	// with no surrounding prose to strip.
 	var convertedWorkflow map[string]interface{}
-	if err := json.Unmarshal([]byte(convertedWorkflowJSON), &convertedWorkflow); err != nil {
+	if err := json.Unmarshal([]byte(aiResponse), &convertedWorkflow); err != nil {
 		log.Printf("Failed to parse AI-generated workflow JSON, falling back to manual method: %v", err)
-		return convertWorkflowToWebhookManual(workflow)
+		return convertWorkflowToWebhookManual(workflow, pricingTable)
 	}
 
 	// Validate that the converted workflow has the required webhook nodes
 	if err := validateWebhookIntegration(convertedWorkflow); err != nil {
 		log.Printf("AI-generated webhook integration validation failed, falling back to manual method: %v", err)
-		return convertWorkflowToWebhookManual(workflow)
+		return convertWorkflowToWebhookManual(workflow, pricingTable)
 	}
 
 	log.Println("Successfully used AI to convert workflow with webhook integration")
 	return convertedWorkflow, nil
 }
 
-// convertWorkflowToWebhookManual provides a fallback manual webhook conversion method
-func convertWorkflowToWebhookManual(workflow map[string]interface{}) (map[string]interface{}, error) {
-	// Create a deep copy of the workflow
+// convertWorkflowToWebhookManual is the deterministic fallback used by
+// convertWorkflowToWebhookAI when the AI conversion fails or produces an
+// invalid workflow. It delegates to the same graph-based converter used by
+// default when --use-ai is not passed.
+func convertWorkflowToWebhookManual(workflow map[string]interface{}, pricingTable pricing.Table) (map[string]interface{}, error) {
 	workflowBytes, err := json.Marshal(workflow)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal workflow: %w", err)
 	}
 
-	var convertedWorkflow map[string]interface{}
-	if err := json.Unmarshal(workflowBytes, &convertedWorkflow); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal workflow: %w", err)
-	}
-
-	// Get nodes array
-	nodes, ok := convertedWorkflow["nodes"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("workflow does not contain nodes array")
-	}
-
-	// Find the first trigger node (usually manual trigger)
-	var firstTriggerNode map[string]interface{}
-
-	for _, node := range nodes {
-		nodeMap, ok := node.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		nodeType, ok := nodeMap["type"].(string)
-		if !ok {
-			continue
-		}
-
-		// Look for manual trigger or other trigger nodes
-		if strings.Contains(nodeType, "manualTrigger") ||
-			strings.Contains(nodeType, "trigger") ||
-			strings.Contains(nodeType, "webhook") {
-			firstTriggerNode = nodeMap
-			break
-		}
-	}
-
-	if firstTriggerNode == nil {
-		return nil, fmt.Errorf("no trigger node found in workflow")
-	}
-
-	// Create webhook node
-	webhookNode := map[string]interface{}{
-		"id":       "webhook-eval-trigger",
-		"name":     "Webhook Trigger",
-		"type":     "n8n-nodes-base.webhook",
-		"position": []interface{}{100, 300},
-		"parameters": map[string]interface{}{
-			"httpMethod":   "POST",
-			"path":         "evaluate",
-			"responseMode": "responseNode",
-			"options": map[string]interface{}{
-				"rawBody": true,
-			},
-		},
-		"typeVersion": 1,
-	}
-
-	// Create response node with proper configuration based on learnings
-	responseNode := map[string]interface{}{
-		"id":       "webhook-eval-response",
-		"name":     "Webhook Response",
-		"type":     "n8n-nodes-base.respondToWebhook",
-		"position": []interface{}{800, 300},
-		"parameters": map[string]interface{}{
-			"respondWith":  "json",
-			"responseBody": "={{ $json }}", // CRITICAL: Use actual workflow data, not hardcoded values
-			"options": map[string]interface{}{
-				"responseHeaders": map[string]interface{}{
-					"entries": []interface{}{
-						map[string]interface{}{
-							"name":  "Content-Type",
-							"value": "application/json",
-						},
-					},
-				},
-			},
-		},
-		"typeVersion": 1,
-	}
-
-	// Add webhook and response nodes to the workflow
-	nodes = append(nodes, webhookNode, responseNode)
-	convertedWorkflow["nodes"] = nodes
-
-	// Update connections to connect webhook to the original trigger's connections
-	connections, ok := convertedWorkflow["connections"].(map[string]interface{})
-	if !ok {
-		connections = make(map[string]interface{})
-		convertedWorkflow["connections"] = connections
-	}
-
-	// Get the original trigger node's connections
-	originalTriggerName, ok := firstTriggerNode["name"].(string)
-	if !ok {
-		originalTriggerName = "Execute workflow"
-	}
-
-	// Create webhook connection to the original trigger's first connection
-	if originalConnections, exists := connections[originalTriggerName]; exists {
-		connections["Webhook Trigger"] = originalConnections
-	}
-
-	// Find the last node in the workflow to connect to response
-	lastNodeName := findLastNode(nodes, connections)
-	if lastNodeName != "" {
-		// Connect last node to webhook response (avoiding circular connections)
-		responseConnections := map[string]interface{}{
-			"main": []interface{}{
-				[]interface{}{
-					map[string]interface{}{
-						"node":  "Webhook Response",
-						"type":  "main",
-						"index": 0,
-					},
-				},
-			},
-		}
-		connections[lastNodeName] = responseConnections
-		log.Printf("Connected final node '%s' to Webhook Response", lastNodeName)
-	} else {
-		log.Printf("Warning: Could not identify final node to connect to Webhook Response")
+	converted, err := convertWorkflowToWebhookGraph(workflowBytes, useCloudEvents, useStreaming, pricingTable)
+	if err != nil {
+		return nil, err
 	}
 
-	// Ensure Webhook Response has no outgoing connections (prevents circular references)
-	connections["Webhook Response"] = map[string]interface{}{
-		"main": []interface{}{[]interface{}{}},
+	var convertedWorkflow map[string]interface{}
+	if err := json.Unmarshal(converted, &convertedWorkflow); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal converted workflow: %w", err)
 	}
-
-	convertedWorkflow["connections"] = connections
-
 	return convertedWorkflow, nil
 }
 
-// findLastNode finds the last node in the workflow execution chain
-func findLastNode(nodes []interface{}, connections map[string]interface{}) string {
-	// Simple heuristic: find a node that has no outgoing connections
-	// or find the node that appears to be the final output node
-
-	nodeNames := make(map[string]bool)
-	connectedNodes := make(map[string]bool)
-
-	// Collect all node names
-	for _, node := range nodes {
-		if nodeMap, ok := node.(map[string]interface{}); ok {
-			if name, ok := nodeMap["name"].(string); ok {
-				nodeNames[name] = true
-			}
-		}
-	}
-
-	// Find nodes that are connected to by others
-	for _, connectionData := range connections {
-		if connectionMap, ok := connectionData.(map[string]interface{}); ok {
-			if mainConnections, ok := connectionMap["main"].([]interface{}); ok {
-				for _, mainConn := range mainConnections {
-					if mainConnArray, ok := mainConn.([]interface{}); ok {
-						for _, conn := range mainConnArray {
-							if connMap, ok := conn.(map[string]interface{}); ok {
-								if nodeName, ok := connMap["node"].(string); ok {
-									connectedNodes[nodeName] = true
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// Find nodes that are not connected to by others (potential end nodes)
-	for nodeName := range nodeNames {
-		if !connectedNodes[nodeName] &&
-			nodeName != "Webhook Trigger" &&
-			nodeName != "Webhook Response" {
-			// Look for nodes that seem to be output/final nodes
-			if strings.Contains(strings.ToLower(nodeName), "response") ||
-				strings.Contains(strings.ToLower(nodeName), "output") ||
-				strings.Contains(strings.ToLower(nodeName), "result") ||
-				strings.Contains(strings.ToLower(nodeName), "insert") ||
-				strings.Contains(strings.ToLower(nodeName), "save") ||
-				strings.Contains(strings.ToLower(nodeName), "merge") ||
-				strings.Contains(strings.ToLower(nodeName), "aggregate") {
-				return nodeName
-			}
-		}
-	}
-
-	// If no obvious end node found, return the last node that's not a trigger or webhook
-	for i := len(nodes) - 1; i >= 0; i-- {
-		if nodeMap, ok := nodes[i].(map[string]interface{}); ok {
-			if name, ok := nodeMap["name"].(string); ok {
-				lowerName := strings.ToLower(name)
-				if !strings.Contains(lowerName, "trigger") &&
-					!strings.Contains(lowerName, "execute") &&
-					!strings.Contains(lowerName, "webhook") {
-					return name
-				}
-			}
-		}
-	}
-
-	return ""
-}
-
-// extractJSONFromAIResponse extracts JSON from AI response, handling cases where AI includes extra text
-func extractJSONFromAIResponse(aiResponse string) (string, error) {
-	// Clean the response first
-	cleaned := strings.TrimSpace(aiResponse)
-
-	// Try to find JSON boundaries
-	jsonStart := strings.Index(cleaned, "{")
-	jsonEnd := strings.LastIndex(cleaned, "}")
-
-	if jsonStart == -1 || jsonEnd == -1 || jsonEnd <= jsonStart {
-		return "", fmt.Errorf("no valid JSON found in AI response: %s", cleaned[:min(len(cleaned), 200)])
-	}
-
-	jsonStr := cleaned[jsonStart : jsonEnd+1]
-
-	// Validate that it's valid JSON
-	var testJSON map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &testJSON); err != nil {
-		return "", fmt.Errorf("AI response contains invalid JSON: %w, response: %s", err, jsonStr[:min(len(jsonStr), 200)])
-	}
-
-	return jsonStr, nil
-}
-
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 // validateWebhookIntegration validates that the converted workflow has proper webhook integration
 func validateWebhookIntegration(workflow map[string]interface{}) error {
 	nodes, ok := workflow["nodes"].([]interface{})
@@ -0,0 +1,269 @@
+// Package graph models an n8n workflow as a typed graph of Node and
+// Connection values, so convert can add webhook instrumentation by walking
+// real in-degree/out-degree relationships instead of the name-substring
+// guessing convert.go used to rely on. Parse and Serialize round-trip any
+// workflow to equivalent JSON (Serialize sorts object keys and re-indents,
+// so the bytes can differ from the input): fields the graph package doesn't
+// know about (position, credentials, pinData, settings, ...) are kept as raw
+// json.RawMessage and written back unchanged.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// mainConnectionType is the n8n connection type used for ordinary data flow
+// between nodes, as opposed to LangChain-style sub-connections such as
+// "ai_tool" or "ai_languageModel".
+const mainConnectionType = "main"
+
+// Node is a single n8n workflow node. Name, Type and ID are pulled out
+// because every Transform needs them; every other field (parameters,
+// position, typeVersion, credentials, webhookId, ...) round-trips untouched
+// through raw.
+type Node struct {
+	ID   string
+	Name string
+	Type string
+
+	raw map[string]json.RawMessage
+}
+
+// NewNode builds a Node of the given n8n type, ready to have fields set via
+// SetField before being added to a Graph.
+func NewNode(id, name, nodeType string) *Node {
+	return &Node{ID: id, Name: name, Type: nodeType, raw: map[string]json.RawMessage{}}
+}
+
+// Field returns the raw JSON for an arbitrary node field, so a Transform can
+// read node-type-specific data (e.g. "parameters") without the graph package
+// knowing about every n8n node type.
+func (n Node) Field(key string) (json.RawMessage, bool) {
+	v, ok := n.raw[key]
+	return v, ok
+}
+
+// SetField overwrites or adds an arbitrary raw field on the node. value is
+// typically produced with json.Marshal.
+func (n *Node) SetField(key string, value json.RawMessage) {
+	if n.raw == nil {
+		n.raw = map[string]json.RawMessage{}
+	}
+	n.raw[key] = value
+}
+
+// SetFieldValue is a convenience wrapper around SetField that marshals v.
+func (n *Node) SetFieldValue(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal field %q for node %q: %w", key, n.Name, err)
+	}
+	n.SetField(key, data)
+	return nil
+}
+
+func (n *Node) UnmarshalJSON(data []byte) error {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("unmarshal node: %w", err)
+	}
+	if err := unmarshalField(raw, "name", &n.Name); err != nil {
+		return fmt.Errorf("unmarshal node name: %w", err)
+	}
+	if err := unmarshalField(raw, "type", &n.Type); err != nil {
+		return fmt.Errorf("unmarshal node type: %w", err)
+	}
+	_ = unmarshalField(raw, "id", &n.ID) // older workflows omit node ids
+	n.raw = raw
+	return nil
+}
+
+func (n Node) MarshalJSON() ([]byte, error) {
+	out := make(map[string]json.RawMessage, len(n.raw)+2)
+	for k, v := range n.raw {
+		out[k] = v
+	}
+	if err := setField(out, "name", n.Name); err != nil {
+		return nil, err
+	}
+	if err := setField(out, "type", n.Type); err != nil {
+		return nil, err
+	}
+	if n.ID != "" {
+		if err := setField(out, "id", n.ID); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(out)
+}
+
+func unmarshalField(raw map[string]json.RawMessage, key string, dst interface{}) error {
+	v, ok := raw[key]
+	if !ok {
+		return fmt.Errorf("missing field %q", key)
+	}
+	return json.Unmarshal(v, dst)
+}
+
+func setField(out map[string]json.RawMessage, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal field %q: %w", key, err)
+	}
+	out[key] = data
+	return nil
+}
+
+// Connection is a single edge endpoint: the target node, the n8n connection
+// type ("main" for data flow, "ai_tool"/"ai_languageModel" for
+// LangChain-style sub-connections), and the target node's input index.
+type Connection struct {
+	Node  string `json:"node"`
+	Type  string `json:"type"`
+	Index int    `json:"index"`
+}
+
+// Graph is a parsed n8n workflow: its nodes, their outgoing connections
+// keyed by source node name and n8n connection type, and every other
+// top-level workflow field preserved raw.
+type Graph struct {
+	Nodes []Node
+	// Connections maps sourceNodeName -> connectionType -> outputPortIndex
+	// -> the edges leaving that port, mirroring n8n's own connections JSON
+	// shape.
+	Connections map[string]map[string][][]Connection
+
+	raw map[string]json.RawMessage
+}
+
+// Parse reads an n8n workflow JSON document into a Graph.
+func Parse(data []byte) (*Graph, error) {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse workflow: %w", err)
+	}
+
+	g := &Graph{raw: raw, Connections: map[string]map[string][][]Connection{}}
+
+	if nodesRaw, ok := raw["nodes"]; ok {
+		if err := json.Unmarshal(nodesRaw, &g.Nodes); err != nil {
+			return nil, fmt.Errorf("parse workflow nodes: %w", err)
+		}
+	}
+
+	if connRaw, ok := raw["connections"]; ok {
+		if err := json.Unmarshal(connRaw, &g.Connections); err != nil {
+			return nil, fmt.Errorf("parse workflow connections: %w", err)
+		}
+	}
+
+	return g, nil
+}
+
+// Serialize writes the Graph back out as an n8n workflow JSON document,
+// preserving every raw top-level field untouched.
+func (g *Graph) Serialize() ([]byte, error) {
+	out := make(map[string]json.RawMessage, len(g.raw)+2)
+	for k, v := range g.raw {
+		out[k] = v
+	}
+
+	if err := setField(out, "nodes", g.Nodes); err != nil {
+		return nil, fmt.Errorf("serialize workflow nodes: %w", err)
+	}
+	if err := setField(out, "connections", g.Connections); err != nil {
+		return nil, fmt.Errorf("serialize workflow connections: %w", err)
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// Node looks up a node by name.
+func (g *Graph) Node(name string) (*Node, bool) {
+	for i := range g.Nodes {
+		if g.Nodes[i].Name == name {
+			return &g.Nodes[i], true
+		}
+	}
+	return nil, false
+}
+
+// AddNode appends a node to the graph. It does not connect it to anything.
+func (g *Graph) AddNode(n Node) {
+	g.Nodes = append(g.Nodes, n)
+}
+
+// Connect adds a "main" edge from (fromName, fromPort) to toName's input
+// toIndex, creating intermediate maps/slices as needed.
+func (g *Graph) Connect(fromName string, fromPort int, toName string, toIndex int) {
+	g.connect(fromName, mainConnectionType, fromPort, Connection{Node: toName, Type: mainConnectionType, Index: toIndex})
+}
+
+func (g *Graph) connect(fromName, connType string, fromPort int, conn Connection) {
+	if g.Connections == nil {
+		g.Connections = map[string]map[string][][]Connection{}
+	}
+	byType, ok := g.Connections[fromName]
+	if !ok {
+		byType = map[string][][]Connection{}
+		g.Connections[fromName] = byType
+	}
+	ports := byType[connType]
+	for len(ports) <= fromPort {
+		ports = append(ports, nil)
+	}
+	ports[fromPort] = append(ports[fromPort], conn)
+	byType[connType] = ports
+}
+
+// OutDegree returns the number of outgoing "main" edges from the node named
+// name, summed across all of its output ports.
+func (g *Graph) OutDegree(name string) int {
+	n := 0
+	for _, port := range g.Connections[name][mainConnectionType] {
+		n += len(port)
+	}
+	return n
+}
+
+// InDegree returns the number of incoming "main" edges into the node named
+// name, across every other node's connections.
+func (g *Graph) InDegree(name string) int {
+	n := 0
+	for _, byType := range g.Connections {
+		for _, port := range byType[mainConnectionType] {
+			for _, conn := range port {
+				if conn.Node == name {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+// Sources returns node names with no incoming "main" edge: the graph's true
+// entry points, computed from the connection map rather than guessed from a
+// node's name.
+func (g *Graph) Sources() []string {
+	var out []string
+	for _, n := range g.Nodes {
+		if g.InDegree(n.Name) == 0 {
+			out = append(out, n.Name)
+		}
+	}
+	return out
+}
+
+// Sinks returns node names with no outgoing "main" edge: nodes whose output
+// isn't consumed by anything else in the graph.
+func (g *Graph) Sinks() []string {
+	var out []string
+	for _, n := range g.Nodes {
+		if g.OutDegree(n.Name) == 0 {
+			out = append(out, n.Name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestConvertGoldenFiles runs the built-in webhook-instrumentation
+// transforms against every fixture in testdata/*.json and compares the
+// result against testdata/<name>.golden.json, so conversion stays
+// reproducible and diffable in CI. There's no n8n/ directory of real
+// exported workflows in this checkout, so the fixture here is a small
+// synthetic workflow rather than a real export.
+func TestConvertGoldenFiles(t *testing.T) {
+	fixtures, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatalf("glob testdata: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		if strings.HasSuffix(fixture, ".golden.json") {
+			continue
+		}
+		fixture := fixture
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			input, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+
+			g, err := Parse(input)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			if err := Apply(g,
+				InjectWebhookTrigger{},
+				InjectMetricsCalculator{},
+				InjectRespondToWebhook{},
+				RewireTriggerEdges{},
+			); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			got, err := g.Serialize()
+			if err != nil {
+				t.Fatalf("Serialize: %v", err)
+			}
+
+			goldenPath := strings.TrimSuffix(fixture, ".json") + ".golden.json"
+			if os.Getenv("UPDATE_GOLDEN") != "" {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("read golden (run with UPDATE_GOLDEN=1 to create it): %v", err)
+			}
+
+			if string(got) != string(want) {
+				t.Errorf("conversion of %s does not match golden file %s\ngot:\n%s", fixture, goldenPath, got)
+			}
+		})
+	}
+}
+
+// TestRoundTripPreservesUnknownFields checks that Parse/Serialize alone
+// (no transforms) decodes to the same JSON value as the input, so fields the
+// graph package doesn't model are never silently dropped.
+func TestRoundTripPreservesUnknownFields(t *testing.T) {
+	input, err := os.ReadFile("testdata/simple.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	g, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	out, err := g.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize: %v", err)
+	}
+
+	var gotNormalized, wantNormalized interface{}
+	if err := json.Unmarshal(out, &gotNormalized); err != nil {
+		t.Fatalf("unmarshal got: %v", err)
+	}
+	if err := json.Unmarshal(input, &wantNormalized); err != nil {
+		t.Fatalf("unmarshal want: %v", err)
+	}
+	if !reflect.DeepEqual(gotNormalized, wantNormalized) {
+		t.Errorf("round trip changed workflow contents:\ngot:  %#v\nwant: %#v", gotNormalized, wantNormalized)
+	}
+}
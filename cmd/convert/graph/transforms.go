@@ -0,0 +1,538 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"datasnack/cloudevents"
+	"datasnack/pricing"
+	"datasnack/schema"
+)
+
+// Transform mutates a Graph in place to add CLI-evaluation instrumentation.
+// Transforms are applied in sequence by the caller (see cmd.convertWorkflowToWebhookGraph),
+// so each one can assume the previous transforms in the pipeline already
+// ran.
+type Transform interface {
+	Name() string
+	Apply(g *Graph) error
+}
+
+// Apply runs each transform against g in order, stopping and returning the
+// first error encountered.
+func Apply(g *Graph, transforms ...Transform) error {
+	for _, t := range transforms {
+		if err := t.Apply(g); err != nil {
+			return fmt.Errorf("%s: %w", t.Name(), err)
+		}
+	}
+	return nil
+}
+
+// WebhookTriggerNodeName, MetricsCalculatorNodeName and
+// RespondToWebhookNodeName are the fixed node names the built-in transforms
+// use, so later transforms and evaluaten8n's webhook discovery can find them
+// by name.
+const (
+	WebhookTriggerNodeName    = "Webhook Trigger (CLI Evaluation)"
+	MetricsCalculatorNodeName = "Metrics Calculator (CLI Evaluation)"
+	RespondToWebhookNodeName  = "Webhook Response (CLI Evaluation)"
+)
+
+// InjectWebhookTrigger adds an n8n-nodes-base.webhook node that lets the
+// workflow be invoked over HTTP for evaluation. Path and Method default to
+// "evaluate" and POST.
+type InjectWebhookTrigger struct {
+	Path   string
+	Method string
+}
+
+func (t InjectWebhookTrigger) Name() string { return "InjectWebhookTrigger" }
+
+func (t InjectWebhookTrigger) Apply(g *Graph) error {
+	path := t.Path
+	if path == "" {
+		path = "evaluate"
+	}
+	method := t.Method
+	if method == "" {
+		method = "POST"
+	}
+
+	node := NewNode("webhook-trigger-cli", WebhookTriggerNodeName, "n8n-nodes-base.webhook")
+	if err := node.SetFieldValue("position", []int{-200, 300}); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("typeVersion", 1); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("parameters", map[string]interface{}{
+		"httpMethod":   method,
+		"path":         path,
+		"responseMode": "responseNode",
+		"options":      map[string]interface{}{},
+	}); err != nil {
+		return err
+	}
+	g.AddNode(*node)
+	return nil
+}
+
+// InjectMetricsCalculator adds a Code node that records timing and
+// content metrics about whatever the workflow produced, in the
+// standardized shape evaluaten8n expects from a webhook response.
+// Pricing is the $/1M-token table baked into the generated Code node for
+// cost_usd estimation. A nil Pricing falls back to pricing.Default().
+// Streaming, set by convert --streaming, additionally computes the
+// "streaming" metrics object from the Code node's per-chunk input items;
+// see metricsCalculatorJS.
+type InjectMetricsCalculator struct {
+	Pricing   pricing.Table
+	Streaming bool
+}
+
+func (t InjectMetricsCalculator) Name() string { return "InjectMetricsCalculator" }
+
+func (t InjectMetricsCalculator) Apply(g *Graph) error {
+	pricingTable := t.Pricing
+	if pricingTable == nil {
+		var err error
+		pricingTable, err = pricing.Default()
+		if err != nil {
+			return fmt.Errorf("InjectMetricsCalculator: failed to load default pricing table: %w", err)
+		}
+	}
+
+	jsCode, err := metricsCalculatorJS(len(g.Nodes), pricingTable, t.Streaming)
+	if err != nil {
+		return err
+	}
+
+	node := NewNode("metrics-calculator-cli", MetricsCalculatorNodeName, "n8n-nodes-base.code")
+	if err := node.SetFieldValue("position", []int{600, 300}); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("typeVersion", 2); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("parameters", map[string]interface{}{
+		"jsCode": jsCode,
+	}); err != nil {
+		return err
+	}
+	g.AddNode(*node)
+	return nil
+}
+
+// metricsCalculatorJS renders the Metrics Calculator Code node's body. The
+// emitted object follows schema.EvaluationResponseSchema() exactly, and the
+// node validates its own output against schema.RequiredPaths() before
+// returning, so a drifted response fails loudly at the webhook boundary
+// instead of surfacing as a silent evaluaten8n parse error downstream.
+// nodesInWorkflow is baked in at conversion time as the best available
+// proxy for workflow_metrics.nodes_executed: n8n's Code node has no API for
+// the executed subset of a running workflow, only the static node count.
+// pricingTable is embedded so the node can estimate token_usage/cost_usd
+// without a round trip back through Go; see pricing.Table. When streaming is
+// true, the node additionally treats its input as one item per streamed
+// chunk (each expected to carry "chunk" text and a "receivedAt" epoch-ms
+// timestamp; convert --streaming injects the plain webhook trigger, which
+// adds neither, so they come from the workflow's own nodes, and an item
+// without receivedAt is stamped with the time the node runs) and reports time-to-first-token/inter-token-latency/throughput as the
+// result's "streaming" object, reconstructing the full response from the
+// concatenated chunks instead of a single input item.
+func metricsCalculatorJS(nodesInWorkflow int, pricingTable pricing.Table, streaming bool) (string, error) {
+	requiredPaths, err := json.Marshal(schema.RequiredPaths(schema.EvaluationResponseSchema()))
+	if err != nil {
+		return "", fmt.Errorf("metricsCalculatorJS: failed to marshal required paths: %w", err)
+	}
+
+	pricingJSON, err := json.Marshal(pricingTable)
+	if err != nil {
+		return "", fmt.Errorf("metricsCalculatorJS: failed to marshal pricing table: %w", err)
+	}
+
+	return fmt.Sprintf(`// Calculate the standardized evaluation response for the CLI webhook. The
+// shape here must match schema.EvaluationResponseSchema() in the
+// datasnack/schema package; requiredPaths below is that schema's
+// RequiredPaths() output, embedded so this node can self-validate without a
+// round trip back through Go.
+const requiredPaths = %s;
+
+// pricingTable is provider -> model -> {input_per_million, output_per_million},
+// matching datasnack/pricing.Table; see pricing/models.yaml for the default
+// rates this was generated from.
+const pricingTable = %s;
+
+const inputData = $input.first().json;
+
+let responseContent = '';
+if (typeof inputData === 'string') {
+  responseContent = inputData;
+} else if (inputData.output !== undefined) {
+  responseContent = String(inputData.output);
+} else if (inputData.result !== undefined) {
+  responseContent = String(inputData.result);
+} else {
+  responseContent = JSON.stringify(inputData);
+}
+
+let query = '';
+try {
+  query = String($(%q).first().json.body.query || '');
+} catch (e) {
+  query = '';
+}
+
+const startedAt = $execution.startedAt ? new Date($execution.startedAt) : new Date();
+const totalTime = (Date.now() - startedAt.getTime()) / 1000;
+
+const provider = String(inputData.provider || 'unknown');
+const model = String(inputData.model || 'unknown');
+
+// Extract token counts from whichever provider-specific shape is present.
+// OpenAI nests them under usage.{prompt,completion,total}_tokens, Anthropic
+// under usage.{input,output}_tokens, and Ollama exposes them as top-level
+// prompt_eval_count/eval_count.
+let tokenUsage = null;
+if (inputData.usage && (inputData.usage.prompt_tokens !== undefined || inputData.usage.completion_tokens !== undefined)) {
+  const promptTokens = inputData.usage.prompt_tokens || 0;
+  const completionTokens = inputData.usage.completion_tokens || 0;
+  tokenUsage = {
+    prompt_tokens: promptTokens,
+    completion_tokens: completionTokens,
+    total_tokens: inputData.usage.total_tokens || (promptTokens + completionTokens),
+  };
+} else if (inputData.usage && (inputData.usage.input_tokens !== undefined || inputData.usage.output_tokens !== undefined)) {
+  const promptTokens = inputData.usage.input_tokens || 0;
+  const completionTokens = inputData.usage.output_tokens || 0;
+  tokenUsage = {
+    prompt_tokens: promptTokens,
+    completion_tokens: completionTokens,
+    total_tokens: promptTokens + completionTokens,
+  };
+} else if (inputData.prompt_eval_count !== undefined || inputData.eval_count !== undefined) {
+  const promptTokens = inputData.prompt_eval_count || 0;
+  const completionTokens = inputData.eval_count || 0;
+  tokenUsage = {
+    prompt_tokens: promptTokens,
+    completion_tokens: completionTokens,
+    total_tokens: promptTokens + completionTokens,
+  };
+}
+
+let costUsd = null;
+if (tokenUsage && pricingTable[provider] && pricingTable[provider][model]) {
+  const rate = pricingTable[provider][model];
+  costUsd = (tokenUsage.prompt_tokens * rate.input_per_million + tokenUsage.completion_tokens * rate.output_per_million) / 1000000;
+}
+
+// In streaming mode each input item is one streamed chunk rather than the
+// final aggregated response; reconstruct responseContent from them and
+// derive time-to-first-token/inter-token-latency/throughput instead of
+// relying on the single-item shape above.
+const streamingEnabled = %t;
+let streaming = null;
+if (streamingEnabled) {
+  const chunks = $input.all().map((item) => ({
+    text: String((item.json && item.json.chunk) || ''),
+    receivedAt: (item.json && item.json.receivedAt) ? Number(item.json.receivedAt) : Date.now(),
+  }));
+
+  if (chunks.length > 0) {
+    responseContent = chunks.map((c) => c.text).join('');
+
+    const gaps = [];
+    for (let i = 1; i < chunks.length; i++) {
+      gaps.push(chunks[i].receivedAt - chunks[i - 1].receivedAt);
+    }
+    const percentile = (values, p) => {
+      if (values.length === 0) {
+        return 0;
+      }
+      const sorted = [...values].sort((a, b) => a - b);
+      return sorted[Math.floor(p * (sorted.length - 1))];
+    };
+
+    const elapsedSeconds = (chunks[chunks.length - 1].receivedAt - startedAt.getTime()) / 1000;
+
+    streaming = {
+      ttft_ms: chunks[0].receivedAt - startedAt.getTime(),
+      tokens_per_second: elapsedSeconds > 0 ? chunks.length / elapsedSeconds : 0,
+      chunk_count: chunks.length,
+      p50_inter_token_ms: percentile(gaps, 0.50),
+      p95_inter_token_ms: percentile(gaps, 0.95),
+      total_bytes: chunks.reduce((sum, c) => sum + c.text.length, 0),
+    };
+  }
+}
+
+const result = {
+  success: true,
+  query: query,
+  response: responseContent,
+  metrics: {
+    response_time: totalTime,
+    total_time: totalTime,
+    response_length: responseContent.length,
+    word_count: responseContent.split(/\s+/).filter(Boolean).length,
+    character_count: responseContent.length,
+    has_content: responseContent.length > 0,
+    timestamp: new Date().toISOString(),
+  },
+  provider_info: {
+    provider: provider,
+    model: model,
+    temperature: String(inputData.temperature || 'unknown'),
+    reasoning_effort: String(inputData.reasoning_effort || 'unknown'),
+  },
+  timing: {
+    response_time: totalTime,
+    total_time: totalTime,
+  },
+  error: null,
+  token_usage: tokenUsage,
+  cost_usd: costUsd,
+  streaming: streaming,
+  workflow_metrics: {
+    workflow_name: $workflow.name,
+    nodes_executed: %d,
+    custom_metrics: {},
+  },
+};
+
+function getPath(obj, path) {
+  return path.split('.').reduce((acc, key) => (acc && typeof acc === 'object') ? acc[key] : undefined, obj);
+}
+
+const missing = requiredPaths.filter((path) => getPath(result, path) === undefined);
+if (missing.length > 0) {
+  result.success = false;
+  result.error = 'Metrics Calculator: missing required field(s): ' + missing.join(', ');
+}
+
+return { json: result };
+`, requiredPaths, pricingJSON, WebhookTriggerNodeName, streaming, nodesInWorkflow), nil
+}
+
+// InjectRespondToWebhook adds an n8n-nodes-base.respondToWebhook node that
+// returns the Metrics Calculator's output as the HTTP response body.
+type InjectRespondToWebhook struct{}
+
+func (t InjectRespondToWebhook) Name() string { return "InjectRespondToWebhook" }
+
+func (t InjectRespondToWebhook) Apply(g *Graph) error {
+	node := NewNode("webhook-response-cli", RespondToWebhookNodeName, "n8n-nodes-base.respondToWebhook")
+	if err := node.SetFieldValue("position", []int{800, 300}); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("typeVersion", 1); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("parameters", map[string]interface{}{
+		"respondWith":  "json",
+		"responseBody": "={{ $json }}",
+		"options": map[string]interface{}{
+			"responseHeaders": map[string]interface{}{
+				"entries": []interface{}{
+					map[string]interface{}{"name": "Content-Type", "value": "application/json"},
+				},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+	g.AddNode(*node)
+	return nil
+}
+
+// InjectStreamingRespondToWebhook is the --streaming variant of
+// InjectRespondToWebhook. It responds with a text body under a
+// text/event-stream Content-Type header, formatting the Metrics Calculator's output as a single
+// "data: <json>\n\ndata: [DONE]\n\n" SSE event so a CLI client using
+// datasnack/stream.Get can consume it the same way it would a chat-trigger
+// workflow's token-by-token stream. The Metrics Calculator still runs first
+// in streaming mode (see InjectMetricsCalculator.Streaming) and its
+// "streaming" metrics reflect the upstream chunk timing, not this node's own
+// single SSE event.
+type InjectStreamingRespondToWebhook struct{}
+
+func (t InjectStreamingRespondToWebhook) Name() string { return "InjectStreamingRespondToWebhook" }
+
+func (t InjectStreamingRespondToWebhook) Apply(g *Graph) error {
+	node := NewNode("webhook-response-cli", RespondToWebhookNodeName, "n8n-nodes-base.respondToWebhook")
+	if err := node.SetFieldValue("position", []int{800, 300}); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("typeVersion", 1); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("parameters", map[string]interface{}{
+		"respondWith":  "text",
+		"responseBody": "={{ 'data: ' + JSON.stringify($json) + '\\n\\ndata: [DONE]\\n\\n' }}",
+		"options": map[string]interface{}{
+			"responseHeaders": map[string]interface{}{
+				"entries": []interface{}{
+					map[string]interface{}{"name": "Content-Type", "value": "text/event-stream"},
+					map[string]interface{}{"name": "Cache-Control", "value": "no-cache"},
+				},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+	g.AddNode(*node)
+	return nil
+}
+
+// InjectCloudEventsWebhookTrigger is the --cloudevents variant of
+// InjectWebhookTrigger. n8n's webhook node already exposes every inbound
+// header on $json.headers, so no extra parameters are needed to receive a
+// binary-mode CloudEvent's "ce-*" headers; rawBody is turned on so that a
+// structured-mode CloudEvent (Content-Type: application/cloudevents+json,
+// whose actual payload is nested under its JSON body's "data" field) is
+// available to downstream nodes unparsed rather than silently misread as
+// the evaluation payload itself.
+type InjectCloudEventsWebhookTrigger struct {
+	Path   string
+	Method string
+}
+
+func (t InjectCloudEventsWebhookTrigger) Name() string { return "InjectCloudEventsWebhookTrigger" }
+
+func (t InjectCloudEventsWebhookTrigger) Apply(g *Graph) error {
+	path := t.Path
+	if path == "" {
+		path = "evaluate"
+	}
+	method := t.Method
+	if method == "" {
+		method = "POST"
+	}
+
+	node := NewNode("webhook-trigger-cli", WebhookTriggerNodeName, "n8n-nodes-base.webhook")
+	if err := node.SetFieldValue("position", []int{-200, 300}); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("typeVersion", 1); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("parameters", map[string]interface{}{
+		"httpMethod":   method,
+		"path":         path,
+		"responseMode": "responseNode",
+		"options": map[string]interface{}{
+			"rawBody": true,
+		},
+	}); err != nil {
+		return err
+	}
+	g.AddNode(*node)
+	return nil
+}
+
+// InjectCloudEventsRespondToWebhook is the --cloudevents variant of
+// InjectRespondToWebhook. It returns the same EvaluationResponseSchema body
+// as the response payload (binary content mode, so evaluaten8n's existing
+// HTTP client keeps working unchanged), alongside the CloudEvents v1.0
+// attributes as "ce-*" headers identifying it as an
+// cloudevents.EventTypeEvaluateResponse event. Structured-mode responses are
+// the cloudevents package's concern (see Send/MarshalStructured), not this
+// node's.
+type InjectCloudEventsRespondToWebhook struct {
+	Source string
+}
+
+func (t InjectCloudEventsRespondToWebhook) Name() string {
+	return "InjectCloudEventsRespondToWebhook"
+}
+
+func (t InjectCloudEventsRespondToWebhook) Apply(g *Graph) error {
+	source := t.Source
+	if source == "" {
+		source = "urn:n8n:workflow"
+	}
+
+	node := NewNode("webhook-response-cli", RespondToWebhookNodeName, "n8n-nodes-base.respondToWebhook")
+	if err := node.SetFieldValue("position", []int{800, 300}); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("typeVersion", 1); err != nil {
+		return err
+	}
+	if err := node.SetFieldValue("parameters", map[string]interface{}{
+		"respondWith":  "json",
+		"responseBody": "={{ $json }}",
+		"options": map[string]interface{}{
+			"responseHeaders": map[string]interface{}{
+				"entries": []interface{}{
+					map[string]interface{}{"name": "Content-Type", "value": "application/json"},
+					map[string]interface{}{"name": "ce-specversion", "value": cloudevents.SpecVersion},
+					map[string]interface{}{"name": "ce-type", "value": cloudevents.EventTypeEvaluateResponse},
+					map[string]interface{}{"name": "ce-source", "value": fmt.Sprintf("={{ %q + $workflow.name }}", source+":")},
+					map[string]interface{}{"name": "ce-id", "value": "={{ $now.toMillis() + '-' + $runIndex }}"},
+					map[string]interface{}{"name": "ce-time", "value": "={{ $now.toISO() }}"},
+				},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+	g.AddNode(*node)
+	return nil
+}
+
+// RewireTriggerEdges rewires the graph so the injected webhook takes over
+// the workflow's original trigger, and every existing sink feeds the
+// injected Metrics Calculator, which in turn feeds the injected webhook
+// response. It computes the original trigger and sinks from in-degree and
+// out-degree over the connection map, replacing the fragile
+// "contains 'response'/'output'/'insert'" name heuristics findLastNode used
+// to rely on.
+//
+// RewireTriggerEdges must run after the three Inject* transforms, since it
+// needs WebhookTriggerNodeName, MetricsCalculatorNodeName and
+// RespondToWebhookNodeName to already be in the graph.
+type RewireTriggerEdges struct{}
+
+func (t RewireTriggerEdges) Name() string { return "RewireTriggerEdges" }
+
+func (t RewireTriggerEdges) Apply(g *Graph) error {
+	injected := map[string]bool{
+		WebhookTriggerNodeName:    true,
+		MetricsCalculatorNodeName: true,
+		RespondToWebhookNodeName:  true,
+	}
+
+	var originalTrigger string
+	for _, name := range g.Sources() {
+		if !injected[name] {
+			originalTrigger = name
+			break
+		}
+	}
+	if originalTrigger == "" {
+		return fmt.Errorf("could not identify the workflow's original trigger node")
+	}
+
+	if byType, ok := g.Connections[originalTrigger]; ok {
+		g.Connections[WebhookTriggerNodeName] = byType
+	}
+	delete(g.Connections, originalTrigger)
+
+	sinkCount := 0
+	for _, name := range g.Sinks() {
+		if injected[name] {
+			continue
+		}
+		g.Connect(name, 0, MetricsCalculatorNodeName, 0)
+		sinkCount++
+	}
+	if sinkCount == 0 {
+		return fmt.Errorf("could not identify any sink node to feed into %s", MetricsCalculatorNodeName)
+	}
+
+	g.Connect(MetricsCalculatorNodeName, 0, RespondToWebhookNodeName, 0)
+
+	return nil
+}
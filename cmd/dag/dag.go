@@ -0,0 +1,173 @@
+// Package dag implements Argo-style DAG evaluation of n8n workflows: an
+// evalset.yaml manifest of tasks bound by dependsOn, scheduled so
+// independent workflows run concurrently and downstream tasks can reference
+// upstream outputs via "{{tasks.<name>.outputs.<field>}}" argument
+// expressions. A single `ai-evaluator evaluateset` run can therefore chain
+// several converted n8n workflows (e.g. "summarize" feeding "triage") the
+// same way an Argo Workflow chains its DAG templates.
+package dag
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Task is one node in the DAG, as loaded from evalset.yaml.
+type Task struct {
+	// Name identifies the task within the DAG and is how other tasks'
+	// dependsOn/Arguments expressions refer to it.
+	Name string `yaml:"name"`
+	// Workflow is the path to a converted (webhook-enabled) n8n workflow
+	// JSON file, as produced by `ai-evaluator convert`.
+	Workflow string `yaml:"workflow"`
+	// DependsOn lists task names that must complete before this one is
+	// dispatched.
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// Arguments is the webhook payload to POST, with values optionally
+	// containing "{{tasks.<name>.outputs.<field>}}" expressions resolved
+	// against completed dependencies before dispatch.
+	Arguments map[string]string `yaml:"arguments,omitempty"`
+	// ContinueOn lists outcome statuses ("failed") of THIS task that
+	// should not block its dependents from running. By default a failed
+	// task skips every task that (transitively) depends on it.
+	ContinueOn []string `yaml:"continueOn,omitempty"`
+	// Retries is how many additional attempts to make after an initial
+	// failure, before the task is considered Failed. Zero means no retries.
+	Retries int `yaml:"retries,omitempty"`
+}
+
+// continuesOnFailure reports whether t's dependents should still run after
+// t itself fails.
+func (t Task) continuesOnFailure() bool {
+	for _, s := range t.ContinueOn {
+		if s == "failed" {
+			return true
+		}
+	}
+	return false
+}
+
+// DAG is a parsed evalset.yaml: the tasks to run and, optionally, which
+// leaf tasks must succeed for the overall run to be considered successful.
+type DAG struct {
+	Tasks []Task `yaml:"tasks"`
+	// Target names the tasks that must reach status Succeeded for the DAG
+	// run to be considered successful overall. Empty means every task must
+	// succeed.
+	Target []string `yaml:"target,omitempty"`
+}
+
+// Load reads and parses an evalset.yaml manifest from path.
+func Load(path string) (*DAG, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dag: failed to read %s: %w", path, err)
+	}
+
+	var d DAG
+	if err := yaml.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("dag: failed to parse %s: %w", path, err)
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Validate checks that every dependsOn/target name refers to a task
+// actually defined in the DAG, that task names are unique, and that the
+// dependency graph is acyclic.
+func (d *DAG) Validate() error {
+	seen := make(map[string]bool, len(d.Tasks))
+	for _, t := range d.Tasks {
+		if t.Name == "" {
+			return fmt.Errorf("dag: task with empty name")
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("dag: duplicate task name %q", t.Name)
+		}
+		seen[t.Name] = true
+	}
+	for _, t := range d.Tasks {
+		for _, dep := range t.DependsOn {
+			if !seen[dep] {
+				return fmt.Errorf("dag: task %q depends on undefined task %q", t.Name, dep)
+			}
+		}
+	}
+	for _, target := range d.Target {
+		if !seen[target] {
+			return fmt.Errorf("dag: target %q is not a defined task", target)
+		}
+	}
+
+	if _, err := d.TopoSort(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TopoSort returns the DAG's tasks in a valid execution order (every task
+// appears after everything it depends on), computed with Kahn's algorithm.
+// It returns an error naming a task still stuck in the graph if the
+// dependency graph is not acyclic.
+func (d *DAG) TopoSort() ([]string, error) {
+	byName := make(map[string]Task, len(d.Tasks))
+	inDegree := make(map[string]int, len(d.Tasks))
+	dependents := make(map[string][]string, len(d.Tasks))
+
+	for _, t := range d.Tasks {
+		byName[t.Name] = t
+		if _, ok := inDegree[t.Name]; !ok {
+			inDegree[t.Name] = 0
+		}
+		for _, dep := range t.DependsOn {
+			inDegree[t.Name]++
+			dependents[dep] = append(dependents[dep], t.Name)
+		}
+	}
+
+	var ready []string
+	for _, t := range d.Tasks {
+		if inDegree[t.Name] == 0 {
+			ready = append(ready, t.Name)
+		}
+	}
+
+	var order []string
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(d.Tasks) {
+		var stuck []string
+		for name, degree := range inDegree {
+			if degree > 0 {
+				stuck = append(stuck, name)
+			}
+		}
+		return nil, fmt.Errorf("dag: dependency graph has a cycle involving: %v", stuck)
+	}
+	return order, nil
+}
+
+// task looks up a task by name.
+func (d *DAG) task(name string) (Task, bool) {
+	for _, t := range d.Tasks {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Task{}, false
+}
@@ -0,0 +1,68 @@
+package dag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"datasnack/cloneAttack"
+	"datasnack/workflow"
+)
+
+// WebhookInvoker dispatches each Task to the webhook trigger found in its
+// converted workflow file, the same way N8nWorkflowEvaluator locates one via
+// extractWebhookURL, POSTing through an HTTPWebhookTransport against
+// BaseURL + the trigger's path.
+type WebhookInvoker struct {
+	// BaseURL is the n8n instance's webhook base, e.g.
+	// "http://localhost:5678/webhook-test".
+	BaseURL string
+}
+
+// Invoke implements Invoker.
+func (w WebhookInvoker) Invoke(ctx context.Context, t Task, arguments map[string]string) (string, map[string]interface{}, error) {
+	data, err := os.ReadFile(t.Workflow)
+	if err != nil {
+		return "", nil, fmt.Errorf("dag: failed to read workflow %s: %w", t.Workflow, err)
+	}
+
+	g, err := workflow.Parse(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("dag: failed to parse workflow %s: %w", t.Workflow, err)
+	}
+
+	var path string
+	for _, n := range g.Triggers() {
+		if n.Type != "n8n-nodes-base.webhook" {
+			continue
+		}
+		if p, ok := n.Parameters["path"].(string); ok {
+			path = p
+			break
+		}
+	}
+	if path == "" {
+		return "", nil, fmt.Errorf("dag: no webhook trigger found in workflow %s", t.Workflow)
+	}
+
+	payload := make(map[string]interface{}, len(arguments))
+	for k, v := range arguments {
+		payload[k] = v
+	}
+
+	transport := cloneAttack.NewHTTPWebhookTransport(fmt.Sprintf("%s/%s", w.BaseURL, path), "", "", false)
+	resp, err := transport.Invoke(ctx, payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("dag: failed to invoke task %q: %w", t.Name, err)
+	}
+
+	var outputs map[string]interface{}
+	if err := json.Unmarshal([]byte(resp.Body), &outputs); err != nil {
+		// Non-JSON responses are still usable as a plain-text output.
+		return resp.Body, nil, nil
+	}
+
+	response, _ := outputs["response"].(string)
+	return response, outputs, nil
+}
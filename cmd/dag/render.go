@@ -0,0 +1,78 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// statusColor is the fill color used for a task's node in both renderings,
+// so a glance at the diagram shows which tasks failed or were skipped.
+func statusColor(s Status) string {
+	switch s {
+	case StatusSucceeded:
+		return "#b7e4c7" // green
+	case StatusFailed:
+		return "#f1948a" // red
+	case StatusSkipped:
+		return "#d5d8dc" // gray
+	default:
+		return "#fef9e7" // pale yellow, e.g. never reached (shouldn't happen)
+	}
+}
+
+// Mermaid renders r as a Mermaid flowchart, coloring each task node by its
+// final Status, for embedding directly in a Markdown CI summary.
+func (r *Report) Mermaid() string {
+	byName := make(map[string]Outcome, len(r.Outcomes))
+	for _, o := range r.Outcomes {
+		byName[o.Task] = o
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, t := range r.DAG.Tasks {
+		b.WriteString(fmt.Sprintf("    %s[%q]\n", mermaidID(t.Name), t.Name))
+		for _, dep := range t.DependsOn {
+			b.WriteString(fmt.Sprintf("    %s --> %s\n", mermaidID(dep), mermaidID(t.Name)))
+		}
+	}
+	for _, t := range r.DAG.Tasks {
+		b.WriteString(fmt.Sprintf("    style %s fill:%s\n", mermaidID(t.Name), statusColor(byName[t.Name].Status)))
+	}
+	return b.String()
+}
+
+// mermaidID sanitizes a task name into a valid Mermaid node identifier.
+func mermaidID(name string) string {
+	replacer := strings.NewReplacer(" ", "_", "-", "_", ".", "_")
+	return "task_" + replacer.Replace(name)
+}
+
+// Graphviz renders r as a Graphviz DOT digraph, coloring each task node by
+// its final Status.
+func (r *Report) Graphviz() string {
+	byName := make(map[string]Outcome, len(r.Outcomes))
+	for _, o := range r.Outcomes {
+		byName[o.Task] = o
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dag {\n")
+	names := make([]string, 0, len(r.DAG.Tasks))
+	for _, t := range r.DAG.Tasks {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("  %q [style=filled, fillcolor=%q];\n", name, statusColor(byName[name].Status)))
+	}
+	for _, t := range r.DAG.Tasks {
+		for _, dep := range t.DependsOn {
+			b.WriteString(fmt.Sprintf("  %q -> %q;\n", dep, t.Name))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,269 @@
+package dag
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the terminal state of one executed Task.
+type Status string
+
+const (
+	// StatusSucceeded means the task's Invoker call returned without error.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed means every attempt (including retries) returned an error.
+	StatusFailed Status = "failed"
+	// StatusSkipped means a dependency failed (or was itself skipped)
+	// without "continueOn: [failed]", so this task never ran.
+	StatusSkipped Status = "skipped"
+)
+
+// Outcome is the terminal result of one Task in a Scheduler.Run.
+type Outcome struct {
+	Task     string
+	Status   Status
+	Attempts int
+	Start    time.Time
+	End      time.Time
+	Response string
+	Outputs  map[string]interface{}
+	Err      error
+}
+
+// Duration is how long the task took across all of its attempts (zero for
+// skipped tasks).
+func (o Outcome) Duration() time.Duration { return o.End.Sub(o.Start) }
+
+// Invoker dispatches a single Task's resolved arguments to its converted
+// workflow and reports back the response text plus any structured fields
+// the workflow returned, so downstream tasks'
+// "{{tasks.<name>.outputs.<field>}}" expressions can reference them.
+type Invoker interface {
+	Invoke(ctx context.Context, t Task, arguments map[string]string) (response string, outputs map[string]interface{}, err error)
+}
+
+// Scheduler executes a DAG's tasks, dispatching every task whose
+// dependencies have resolved concurrently rather than one at a time.
+type Scheduler struct {
+	DAG    *DAG
+	Invoke Invoker
+}
+
+// Report is the aggregate result of a Scheduler.Run: every task's Outcome,
+// and whether the run met the DAG's Target (or, absent a Target, whether
+// every task succeeded).
+type Report struct {
+	DAG      *DAG
+	Outcomes []Outcome
+	Success  bool
+}
+
+var outputExprPattern = regexp.MustCompile(`\{\{\s*tasks\.([A-Za-z0-9_-]+)\.outputs\.([A-Za-z0-9_.]+)\s*\}\}`)
+
+// Run schedules and executes every task in s.DAG, returning once all tasks
+// have reached a terminal status (succeeded, failed or skipped).
+func (s *Scheduler) Run(ctx context.Context) (*Report, error) {
+	if _, err := s.DAG.TopoSort(); err != nil {
+		return nil, err
+	}
+
+	inDegree := make(map[string]int, len(s.DAG.Tasks))
+	dependents := make(map[string][]string, len(s.DAG.Tasks))
+	for _, t := range s.DAG.Tasks {
+		if _, ok := inDegree[t.Name]; !ok {
+			inDegree[t.Name] = 0
+		}
+		for _, dep := range t.DependsOn {
+			inDegree[t.Name]++
+			dependents[dep] = append(dependents[dep], t.Name)
+		}
+	}
+
+	var mu sync.Mutex
+	outcomes := make(map[string]Outcome, len(s.DAG.Tasks))
+
+	completions := make(chan Outcome)
+	var queue []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	pending := len(s.DAG.Tasks)
+	for pending > 0 {
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			t, _ := s.DAG.task(name)
+
+			if blocked, blockedBy := s.blockedByDependency(t, outcomes); blocked {
+				outcome := Outcome{Task: name, Status: StatusSkipped, Start: time.Now(), End: time.Now(),
+					Err: fmt.Errorf("dag: skipped because dependency %q did not succeed", blockedBy)}
+				mu.Lock()
+				outcomes[name] = outcome
+				mu.Unlock()
+				pending--
+				queue = append(queue, s.resolveReady(name, dependents, inDegree)...)
+				continue
+			}
+
+			go func(t Task) {
+				mu.Lock()
+				arguments, err := resolveArguments(t.Arguments, outcomes)
+				mu.Unlock()
+				if err != nil {
+					completions <- Outcome{Task: t.Name, Status: StatusFailed, Start: time.Now(), End: time.Now(), Err: err}
+					return
+				}
+				completions <- s.runTask(ctx, t, arguments)
+			}(t)
+		}
+
+		if pending == 0 {
+			break
+		}
+
+		outcome := <-completions
+		mu.Lock()
+		outcomes[outcome.Task] = outcome
+		mu.Unlock()
+		pending--
+		queue = append(queue, s.resolveReady(outcome.Task, dependents, inDegree)...)
+	}
+
+	report := &Report{DAG: s.DAG}
+	for _, t := range s.DAG.Tasks {
+		report.Outcomes = append(report.Outcomes, outcomes[t.Name])
+	}
+	report.Success = s.evaluateSuccess(outcomes)
+	return report, nil
+}
+
+// resolveReady decrements the in-degree of finishedTask's dependents,
+// returning the ones that just reached zero (i.e. are now ready to be
+// considered, whether that means dispatching them or skipping them).
+func (s *Scheduler) resolveReady(finishedTask string, dependents map[string][]string, inDegree map[string]int) []string {
+	var ready []string
+	for _, dependent := range dependents[finishedTask] {
+		inDegree[dependent]--
+		if inDegree[dependent] == 0 {
+			ready = append(ready, dependent)
+		}
+	}
+	return ready
+}
+
+// blockedByDependency reports whether t must be skipped because one of its
+// dependencies ended in a status that doesn't satisfy t's dependency
+// (failed or skipped, without that dependency declaring continueOn: [failed]).
+func (s *Scheduler) blockedByDependency(t Task, outcomes map[string]Outcome) (bool, string) {
+	for _, dep := range t.DependsOn {
+		outcome := outcomes[dep]
+		if outcome.Status == StatusSucceeded {
+			continue
+		}
+		depTask, _ := s.DAG.task(dep)
+		if outcome.Status == StatusFailed && depTask.continuesOnFailure() {
+			continue
+		}
+		return true, dep
+	}
+	return false, ""
+}
+
+// runTask invokes t, retrying up to t.Retries additional times on error.
+func (s *Scheduler) runTask(ctx context.Context, t Task, arguments map[string]string) Outcome {
+	outcome := Outcome{Task: t.Name, Start: time.Now()}
+
+	attempts := t.Retries + 1
+	for attempt := 1; attempt <= attempts; attempt++ {
+		outcome.Attempts = attempt
+		response, outputs, err := s.Invoke.Invoke(ctx, t, arguments)
+		if err == nil {
+			outcome.Status = StatusSucceeded
+			outcome.Response = response
+			outcome.Outputs = outputs
+			outcome.End = time.Now()
+			return outcome
+		}
+		outcome.Err = err
+	}
+
+	outcome.Status = StatusFailed
+	outcome.End = time.Now()
+	return outcome
+}
+
+// evaluateSuccess applies the DAG's Target semantics: if Target is set, the
+// run succeeded only if every named target task succeeded; otherwise every
+// task must have succeeded.
+func (s *Scheduler) evaluateSuccess(outcomes map[string]Outcome) bool {
+	targets := s.DAG.Target
+	if len(targets) == 0 {
+		for _, t := range s.DAG.Tasks {
+			targets = append(targets, t.Name)
+		}
+	}
+	for _, name := range targets {
+		if outcomes[name].Status != StatusSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveArguments replaces every "{{tasks.<name>.outputs.<field>}}"
+// expression in arguments with the referenced completed task's output,
+// looking up "response" directly and anything else as a dotted path into
+// that task's structured Outputs.
+func resolveArguments(arguments map[string]string, outcomes map[string]Outcome) (map[string]string, error) {
+	resolved := make(map[string]string, len(arguments))
+	var resolveErr error
+	for key, value := range arguments {
+		resolved[key] = outputExprPattern.ReplaceAllStringFunc(value, func(expr string) string {
+			m := outputExprPattern.FindStringSubmatch(expr)
+			taskName, field := m[1], m[2]
+
+			outcome, ok := outcomes[taskName]
+			if !ok || outcome.Status != StatusSucceeded {
+				resolveErr = fmt.Errorf("dag: argument %q references task %q, which has not succeeded", key, taskName)
+				return expr
+			}
+			if field == "response" {
+				return outcome.Response
+			}
+			v, ok := resolveOutputPath(outcome.Outputs, field)
+			if !ok {
+				resolveErr = fmt.Errorf("dag: argument %q references unknown output %q.%s", key, taskName, field)
+				return expr
+			}
+			return fmt.Sprintf("%v", v)
+		})
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+	}
+	return resolved, nil
+}
+
+// resolveOutputPath resolves a dotted path (e.g. "metrics.word_count")
+// against a task's JSON-decoded Outputs map.
+func resolveOutputPath(outputs map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = outputs
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,131 @@
+package dag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fakeInvoker is a test Invoker that records which tasks ran and fails
+// every task named "b", to exercise retries and continueOn.
+type fakeInvoker struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeInvoker) Invoke(ctx context.Context, t Task, arguments map[string]string) (string, map[string]interface{}, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, t.Name)
+	f.mu.Unlock()
+
+	if t.Name == "b" {
+		return "", nil, fmt.Errorf("b always fails")
+	}
+	return "resp-from-" + t.Name, map[string]interface{}{
+		"metrics": map[string]interface{}{"word_count": 3},
+	}, nil
+}
+
+func TestSchedulerRunRespectsDependenciesContinueOnAndTarget(t *testing.T) {
+	d := &DAG{
+		Tasks: []Task{
+			{Name: "a", Workflow: "a.json"},
+			{
+				Name: "b", Workflow: "b.json", DependsOn: []string{"a"},
+				ContinueOn: []string{"failed"}, Retries: 1,
+				Arguments: map[string]string{"query": "{{tasks.a.outputs.response}}"},
+			},
+			{Name: "c", Workflow: "c.json", DependsOn: []string{"b"}},
+			{
+				Name: "d", Workflow: "d.json", DependsOn: []string{"a"},
+				Arguments: map[string]string{"words": "{{tasks.a.outputs.metrics.word_count}}"},
+			},
+		},
+		Target: []string{"d"},
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	inv := &fakeInvoker{}
+	s := &Scheduler{DAG: d, Invoke: inv}
+	report, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	byName := make(map[string]Outcome, len(report.Outcomes))
+	for _, o := range report.Outcomes {
+		byName[o.Task] = o
+	}
+
+	if byName["a"].Status != StatusSucceeded {
+		t.Errorf("a: got status %s, want succeeded", byName["a"].Status)
+	}
+	if byName["b"].Status != StatusFailed || byName["b"].Attempts != 2 {
+		t.Errorf("b: got status %s with %d attempts, want failed after 2 attempts (1 retry)", byName["b"].Status, byName["b"].Attempts)
+	}
+	if byName["c"].Status != StatusSucceeded {
+		t.Errorf("c: got status %s, want succeeded (b declares continueOn: [failed])", byName["c"].Status)
+	}
+	if byName["d"].Status != StatusSucceeded {
+		t.Errorf("d: got status %s, want succeeded", byName["d"].Status)
+	}
+	if !report.Success {
+		t.Errorf("report.Success = false, want true: only target task %q must succeed", d.Target)
+	}
+}
+
+func TestSchedulerSkipsDependentsOfAFailureWithoutContinueOn(t *testing.T) {
+	d := &DAG{
+		Tasks: []Task{
+			{Name: "a", Workflow: "a.json"},
+			{Name: "b", Workflow: "b.json", DependsOn: []string{"a"}},
+			{Name: "c", Workflow: "c.json", DependsOn: []string{"b"}},
+		},
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	s := &Scheduler{DAG: d, Invoke: &fakeInvoker{}}
+	report, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	byName := make(map[string]Outcome, len(report.Outcomes))
+	for _, o := range report.Outcomes {
+		byName[o.Task] = o
+	}
+	if byName["c"].Status != StatusSkipped {
+		t.Errorf("c: got status %s, want skipped (b failed without continueOn)", byName["c"].Status)
+	}
+	if report.Success {
+		t.Errorf("report.Success = true, want false: c never succeeded and no target narrows the requirement")
+	}
+}
+
+func TestDAGValidateRejectsCycles(t *testing.T) {
+	d := &DAG{
+		Tasks: []Task{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a"}},
+		},
+	}
+	if err := d.Validate(); err == nil {
+		t.Fatal("Validate: expected an error for a cyclic dependency graph")
+	}
+}
+
+func TestDAGValidateRejectsUnknownDependency(t *testing.T) {
+	d := &DAG{
+		Tasks: []Task{
+			{Name: "a", DependsOn: []string{"missing"}},
+		},
+	}
+	if err := d.Validate(); err == nil {
+		t.Fatal("Validate: expected an error for a dependsOn referencing an undefined task")
+	}
+}
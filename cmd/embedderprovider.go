@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"datasnack/cloneAttack"
+)
+
+// initializeEmbedder builds a cloneAttack.Embedder from EMBEDDER_PROVIDER
+// ("openai", "ollama", or "local"; defaults to "openai"), mirroring
+// initializeAIClient's environment-variable driven provider selection.
+// EMBEDDER_MODEL and EMBEDDER_BASE_URL override each provider's default
+// model/endpoint; EMBEDDER_API_KEY is only read for "openai", and
+// EMBEDDER_SCRIPT_PATH is required for "local".
+func initializeEmbedder() (cloneAttack.Embedder, error) {
+	provider := os.Getenv("EMBEDDER_PROVIDER")
+	if provider == "" {
+		provider = "openai"
+	}
+	model := os.Getenv("EMBEDDER_MODEL")
+	baseURL := os.Getenv("EMBEDDER_BASE_URL")
+
+	switch provider {
+	case "openai":
+		embedder := cloneAttack.NewOpenAIEmbedder(os.Getenv("EMBEDDER_API_KEY"))
+		if model != "" {
+			embedder.Model = model
+		}
+		if baseURL != "" {
+			embedder.BaseURL = baseURL
+		}
+		return embedder, nil
+
+	case "ollama":
+		embedder := cloneAttack.NewOllamaEmbedder(model)
+		if baseURL != "" {
+			embedder.BaseURL = baseURL
+		}
+		return embedder, nil
+
+	case "local":
+		scriptPath := os.Getenv("EMBEDDER_SCRIPT_PATH")
+		if scriptPath == "" {
+			return nil, fmt.Errorf("EMBEDDER_SCRIPT_PATH is required when EMBEDDER_PROVIDER=local")
+		}
+		return cloneAttack.NewLocalEmbedder(scriptPath), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported EMBEDDER_PROVIDER %q: must be openai, ollama, or local", provider)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"datasnack/cloneAttack"
+)
+
+// embeddingCache persists text->embedding lookups across suggestionsCmd runs,
+// keyed by SHA256 of the embedded text, so re-running suggestions against
+// the same prompt config and evaluation results doesn't re-embed every
+// prompt and vulnerability on every invocation.
+type embeddingCache struct {
+	path    string
+	entries map[string][]float32
+	dirty   bool
+}
+
+// loadEmbeddingCache reads path if it exists, starting empty otherwise - a
+// missing or corrupt cache file isn't fatal, it's just a cold start.
+func loadEmbeddingCache(path string) *embeddingCache {
+	cache := &embeddingCache{path: path, entries: make(map[string][]float32)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	_ = json.Unmarshal(data, &cache.entries)
+	return cache
+}
+
+// save writes the cache back to disk if anything was added since it was
+// loaded; a no-op otherwise.
+func (c *embeddingCache) save() error {
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// embed returns text's embedding, consulting the cache first and only
+// calling embedder on a miss.
+func (c *embeddingCache) embed(ctx context.Context, embedder cloneAttack.Embedder, text string) ([]float32, error) {
+	key := hashText(text)
+	if vec, ok := c.entries[key]; ok {
+		return vec, nil
+	}
+	vec, err := embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	c.entries[key] = vec
+	c.dirty = true
+	return vec, nil
+}
+
+// embeddingMatcher ranks a vulnerability against prompts by cosine
+// similarity between their embeddings, replacing the old
+// strings.Contains(...AgentType...) heuristic in findRelevantVulnerabilities.
+type embeddingMatcher struct {
+	embedder      cloneAttack.Embedder
+	cache         *embeddingCache
+	minSimilarity float64
+	topK          int
+}
+
+// newEmbeddingMatcher builds an embeddingMatcher from embedder and the
+// persisted cache at cachePath.
+func newEmbeddingMatcher(embedder cloneAttack.Embedder, cachePath string, minSimilarity float64, topK int) *embeddingMatcher {
+	return &embeddingMatcher{
+		embedder:      embedder,
+		cache:         loadEmbeddingCache(cachePath),
+		minSimilarity: minSimilarity,
+		topK:          topK,
+	}
+}
+
+// promptText is the text embedded to represent a prompt: its own content
+// plus the context (description, agent type) that distinguishes it from
+// other prompts with similar wording.
+func promptText(info PromptInfo) string {
+	return info.Prompt + "\n" + info.Description + "\n" + info.AgentType
+}
+
+// vulnerabilityText is the text embedded to represent a vulnerability
+// finding, for comparison against promptText.
+func vulnerabilityText(vuln Vulnerability) string {
+	return vuln.Type + "\n" + vuln.Description + "\n" + vuln.Prompt + "\n" + vuln.Response
+}
+
+// cosineSimilarity is vectorStore's formula, duplicated here rather than
+// imported since matching needs float64 precision over single prompt/vuln
+// vector pairs, not vectorStore.VectorSink's batched id-keyed storage.
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// scoredVulnerability pairs a Vulnerability with its cosine similarity to
+// the prompt it was just matched against, for rankRelevantVulnerabilities to
+// sort by before truncating to topK.
+type scoredVulnerability struct {
+	vuln  Vulnerability
+	score float64
+}
+
+// rankRelevantVulnerabilities embeds promptInfo and every vulnerability in
+// results (via m.cache, so repeat runs reuse prior embeddings), keeps those
+// scoring at least m.minSimilarity against the prompt, and returns them
+// sorted by descending score and truncated to m.topK. The best (first)
+// score is returned alongside for the caller to record as a
+// PromptSuggestion's MatchScore.
+func (m *embeddingMatcher) rankRelevantVulnerabilities(ctx context.Context, promptInfo PromptInfo, vulnerabilities []Vulnerability) ([]Vulnerability, float64, error) {
+	promptVec, err := m.cache.embed(ctx, m.embedder, promptText(promptInfo))
+	if err != nil {
+		return nil, 0, fmt.Errorf("embedding prompt: %w", err)
+	}
+
+	scored := make([]scoredVulnerability, 0, len(vulnerabilities))
+	for _, vuln := range vulnerabilities {
+		vulnVec, err := m.cache.embed(ctx, m.embedder, vulnerabilityText(vuln))
+		if err != nil {
+			return nil, 0, fmt.Errorf("embedding vulnerability %q: %w", vuln.Type, err)
+		}
+		score := cosineSimilarity(promptVec, vulnVec)
+		if score < m.minSimilarity {
+			continue
+		}
+		scored = append(scored, scoredVulnerability{vuln: vuln, score: score})
+	}
+
+	sort.Slice(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	if m.topK > 0 && len(scored) > m.topK {
+		scored = scored[:m.topK]
+	}
+
+	relevant := make([]Vulnerability, len(scored))
+	bestScore := 0.0
+	for i, sv := range scored {
+		relevant[i] = sv.vuln
+		if i == 0 {
+			bestScore = sv.score
+		}
+	}
+	return relevant, bestScore, nil
+}
@@ -5,13 +5,41 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"datasnack/cloneAttack"
+	"datasnack/cloneAttack/harness"
+	"datasnack/cloneAttack/report"
+	"datasnack/pricing"
 
 	"github.com/spf13/cobra"
 )
 
+// loadTestConfig holds the path to an optional harness.Config file passed via --load-config.
+var loadTestConfig string
+
+// resumeFile holds the path to a previous run's JSONL results stream passed
+// via --resume; reportFormats holds the comma-separated list passed via
+// --report-format.
+var (
+	resumeFile    string
+	reportFormats string
+)
+
+// evaluaten8nFormat holds the output format for the results file written by
+// evaluaten8nCmd, set via --format: "json" (default) or "sarif".
+var evaluaten8nFormat string
+
+// assertionsFile holds the path to an optional declarative assertions YAML
+// file passed via --assertions.
+var assertionsFile string
+
+// emitVEX is set via --vex to additionally write a CycloneDX VEX document
+// describing the evaluated agent and its vulnerabilities.
+var emitVEX bool
+
 // evaluaten8nCmd represents the evaluaten8n command
 var evaluaten8nCmd = &cobra.Command{
 	Use:   "evaluaten8n [workflow-file]",
@@ -54,12 +82,17 @@ Example:
 		}
 
 		// Initialize AI client (same logic as serve.go)
-		ai, err := initializeAIClient()
+		pricingTable, err := pricing.Default()
+		if err != nil {
+			log.Fatalf("Failed to load default pricing table: %v", err)
+		}
+		ai, err := initializeAIClient(pricingTable)
 		if err != nil {
 			log.Fatalf("Failed to initialize AI client: %v", err)
 		}
 
-		// Initialize n8n workflow evaluator
+		// Initialize n8n workflow evaluator. Passing a nil transport falls back
+		// to an HTTP webhook transport built from the workflow's own webhook node.
 		evaluator := cloneAttack.NewN8nWorkflowEvaluator(
 			ai,
 			workflowFile,
@@ -70,8 +103,47 @@ Example:
 				ConsistencyTests:     agentDetails.TestConfiguration.ConsistencyTests,
 				IterationsPerTest:    agentDetails.TestConfiguration.IterationsPerTest,
 			},
+			nil,
 		)
 
+		// Optionally run the test suites under a declarative load-test
+		// strategy (concurrency, ramp-up, duration, think-time) instead
+		// of the default sequential execution.
+		if loadTestConfig != "" {
+			cfg, err := harness.LoadConfig(loadTestConfig)
+			if err != nil {
+				log.Fatalf("Failed to load load-test config: %v", err)
+			}
+			evaluator.WithHarnessConfig(cfg)
+		}
+
+		// Stream every completed call to results/n8n_evaluation_stream.jsonl
+		// as it happens, with periodic StressTestResults checkpoints, so a
+		// crash partway through a long run can be continued with --resume.
+		streamFile := "results/n8n_evaluation_stream.jsonl"
+		if resumeFile != "" {
+			streamFile = resumeFile
+			if _, err := evaluator.WithResume(resumeFile); err != nil {
+				log.Fatalf("Failed to resume from %s: %v", resumeFile, err)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(streamFile), 0755); err != nil {
+			log.Fatalf("Failed to create results stream directory: %v", err)
+		}
+		evaluator.WithResultsStream(streamFile, 10)
+
+		if reportFormats != "" {
+			evaluator.WithReportFormats(strings.Split(reportFormats, ","))
+		}
+
+		// Gate the run on a declarative set of expected-behavior assertions,
+		// e.g. "the response must not contain internal API key names".
+		if assertionsFile != "" {
+			if _, err := evaluator.WithAssertions(assertionsFile); err != nil {
+				log.Fatalf("Failed to load assertions from %s: %v", assertionsFile, err)
+			}
+		}
+
 		// Run comprehensive vulnerability test
 		results, err := evaluator.RunComprehensiveVulnerabilityTest()
 		if err != nil {
@@ -80,16 +152,52 @@ Example:
 
 		// Save results
 		timestamp := time.Now().Format("20060102_150405")
-		resultsFile := fmt.Sprintf("results/n8n_evaluation_results_%s.json", timestamp)
 
-		resultsJSON, err := json.MarshalIndent(results, "", "  ")
-		if err != nil {
-			log.Printf("Failed to marshal results: %v", err)
+		if evaluaten8nFormat == "sarif" {
+			vulnReport := &cloneAttack.VulnerabilityReport{PotentialVulnerabilities: results.Vulnerabilities}
+			sarifData, err := vulnReport.ToSARIF("")
+			if err != nil {
+				log.Printf("Failed to render SARIF report: %v", err)
+			} else {
+				resultsFile := fmt.Sprintf("results/n8n_evaluation_results_%s.sarif", timestamp)
+				if err := os.WriteFile(resultsFile, sarifData, 0644); err != nil {
+					log.Printf("Failed to write SARIF report: %v", err)
+				} else {
+					log.Printf("SARIF report saved to: %s", resultsFile)
+				}
+			}
 		} else {
-			if err := os.WriteFile(resultsFile, resultsJSON, 0644); err != nil {
-				log.Printf("Failed to write results: %v", err)
+			resultsFile := fmt.Sprintf("results/n8n_evaluation_results_%s.json", timestamp)
+			resultsJSON, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				log.Printf("Failed to marshal results: %v", err)
+			} else {
+				if err := os.WriteFile(resultsFile, resultsJSON, 0644); err != nil {
+					log.Printf("Failed to write results: %v", err)
+				} else {
+					log.Printf("Results saved to: %s", resultsFile)
+				}
+			}
+		}
+
+		if emitVEX {
+			vulnReport := &cloneAttack.VulnerabilityReport{PotentialVulnerabilities: results.Vulnerabilities}
+			component := report.BomRef{
+				Ref:         fmt.Sprintf("agent:%s", filepath.Base(workflowFile)),
+				Name:        filepath.Base(workflowFile),
+				Description: agentDetails.AgentPurpose,
+				Properties:  map[string]string{"systemPrompt": agentDetails.AgentPurpose},
+			}
+			vexData, err := vulnReport.ToCycloneDXVEX(component)
+			if err != nil {
+				log.Printf("Failed to render CycloneDX VEX document: %v", err)
 			} else {
-				log.Printf("Results saved to: %s", resultsFile)
+				vexFile := fmt.Sprintf("results/n8n_evaluation_results_%s.vex.json", timestamp)
+				if err := os.WriteFile(vexFile, vexData, 0644); err != nil {
+					log.Printf("Failed to write CycloneDX VEX document: %v", err)
+				} else {
+					log.Printf("CycloneDX VEX document saved to: %s", vexFile)
+				}
 			}
 		}
 
@@ -97,9 +205,27 @@ Example:
 			results.TotalCalls,
 			results.SuccessfulCalls,
 			results.FailedCalls)
+
+		// Gate CI pipelines on specific behavioral guarantees: exit non-zero
+		// if any loaded assertion failed.
+		failed := 0
+		for _, result := range results.AssertionsApplied {
+			if !result.Passed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Fatalf("%d/%d assertions failed", failed, len(results.AssertionsApplied))
+		}
 	},
 }
 
 func init() {
+	evaluaten8nCmd.Flags().StringVar(&loadTestConfig, "load-config", "", "path to a harness load-test config JSON file (use '-' for stdin); omit to run tests sequentially")
+	evaluaten8nCmd.Flags().StringVar(&resumeFile, "resume", "", "path to a previous run's JSONL results stream; resumes by skipping scenarios it already completed")
+	evaluaten8nCmd.Flags().StringVar(&reportFormats, "report-format", "", "comma-separated report formats to write in addition to JSON, e.g. \"sarif,junit\"")
+	evaluaten8nCmd.Flags().StringVar(&evaluaten8nFormat, "format", "json", "output format for the results file: \"json\" or \"sarif\"")
+	evaluaten8nCmd.Flags().StringVar(&assertionsFile, "assertions", "", "path to a declarative assertions YAML file; the command exits non-zero if any assertion fails")
+	evaluaten8nCmd.Flags().BoolVar(&emitVEX, "vex", false, "also write a CycloneDX VEX document describing the agent and its vulnerabilities")
 	rootCmd.AddCommand(evaluaten8nCmd)
 }
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	"datasnack/cmd/dag"
+
+	"github.com/spf13/cobra"
+)
+
+// dagBaseURL holds the n8n webhook base URL passed via --n8n-base-url.
+var dagBaseURL string
+
+// maxCostUSD holds the budget assertion passed via --max-cost-usd: a
+// negative value (the default) means no budget is enforced.
+var maxCostUSD float64
+
+// evaluatesetCmd represents the evaluateset command
+var evaluatesetCmd = &cobra.Command{
+	Use:   "evaluateset [evalset-file]",
+	Short: "Run a DAG of converted n8n workflows declared in an evalset.yaml manifest",
+	Long: `Evaluateset runs several converted n8n workflows as a single dependency DAG,
+in the style of an Argo Workflow's DAG template: each task names a converted
+workflow file and, optionally, the tasks it dependsOn. Downstream tasks can
+reference an upstream task's webhook response in their arguments via
+"{{tasks.<name>.outputs.<field>}}" expressions.
+
+Tasks whose dependencies have all resolved are dispatched concurrently. A
+task that fails skips every dependent that doesn't declare
+"continueOn: [failed]". The run's overall success is determined by its
+"target" tasks (or every task, if none is set).
+
+Results, including a Mermaid rendering of the executed DAG and the run's
+aggregate cost_usd (summed from each task's converted-workflow response, if
+it reported one), are written to evalsetResults.json. Pass --max-cost-usd to
+fail the run once that total is exceeded.
+
+Example:
+  ai-evaluator evaluateset evalset.yaml
+  ai-evaluator evaluateset evalset.yaml --max-cost-usd 0.50`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifestFile := args[0]
+
+		d, err := dag.Load(manifestFile)
+		if err != nil {
+			log.Fatalf("Failed to load evalset: %v", err)
+		}
+
+		scheduler := &dag.Scheduler{DAG: d, Invoke: dag.WebhookInvoker{BaseURL: dagBaseURL}}
+		report, err := scheduler.Run(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to run DAG: %v", err)
+		}
+
+		succeeded := 0
+		totalCostUSD := 0.0
+		outcomes := make([]evalsetOutcome, 0, len(report.Outcomes))
+		for _, o := range report.Outcomes {
+			log.Printf("task %q: %s (%d attempt(s), %s)", o.Task, o.Status, o.Attempts, o.Duration())
+			if o.Status == dag.StatusSucceeded {
+				succeeded++
+			}
+
+			costUSD, _ := o.Outputs["cost_usd"].(float64)
+			totalCostUSD += costUSD
+
+			entry := evalsetOutcome{
+				Task:     o.Task,
+				Status:   string(o.Status),
+				Attempts: o.Attempts,
+				Seconds:  o.Duration().Seconds(),
+				Response: o.Response,
+				Outputs:  o.Outputs,
+				CostUSD:  costUSD,
+			}
+			if o.Err != nil {
+				entry.Error = o.Err.Error()
+			}
+			outcomes = append(outcomes, entry)
+		}
+
+		withinBudget := maxCostUSD < 0 || totalCostUSD <= maxCostUSD
+		if !withinBudget {
+			log.Printf("DAG run exceeded its budget: cost_usd %.4f > --max-cost-usd %.4f", totalCostUSD, maxCostUSD)
+		}
+
+		results := evalsetResults{
+			Success:      report.Success && withinBudget,
+			TotalCostUSD: totalCostUSD,
+			Outcomes:     outcomes,
+			Mermaid:      report.Mermaid(),
+			Graphviz:     report.Graphviz(),
+		}
+
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal results: %v", err)
+		}
+		if err := os.WriteFile("evalsetResults.json", data, 0644); err != nil {
+			log.Fatalf("Failed to write results: %v", err)
+		}
+
+		log.Println("Results saved to: evalsetResults.json")
+		if !results.Success {
+			if !withinBudget {
+				log.Fatalf("DAG run exceeded its budget: cost_usd %.4f > --max-cost-usd %.4f", totalCostUSD, maxCostUSD)
+			}
+			log.Fatalf("DAG run did not meet its target: %d/%d tasks succeeded", succeeded, len(report.Outcomes))
+		}
+	},
+}
+
+// evalsetResults is the JSON shape written to evalsetResults.json.
+type evalsetResults struct {
+	Success      bool             `json:"success"`
+	TotalCostUSD float64          `json:"totalCostUsd"`
+	Outcomes     []evalsetOutcome `json:"outcomes"`
+	Mermaid      string           `json:"mermaid"`
+	Graphviz     string           `json:"graphviz"`
+}
+
+// evalsetOutcome is a JSON-friendly copy of a dag.Outcome: dag.Outcome's Err
+// field is an error interface, which doesn't marshal usefully on its own.
+type evalsetOutcome struct {
+	Task     string                 `json:"task"`
+	Status   string                 `json:"status"`
+	Attempts int                    `json:"attempts"`
+	Seconds  float64                `json:"seconds"`
+	Response string                 `json:"response,omitempty"`
+	Outputs  map[string]interface{} `json:"outputs,omitempty"`
+	CostUSD  float64                `json:"costUsd,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+func init() {
+	evaluatesetCmd.Flags().StringVar(&dagBaseURL, "n8n-base-url", "http://localhost:5678/webhook-test", "base URL n8n webhook triggers are served from")
+	evaluatesetCmd.Flags().Float64Var(&maxCostUSD, "max-cost-usd", -1, "fail the run if aggregate cost_usd exceeds this budget; negative disables the check")
+	rootCmd.AddCommand(evaluatesetCmd)
+}
@@ -0,0 +1,276 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"datasnack/cloneAttack"
+
+	"github.com/spf13/cobra"
+)
+
+// exceptionsFile backs every exceptions subcommand's --file flag: the
+// VulnerabilityException store (YAML, or JSON by extension) consulted by
+// ServicesPlus/PythonAgentEvaluator's WithExceptions.
+var exceptionsFile string
+
+// go run . exceptions
+var exceptionsCmd = &cobra.Command{
+	Use:   "exceptions",
+	Short: "Manage the VulnerabilityException store read by ServicesPlus/PythonAgentEvaluator's WithExceptions (evaluate and evaluaten8n do not load it)",
+	Long: `Exceptions let operators suppress known-accepted findings (a compensating
+control, a confirmed false positive, an accepted risk) without editing test
+code. "exceptions list/create/update/delete" manage the store directly from
+the CLI; "exceptions serve" exposes the same operations as a small HTTP API
+for a security team's own tooling.`,
+}
+
+// go run . exceptions list
+var exceptionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every exception in the store",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := cloneAttack.NewFileExceptionStore(exceptionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load exception store:", err)
+		}
+		list, err := store.List()
+		if err != nil {
+			log.Fatalln("Failed to list exceptions:", err)
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			log.Fatalln("Failed to marshal exceptions:", err)
+		}
+		fmt.Println(string(data))
+	},
+}
+
+// exceptionType, exceptionSeverities, exceptionPromptPattern,
+// exceptionResponsePattern, exceptionScenarioIDs, exceptionReason,
+// exceptionCreatedBy, and exceptionExpiresAt back "exceptions create"/
+// "exceptions update"'s flags.
+var exceptionType string
+var exceptionSeverities []string
+var exceptionPromptPattern string
+var exceptionResponsePattern string
+var exceptionScenarioIDs []string
+var exceptionReason string
+var exceptionCreatedBy string
+var exceptionExpiresAt string
+
+func exceptionFromFlags() cloneAttack.VulnerabilityException {
+	return cloneAttack.VulnerabilityException{
+		Type:            exceptionType,
+		Severities:      exceptionSeverities,
+		PromptPattern:   exceptionPromptPattern,
+		ResponsePattern: exceptionResponsePattern,
+		ScenarioIDs:     exceptionScenarioIDs,
+		Reason:          exceptionReason,
+		CreatedBy:       exceptionCreatedBy,
+		ExpiresAt:       exceptionExpiresAt,
+	}
+}
+
+// go run . exceptions create
+var exceptionsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Add a new exception to the store",
+	Run: func(cmd *cobra.Command, args []string) {
+		if exceptionReason == "" {
+			log.Fatalln("--reason is required: one of compensating_controls, false_positive, accepted_risk")
+		}
+		store, err := cloneAttack.NewFileExceptionStore(exceptionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load exception store:", err)
+		}
+		created, err := store.Create(exceptionFromFlags())
+		if err != nil {
+			log.Fatalln("Failed to create exception:", err)
+		}
+		log.Printf("Created exception %s", created.Guid)
+	},
+}
+
+// exceptionGuid backs "exceptions update"/"exceptions delete"'s --guid flag.
+var exceptionGuid string
+
+// go run . exceptions update
+var exceptionsUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Replace an existing exception's fields",
+	Run: func(cmd *cobra.Command, args []string) {
+		if exceptionGuid == "" {
+			log.Fatalln("--guid is required")
+		}
+		store, err := cloneAttack.NewFileExceptionStore(exceptionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load exception store:", err)
+		}
+		updated, err := store.Update(exceptionGuid, exceptionFromFlags())
+		if err != nil {
+			log.Fatalln("Failed to update exception:", err)
+		}
+		log.Printf("Updated exception %s", updated.Guid)
+	},
+}
+
+// go run . exceptions delete
+var exceptionsDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Remove an exception from the store",
+	Run: func(cmd *cobra.Command, args []string) {
+		if exceptionGuid == "" {
+			log.Fatalln("--guid is required")
+		}
+		store, err := cloneAttack.NewFileExceptionStore(exceptionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load exception store:", err)
+		}
+		if err := store.Delete(exceptionGuid); err != nil {
+			log.Fatalln("Failed to delete exception:", err)
+		}
+		log.Printf("Deleted exception %s", exceptionGuid)
+	},
+}
+
+// exceptionsServePort backs "exceptions serve"'s --port flag.
+var exceptionsServePort int
+
+// go run . exceptions serve
+var exceptionsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Stand up an HTTP API for managing the exception store",
+	Long: `Exposes the exception store over HTTP so a security team's own tooling can
+manage it without shelling out to this CLI:
+
+  GET    /exceptions       list every exception
+  POST   /exceptions       create one (JSON body: a VulnerabilityException)
+  PUT    /exceptions/{guid} replace one
+  DELETE /exceptions/{guid} remove one`,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := cloneAttack.NewFileExceptionStore(exceptionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load exception store:", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/exceptions", func(w http.ResponseWriter, r *http.Request) {
+			handleExceptionsCollection(w, r, store)
+		})
+		mux.HandleFunc("/exceptions/", func(w http.ResponseWriter, r *http.Request) {
+			handleExceptionsItem(w, r, store)
+		})
+
+		addr := fmt.Sprintf(":%d", exceptionsServePort)
+		log.Printf("Serving the exception store (%s) on %s", exceptionsFile, addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatalf("exceptions serve: server stopped: %v", err)
+		}
+	},
+}
+
+func handleExceptionsCollection(w http.ResponseWriter, r *http.Request, store cloneAttack.VulnerabilityExceptionStore) {
+	switch r.Method {
+	case http.MethodGet:
+		list, err := store.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	case http.MethodPost:
+		var e cloneAttack.VulnerabilityException
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		created, err := store.Create(e)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+
+	default:
+		http.Error(w, "only GET and POST are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleExceptionsItem(w http.ResponseWriter, r *http.Request, store cloneAttack.VulnerabilityExceptionStore) {
+	guid := strings.TrimPrefix(r.URL.Path, "/exceptions/")
+	if guid == "" {
+		http.Error(w, "missing guid in path", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var e cloneAttack.VulnerabilityException
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updated, err := store.Update(guid, e)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, updated)
+
+	case http.MethodDelete:
+		if err := store.Delete(guid); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "only PUT and DELETE are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("exceptions serve: failed to encode response: %v", err)
+	}
+}
+
+func init() {
+	exceptionsCmd.PersistentFlags().StringVar(&exceptionsFile, "file", "config/vulnerability_exceptions.yaml", "exception store file (YAML, or JSON by extension)")
+
+	exceptionsCmd.AddCommand(exceptionsListCmd)
+
+	for _, c := range []*cobra.Command{exceptionsCreateCmd, exceptionsUpdateCmd} {
+		c.Flags().StringVar(&exceptionType, "type", "", "Vulnerability.Type to match (e.g. data_leakage); empty matches any type")
+		c.Flags().StringSliceVar(&exceptionSeverities, "severities", nil, "severities to match (high, medium, low); empty matches any severity")
+		c.Flags().StringVar(&exceptionPromptPattern, "prompt-pattern", "", "regex matched against Vulnerability.Prompt")
+		c.Flags().StringVar(&exceptionResponsePattern, "response-pattern", "", "regex matched against Vulnerability.Response")
+		c.Flags().StringSliceVar(&exceptionScenarioIDs, "scenario-ids", nil, "TestType/TestScenario values to match; empty matches any scenario")
+		c.Flags().StringVar(&exceptionReason, "reason", "", "compensating_controls, false_positive, or accepted_risk")
+		c.Flags().StringVar(&exceptionCreatedBy, "created-by", os.Getenv("USER"), "who created this exception")
+		c.Flags().StringVar(&exceptionExpiresAt, "expires-at", "", "RFC3339 timestamp after which this exception no longer applies")
+	}
+	exceptionsCmd.AddCommand(exceptionsCreateCmd)
+
+	exceptionsUpdateCmd.Flags().StringVar(&exceptionGuid, "guid", "", "guid of the exception to update")
+	exceptionsCmd.AddCommand(exceptionsUpdateCmd)
+
+	exceptionsDeleteCmd.Flags().StringVar(&exceptionGuid, "guid", "", "guid of the exception to delete")
+	exceptionsCmd.AddCommand(exceptionsDeleteCmd)
+
+	exceptionsServeCmd.Flags().IntVar(&exceptionsServePort, "port", 8086, "port to serve the exception API on")
+	exceptionsCmd.AddCommand(exceptionsServeCmd)
+
+	rootCmd.AddCommand(exceptionsCmd)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Reachability classes a prompt relative to the agent's source tree,
+// modeled on govulncheck's imported-vs-called split: "reachable" means the
+// prompt's symbol turns up somewhere other than its own declaration file,
+// "imported-only" means its declaration file exists but nothing else in the
+// tree references it, and "unused" means even its declaration file is
+// missing.
+const (
+	reachableStatus    = "reachable"
+	importedOnlyStatus = "imported-only"
+	unusedStatus       = "unused"
+)
+
+// promptUsageGraph is a static reference count, per prompt name, of how
+// often that name turns up in the agent source tree outside its own
+// declaration file. It's built once per suggestionsCmd/reportCmd run and
+// consulted by findRelevantVulnerabilities so noisy substring matches on
+// AgentType don't surface vulnerabilities for prompts the agent never
+// actually calls.
+type promptUsageGraph struct {
+	declared   map[string]bool // promptName -> its Location file exists on disk
+	referenced map[string]int  // promptName -> references found outside its own Location file
+}
+
+// buildPromptUsageGraph scans root (agentConfig.AgentRootFolder) for every
+// prompt name in config.OriginalPrompts, grepping each file under root for
+// occurrences of that name other than in the prompt's own PromptInfo.Location
+// file. A file that can't be read is skipped rather than failing the whole
+// scan, since agent source trees often mix text, binary, and vendored
+// assets under one root.
+func buildPromptUsageGraph(root string, config *PromptConfig) (*promptUsageGraph, error) {
+	graph := &promptUsageGraph{
+		declared:   make(map[string]bool),
+		referenced: make(map[string]int),
+	}
+
+	for promptName, info := range config.OriginalPrompts {
+		if info.Location == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, info.Location)); err == nil {
+			graph.declared[promptName] = true
+		}
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil
+		}
+		contents := string(data)
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+
+		for promptName, info := range config.OriginalPrompts {
+			if rel == info.Location {
+				continue
+			}
+			graph.referenced[promptName] += strings.Count(contents, promptName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// classify returns promptName's Reachability relative to graph.
+func (g *promptUsageGraph) classify(promptName string) string {
+	if g.referenced[promptName] > 0 {
+		return reachableStatus
+	}
+	if g.declared[promptName] {
+		return importedOnlyStatus
+	}
+	return unusedStatus
+}
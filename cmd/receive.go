@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"datasnack/cloudevents"
+	"datasnack/schema"
+
+	"github.com/spf13/cobra"
+)
+
+// receivePort and receivePath hold the --port and --path flags for
+// receiveCmd.
+var receivePort int
+var receivePath string
+
+// receiveCmd represents the receive command
+var receiveCmd = &cobra.Command{
+	Use:   "receive",
+	Short: "Accept out-of-band CloudEvents-formatted evaluation results",
+	Long: `Receive stands up an HTTP server that accepts evaluation results sent as
+CloudEvents v1.0 events (binary or structured content mode), for workflows
+converted with --cloudevents that reply out-of-band (e.g. via a Kafka/NATS
+bridge or another workflow's HTTP Request node) rather than through
+n8n's respondToWebhook node.
+
+Each accepted event's data is validated against
+schema.EvaluationResponseSchema() and logged; drifted fields are reported
+but don't reject the event, since out-of-band senders aren't guaranteed to
+be converted workflows.
+
+Example:
+  ai-evaluator receive --port 8085 --path /evaluate-results`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mux := http.NewServeMux()
+		mux.HandleFunc(receivePath, handleReceivedEvent)
+
+		addr := fmt.Sprintf(":%d", receivePort)
+		log.Printf("Listening for CloudEvents-formatted evaluation results on %s%s", addr, receivePath)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatalf("receive: server stopped: %v", err)
+		}
+	},
+}
+
+func handleReceivedEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := cloudevents.Read(r)
+	if err != nil {
+		log.Printf("receive: failed to decode CloudEvent: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if drifts, err := schema.ValidateJSON(schema.EvaluationResponseSchema(), event.Data); err != nil {
+		log.Printf("receive: event %s data is not JSON: %v", event.ID, err)
+	} else if len(drifts) > 0 {
+		log.Printf("receive: event %s drifted from the expected evaluation schema: %v", event.ID, drifts)
+	}
+
+	log.Printf("receive: accepted event id=%s type=%s source=%s", event.ID, event.Type, event.Source)
+
+	if os.Getenv("DATASNACK_RECEIVE_LOG_PAYLOAD") != "" {
+		pretty, _ := json.MarshalIndent(event, "", "  ")
+		log.Println(string(pretty))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func init() {
+	receiveCmd.Flags().IntVar(&receivePort, "port", 8085, "port to listen on for incoming CloudEvents")
+	receiveCmd.Flags().StringVar(&receivePath, "path", "/evaluate-results", "HTTP path to accept CloudEvents on")
+	rootCmd.AddCommand(receiveCmd)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// reportFormat holds the --format flag. Unlike suggestionsCmd, report's
+// native purpose is the SARIF export, so it defaults to "sarif" rather than
+// "json"; "json" here means the raw []report.Finding list, not a
+// SuggestionsReport.
+var reportFormat string
+
+// go run . report
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Export the most recent evaluation results and prompt suggestions as SARIF, CSV, or Markdown",
+	Long: `Converts the most recent evaluation_*.json results and their generated prompt_suggestions_*.json
+into a single findings view, suitable for GitHub code scanning, GitLab, or other security dashboards:
+
+- ruleId is derived from each Vulnerability's normalized taxonomy type (falling back to its free-text Type)
+- level is mapped from Severity (high/critical -> error, medium/low -> warning, else note)
+- each finding's location resolves to its PromptInfo.Location via PromptConfig.OriginalPrompts
+- any PromptSuggestion covering a finding's vulnerability type attaches as a SARIF fix
+
+Defaults to SARIF 2.1.0; --format also accepts json (the raw findings list), csv, and md.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateReportFormat(reportFormat); err != nil {
+			log.Fatalln(err)
+		}
+
+		configPath := os.Getenv("AGENT_CONFIG")
+		if configPath == "" {
+			configPath = "config/agentConfig.json"
+		}
+
+		log.Println("Reading agent configuration from:", configPath)
+		configData, err := os.ReadFile(configPath)
+		if err != nil {
+			log.Fatalln("Failed to read agent config file:", err)
+		}
+
+		var agentConfig PythonAgentConfig
+		if err := json.Unmarshal(configData, &agentConfig); err != nil {
+			log.Fatalln("Failed to unmarshal agent config:", err)
+		}
+
+		resultsDir := "results"
+		evaluationFile, err := findMostRecentEvaluationFile(resultsDir)
+		if err != nil {
+			log.Fatalln("Failed to find evaluation results file:", err)
+		}
+		log.Printf("Using evaluation results from: %s", evaluationFile)
+
+		evaluationResults, err := loadEvaluationResults(evaluationFile)
+		if err != nil {
+			log.Fatalln("Failed to load evaluation results:", err)
+		}
+
+		catalog, err := loadVulnCatalog()
+		if err != nil {
+			log.Fatalln("Failed to load vuln taxonomy:", err)
+		}
+		normalizeVulnerabilities(evaluationResults, catalog)
+
+		suggestionsFile, err := findMostRecentSuggestionsFile(resultsDir)
+		if err != nil {
+			log.Fatalln("Failed to find prompt suggestions file:", err)
+		}
+		log.Printf("Using prompt suggestions from: %s", suggestionsFile)
+
+		suggestionsReport, err := loadSuggestionsReport(suggestionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load prompt suggestions:", err)
+		}
+
+		promptConfigPath := filepath.Join(agentConfig.AgentRootFolder, "backend", "evaluation", "config", "prompt_config.yaml")
+		log.Printf("Loading prompt config from: %s", promptConfigPath)
+
+		promptConfig, err := loadPromptConfig(promptConfigPath)
+		if err != nil {
+			log.Fatalln("Failed to load prompt config:", err)
+		}
+
+		findings := buildFindings(evaluationResults, suggestionsReport.Suggestions, promptConfig)
+
+		timestamp := time.Now().Format("20060102_150405")
+		basePath := fmt.Sprintf("results/report_%s", timestamp)
+
+		var filename string
+		if reportFormat == "json" {
+			data, err := json.MarshalIndent(findings, "", "  ")
+			if err != nil {
+				log.Fatalln("Failed to marshal findings:", err)
+			}
+			filename = basePath + ".json"
+			if err := os.WriteFile(filename, data, 0644); err != nil {
+				log.Fatalln("Failed to write report file:", err)
+			}
+		} else {
+			filename, err = writeFindingsReport(reportFormat, basePath, findings)
+			if err != nil {
+				log.Fatalln("Failed to write report file:", err)
+			}
+		}
+
+		log.Printf("Report saved to: %s", filename)
+		log.Printf("Exported %d findings from %d vulnerabilities and %d prompt suggestions",
+			len(findings), len(evaluationResults.Vulnerabilities), len(suggestionsReport.Suggestions))
+	},
+}
+
+// findMostRecentSuggestionsFile finds the most recently modified
+// prompt_suggestions_*.json file in resultsDir, mirroring
+// findMostRecentEvaluationFile. It only matches the JSON form of a
+// suggestions report, since that's the only format report can parse back
+// into a SuggestionsReport.
+func findMostRecentSuggestionsFile(resultsDir string) (string, error) {
+	var files []string
+
+	err := filepath.WalkDir(resultsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasPrefix(d.Name(), "prompt_suggestions_") && strings.HasSuffix(d.Name(), ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no prompt suggestions files found in %s", resultsDir)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		info1, _ := os.Stat(files[i])
+		info2, _ := os.Stat(files[j])
+		return info1.ModTime().After(info2.ModTime())
+	})
+
+	return files[0], nil
+}
+
+// loadSuggestionsReport loads a SuggestionsReport from a JSON file.
+func loadSuggestionsReport(filename string) (*SuggestionsReport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var rpt SuggestionsReport
+	if err := json.Unmarshal(data, &rpt); err != nil {
+		return nil, err
+	}
+
+	return &rpt, nil
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportFormat, "format", "sarif", "output format: sarif, json, csv, md")
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"datasnack/cloneAttack/report"
+	"fmt"
+	"os"
+)
+
+// validReportFormats are the --format values suggestionsCmd and reportCmd
+// both accept. "json" is each command's own native JSON report shape;
+// "sarif", "csv", and "md" render the shared []report.Finding view via
+// cloneAttack/report, so the same vulnerabilities/suggestions are
+// consumable by code-scanning dashboards as well as by humans.
+var validReportFormats = map[string]bool{"json": true, "sarif": true, "csv": true, "md": true}
+
+func validateReportFormat(format string) error {
+	if !validReportFormats[format] {
+		return fmt.Errorf("unsupported --format %q: must be one of json, sarif, csv, md", format)
+	}
+	return nil
+}
+
+// buildFindings converts results' vulnerabilities into cloneAttack/report
+// Findings. Each vulnerability's RuleID prefers its vulncatalog
+// NormalizedType over its free-text Type; its prompt location and fix (if
+// any) come from whichever suggestion first claimed that vulnerability
+// type, resolved against promptConfig.OriginalPrompts.
+func buildFindings(results *EvaluationResults, suggestions []PromptSuggestion, promptConfig *PromptConfig) []report.Finding {
+	suggestionForType := make(map[string]PromptSuggestion)
+	for _, s := range suggestions {
+		for _, t := range s.VulnerabilityTypes {
+			if _, exists := suggestionForType[t]; !exists {
+				suggestionForType[t] = s
+			}
+		}
+	}
+
+	findings := make([]report.Finding, 0, len(results.Vulnerabilities))
+	for _, vuln := range results.Vulnerabilities {
+		ruleID := vuln.NormalizedType
+		if ruleID == "" {
+			ruleID = vuln.Type
+		}
+		f := report.Finding{
+			RuleID:        ruleID,
+			Message:       vuln.Description,
+			Severity:      vuln.Severity,
+			TestType:      vuln.Type,
+			Score:         float64(vuln.Score),
+			InputPrompt:   vuln.Prompt,
+			AgentResponse: vuln.Response,
+		}
+
+		if s, ok := suggestionForType[vuln.Type]; ok {
+			f.LogicalID = s.PromptName
+			f.LogicalKind = "prompt"
+			if promptConfig != nil {
+				if info, ok := promptConfig.OriginalPrompts[s.PromptName]; ok {
+					f.WorkflowFile = info.Location
+				}
+			}
+			if s.SuggestedPrompt != "" && s.SuggestedPrompt != s.CurrentPrompt {
+				f.FixDescription = s.Reasoning
+				f.FixArtifactURI = f.WorkflowFile
+				f.OriginalText = s.CurrentPrompt
+				f.ReplacementText = s.SuggestedPrompt
+			}
+		}
+
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// writeFindingsReport renders findings as format ("sarif", "csv", or "md")
+// and writes it to basePath plus that format's extension, returning the
+// path written to.
+func writeFindingsReport(format, basePath string, findings []report.Finding) (string, error) {
+	var data []byte
+	var err error
+	switch format {
+	case "sarif":
+		data, err = report.SARIF("datasnack-ai-suggestions", "", "", findings)
+	case "csv":
+		data, err = report.CSV(findings)
+	case "md":
+		data = report.Markdown(findings)
+	default:
+		return "", fmt.Errorf("writeFindingsReport: unsupported format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	path := basePath + "." + format
+	return path, os.WriteFile(path, data, 0644)
+}
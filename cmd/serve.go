@@ -6,17 +6,58 @@ package cmd
 import (
 	awsbedrock "datasnack/awsBedrock"
 	"datasnack/cloneAttack"
+	"datasnack/cloneAttack/middleware"
 	"datasnack/gollmClient"
+	"datasnack/modelconfig"
+	"datasnack/pricing"
+	"datasnack/providerpool"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 )
 
+// evaluateFormat holds the output format for the results file written by
+// evaluateCmd, set via --format: "json" (default) or "sarif".
+var evaluateFormat string
+
+// evaluateStream enables live token output for evaluateCmd, set via
+// --stream. It's passed straight through to NewPythonAgentEvaluator, which
+// falls back to non-streaming generation for an AIClient that doesn't
+// implement cloneAttack.StreamingAIClient.
+var evaluateStream bool
+
+// evaluatePricing is set via --pricing to override the default $/1M-token
+// rates (pricing.Default()) used to estimate GenerationResult.USDCost for
+// every gollmClient.GollmClient evaluateCmd constructs.
+var evaluatePricing string
+
+// evaluateFailOnSeverity, evaluateAllowIfNoScan, and evaluateBypassIfNoVulns
+// back evaluateCmd's --fail-on-severity/--allow-if-no-scan/
+// --bypass-if-no-vulns flags, passed straight through as a
+// cloneAttack.PolicyConfig: when a fleet member's run returns a
+// *cloneAttack.PolicyViolationError, evaluateCmd logs it and still exits
+// non-zero once every fleet member has been evaluated.
+var evaluateFailOnSeverity string
+var evaluateAllowIfNoScan bool
+var evaluateBypassIfNoVulns bool
+
+// evaluateOutput is set via --output to additionally write a
+// cloneAttack.PythonAgentEvaluator.ExportSARIF report next to evaluateCmd's
+// JSON results file: "" (default, JSON only) or "sarif". Unlike --format,
+// which chooses between writing JSON or SARIF, --output adds the SARIF
+// report alongside the JSON one so existing JSON-consuming tooling is
+// unaffected.
+var evaluateOutput string
+
 type TestConfiguration struct {
 	DataLeakageTests     int `json:"dataLeakageTests"`
 	PromptInjectionTests int `json:"promptInjectionTests"`
@@ -37,9 +78,26 @@ type PythonAgentConfig struct {
 }
 
 type AIClientConfig struct {
-	PreferredOrder       []AIClientOption `json:"preferredOrder"`
-	FallbackToBedrock    bool             `json:"fallbackToBedrock"`
-	LogProviderSelection bool             `json:"logProviderSelection"`
+	PreferredOrder       []AIClientOption  `json:"preferredOrder"`
+	FallbackToBedrock    bool              `json:"fallbackToBedrock"`
+	LogProviderSelection bool              `json:"logProviderSelection"`
+	Middleware           middleware.Config `json:"middleware"`
+
+	// ProviderTokens and ProviderURLs let an AIClientOption's api key/base
+	// URL be supplied out of band, keyed by option.Provider, instead of (or
+	// in addition to) per-option EnvKey/Endpoint. initializeAIClient also
+	// merges in the PROVIDER_TOKEN / PROVIDER_URL env vars, each a
+	// comma-delimited list of "provider:value" pairs, which take priority
+	// over whatever's in the config file.
+	ProviderTokens map[string]string `json:"providerTokens,omitempty"`
+	ProviderURLs   map[string]string `json:"providerUrls,omitempty"`
+
+	// HealthCheckIntervalSeconds controls how often the ProviderPool built
+	// from PreferredOrder re-probes each provider in the background so a
+	// mid-run degradation drops it out of rotation. <= 0 disables periodic
+	// checks; every provider that passed testAIClient at startup is then
+	// simply assumed healthy for the rest of the run.
+	HealthCheckIntervalSeconds int `json:"healthCheckIntervalSeconds,omitempty"`
 }
 
 type AIClientOption struct {
@@ -49,6 +107,13 @@ type AIClientOption struct {
 	EnvKey      string `json:"envKey"`
 	Endpoint    string `json:"endpoint,omitempty"`
 	Description string `json:"description"`
+
+	// Weight is this option's relative share of traffic once it's in the
+	// ProviderPool, among whichever other options also passed testAIClient;
+	// <= 0 is treated as 1. MaxConcurrency caps how many calls may be in
+	// flight on it at once; <= 0 means unbounded.
+	Weight         int `json:"weight,omitempty"`
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
 }
 
 // go run . evaluate
@@ -79,10 +144,19 @@ based on performance results.`,
 			log.Fatalln("Failed to unmarshal agent config:", err)
 		}
 
-		// Initialize AI client based on configuration and available keys
-		ai, err := initializeAIClient()
+		pricingTable, err := loadPricingTable(evaluatePricing)
+		if err != nil {
+			log.Fatalln("Failed to load pricing table:", err)
+		}
+
+		// Initialize one AI client per model. Most setups still just have
+		// config/aiClientConfig.json's single preferred model; a
+		// config/models/ directory of modelconfig.ModelConfig YAML files
+		// (override via MODEL_CONFIG_DIR) runs the same attack suite across
+		// a whole fleet in one invocation instead.
+		fleet, err := initializeAIClientOrFleet(pricingTable)
 		if err != nil {
-			log.Fatalln("Failed to initialize AI client:", err)
+			log.Fatalln("Failed to initialize AI client(s):", err)
 		}
 
 		// Construct the evaluation config path from the agent root folder
@@ -95,53 +169,244 @@ based on performance results.`,
 			evaluationConfigPath = "config/evaluation_config.yaml"
 		}
 
-		// Initialize Python agent evaluator using HTTP endpoints
-		evaluator, err := cloneAttack.NewPythonAgentEvaluator(
-			ai,
-			cloneAttack.PythonAgentConfig{
-				PythonPath:      agentConfig.PythonPath,
-				AgentScript:     agentConfig.AgentScript,
-				TrackingEnabled: agentConfig.TrackingEnabled,
-			},
-			agentConfig.AgentPurpose,
-			cloneAttack.TestConfiguration{
-				DataLeakageTests:     agentConfig.TestConfiguration.DataLeakageTests,
-				PromptInjectionTests: agentConfig.TestConfiguration.PromptInjectionTests,
-				ConsistencyTests:     agentConfig.TestConfiguration.ConsistencyTests,
-				IterationsPerTest:    agentConfig.TestConfiguration.IterationsPerTest,
-			},
-			evaluationConfigPath,
-		)
-		if err != nil {
-			log.Fatalln("Failed to initialize Python agent evaluator:", err)
+		// Ctrl-C aborts whichever fleet member is currently generating
+		// (evaluator.Cancel), rather than killing the process outright, so
+		// results already collected for earlier fleet members are kept.
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		defer signal.Stop(sigCh)
+		var current *cloneAttack.PythonAgentEvaluator
+		exitCode := 0
+		go func() {
+			for range sigCh {
+				if current != nil {
+					log.Println("Interrupt received, aborting in-flight AI generation...")
+					current.Cancel()
+				}
+			}
+		}()
+
+		for _, name := range fleetNames(fleet) {
+			log.Printf("Evaluating against %s", name)
+
+			// Initialize Python agent evaluator using HTTP endpoints
+			evaluator, err := cloneAttack.NewPythonAgentEvaluator(
+				fleet[name],
+				cloneAttack.PythonAgentConfig{
+					PythonPath:      agentConfig.PythonPath,
+					AgentScript:     agentConfig.AgentScript,
+					TrackingEnabled: agentConfig.TrackingEnabled,
+				},
+				agentConfig.AgentPurpose,
+				cloneAttack.TestConfiguration{
+					DataLeakageTests:     agentConfig.TestConfiguration.DataLeakageTests,
+					PromptInjectionTests: agentConfig.TestConfiguration.PromptInjectionTests,
+					ConsistencyTests:     agentConfig.TestConfiguration.ConsistencyTests,
+					IterationsPerTest:    agentConfig.TestConfiguration.IterationsPerTest,
+					Policy: cloneAttack.PolicyConfig{
+						PreventOnSeverity: evaluateFailOnSeverity,
+						AllowIfNoScan:     evaluateAllowIfNoScan,
+						BypassIfNoVulns:   evaluateBypassIfNoVulns,
+					},
+				},
+				evaluationConfigPath,
+				evaluateStream,
+			)
+			if err != nil {
+				log.Printf("Failed to initialize Python agent evaluator for %s: %v", name, err)
+				continue
+			}
+			current = evaluator
+
+			// Run comprehensive evaluation
+			results, err := evaluator.RunComprehensiveVulnerabilityTest()
+			var policyErr *cloneAttack.PolicyViolationError
+			if err != nil && !errors.As(err, &policyErr) {
+				log.Printf("Comprehensive evaluation failed for %s: %v", name, err)
+				continue
+			}
+			if policyErr != nil {
+				log.Printf("Policy gate failed for %s: %v", name, policyErr)
+				exitCode = 1
+			}
+
+			timestamp := time.Now().Format("20060102_150405")
+
+			if evaluateFormat == "sarif" {
+				vulnReport := &cloneAttack.VulnerabilityReport{PotentialVulnerabilities: results.Vulnerabilities}
+				sarifData, err := vulnReport.ToSARIF("")
+				if err != nil {
+					log.Printf("Failed to render SARIF report for %s: %v", name, err)
+					continue
+				}
+				filename := resultsFilename(name, timestamp, "sarif")
+				if err := os.WriteFile(filename, sarifData, 0644); err != nil {
+					log.Println("Failed to write SARIF report:", err)
+				} else {
+					log.Printf("SARIF report saved to: %s", filename)
+				}
+				continue
+			}
+
+			// Save results to JSON file
+			resultsJSON, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				log.Printf("Failed to marshal results for %s: %v", name, err)
+				continue
+			}
+
+			filename := resultsFilename(name, timestamp, "json")
+			if err := os.WriteFile(filename, resultsJSON, 0644); err != nil {
+				log.Println("Failed to write results:", err)
+			} else {
+				log.Printf("Results saved to: %s", filename)
+			}
+
+			if evaluateOutput == "sarif" {
+				sarifFilename := resultsFilename(name, timestamp, "sarif")
+				if err := evaluator.ExportSARIF(sarifFilename); err != nil {
+					log.Printf("Failed to write SARIF report for %s: %v", name, err)
+				} else {
+					log.Printf("SARIF report saved to: %s", sarifFilename)
+				}
+			}
 		}
 
-		// Run comprehensive evaluation
-		results, err := evaluator.RunComprehensiveVulnerabilityTest()
-		if err != nil {
-			log.Println("Comprehensive evaluation failed:", err)
-			return
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	},
+}
+
+// resultsFilename names the results file for one fleet member. The default
+// (single aiClientConfig.json, no fleet) member is named "default" and gets
+// the original unsuffixed filename; every other fleet member's name is
+// included so a multi-model run doesn't overwrite its own results.
+func resultsFilename(name, timestamp, ext string) string {
+	if name == "default" {
+		return fmt.Sprintf("results/evaluation_results_%s.%s", timestamp, ext)
+	}
+	return fmt.Sprintf("results/evaluation_results_%s_%s.%s", name, timestamp, ext)
+}
+
+// fleetNames returns fleet's keys, sorted, so a multi-model run evaluates
+// (and logs) in a stable order instead of Go's randomized map iteration.
+func fleetNames(fleet map[string]cloneAttack.AIClient) []string {
+	names := make([]string, 0, len(fleet))
+	for name := range fleet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mergeProviderValues parses env (a comma-delimited list of "provider:value"
+// pairs, as PROVIDER_TOKEN/PROVIDER_URL are documented to be) into *dst,
+// creating the map if needed and overwriting any entry already present for
+// the same provider — env vars take priority over the config file.
+func mergeProviderValues(dst *map[string]string, env string) {
+	if env == "" {
+		return
+	}
+	if *dst == nil {
+		*dst = make(map[string]string)
+	}
+	for _, pair := range strings.Split(env, ",") {
+		provider, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || provider == "" {
+			continue
 		}
+		(*dst)[provider] = value
+	}
+}
+
+// resolveProviderValue looks up provider in configured, falling back to
+// fallback (an option's own EnvKey-derived key or Endpoint) when absent.
+func resolveProviderValue(configured map[string]string, provider, fallback string) string {
+	if v, ok := configured[provider]; ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// initializeAIClientOrFleet returns the fleet described by MODEL_CONFIG_DIR
+// (or config/models, if that directory exists), keyed by each model's
+// logical name; otherwise it falls back to the single client
+// initializeAIClient builds from aiClientConfig.json, keyed as "default".
+// pricingTable is applied to every gollmClient.GollmClient in the result, so
+// its GenerateAIMetered/GenerateAISchemaMetered calls report USDCost against
+// it instead of pricing.Default().
+func initializeAIClientOrFleet(pricingTable pricing.Table) (map[string]cloneAttack.AIClient, error) {
+	modelsDir := os.Getenv("MODEL_CONFIG_DIR")
+	if modelsDir == "" {
+		modelsDir = "config/models"
+	}
+
+	if _, err := os.Stat(modelsDir); err == nil {
+		return initializeAIClientFleet(modelsDir, pricingTable)
+	}
+
+	client, err := initializeAIClient(pricingTable)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]cloneAttack.AIClient{"default": client}, nil
+}
+
+// initializeAIClientFleet scans dir for modelconfig.ModelConfig YAML files,
+// building one cloneAttack.AIClient per entry, keyed by its logical name, so
+// evaluateCmd can run the same attack suite across every model in the fleet
+// in one invocation instead of aiClientConfig.json's single preferred model.
+func initializeAIClientFleet(dir string, pricingTable pricing.Table) (map[string]cloneAttack.AIClient, error) {
+	configs, err := modelconfig.LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
 
-		// Save results to JSON file
-		resultsJSON, err := json.MarshalIndent(results, "", "  ")
+	fleet := make(map[string]cloneAttack.AIClient, len(configs))
+	for name, cfg := range configs {
+		apiKey := ""
+		if cfg.TokenEnvKey != "" {
+			apiKey = os.Getenv(cfg.TokenEnvKey)
+		}
+
+		client, err := gollmClient.New(gollmClient.Config{
+			Provider:  cfg.Provider,
+			Model:     cfg.Model,
+			APIKey:    apiKey,
+			BaseURL:   cfg.Endpoint,
+			MaxTokens: cfg.MaxTokens,
+			Templates: cfg.Templates,
+			Pricing:   pricingTable,
+		})
 		if err != nil {
-			log.Println("Failed to marshal results:", err)
-			return
+			return nil, fmt.Errorf("failed to initialize AI client %q: %w", name, err)
 		}
 
-		timestamp := time.Now().Format("20060102_150405")
-		filename := fmt.Sprintf("results/evaluation_results_%s.json", timestamp)
-		if err := os.WriteFile(filename, resultsJSON, 0644); err != nil {
-			log.Println("Failed to write results:", err)
-		} else {
-			log.Printf("Results saved to: %s", filename)
+		if cfg.Temperature != 0 {
+			client.SetTemperature(cfg.Temperature)
 		}
-	},
+		if cfg.TopP != 0 {
+			client.SetTopP(cfg.TopP)
+		}
+
+		fleet[name] = client
+	}
+
+	return fleet, nil
 }
 
-// initializeAIClient creates an AI client based on configuration and available API keys
-func initializeAIClient() (cloneAttack.AIClient, error) {
+// initializeAIClient builds a providerpool.Pool over every PreferredOrder
+// option that creates and passes testAIClient successfully, instead of the
+// previous "try in order, keep the first that responds to Hello" behavior.
+// This lets RunComprehensiveVulnerabilityTest shard its calls across every
+// healthy provider (e.g. OpenAI, Anthropic, Groq, and a local Ollama) at
+// once, weighted by each option's configured Weight and backed off by its
+// own observed error rate and latency, instead of committing to a single
+// provider for the whole run. pricingTable is applied to every gollm-backed
+// member so its GenerateAIMetered/GenerateAISchemaMetered calls report
+// USDCost against it instead of pricing.Default().
+func initializeAIClient(pricingTable pricing.Table) (cloneAttack.AIClient, error) {
 	// Load AI client configuration
 	configPath := os.Getenv("AI_CLIENT_CONFIG")
 	if configPath == "" {
@@ -157,15 +422,24 @@ func initializeAIClient() (cloneAttack.AIClient, error) {
 	if err := json.Unmarshal(configData, &aiConfig); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal AI client config: %w", err)
 	}
+	mergeProviderValues(&aiConfig.ProviderTokens, os.Getenv("PROVIDER_TOKEN"))
+	mergeProviderValues(&aiConfig.ProviderURLs, os.Getenv("PROVIDER_URL"))
 
-	// Try each provider in the preferred order
+	var members []providerpool.Member
+
+	// Evaluate every provider in the preferred order, instead of stopping at
+	// the first one that works, so all of them can join the pool.
 	for i, option := range aiConfig.PreferredOrder {
 		if aiConfig.LogProviderSelection {
-			log.Printf("Trying AI provider %d/%d: %s (%s)", i+1, len(aiConfig.PreferredOrder), option.Description, option.Type)
+			log.Printf("Evaluating AI provider %d/%d: %s (%s)", i+1, len(aiConfig.PreferredOrder), option.Description, option.Type)
 		}
 
-		// Check if the required environment variable/key is available
-		apiKey := os.Getenv(option.EnvKey)
+		// ProviderTokens/ProviderURLs (config file or PROVIDER_TOKEN/
+		// PROVIDER_URL env vars), keyed by option.Provider, take priority
+		// over the option's own EnvKey/Endpoint.
+		apiKey := resolveProviderValue(aiConfig.ProviderTokens, option.Provider, os.Getenv(option.EnvKey))
+		option.Endpoint = resolveProviderValue(aiConfig.ProviderURLs, option.Provider, option.Endpoint)
+
 		if apiKey == "" && option.Provider != "gollm" || option.Type == "ollama" {
 			// For Ollama, we don't need an API key, just check if endpoint is accessible
 			if option.Type == "ollama" {
@@ -187,7 +461,7 @@ func initializeAIClient() (cloneAttack.AIClient, error) {
 
 		switch option.Provider {
 		case "gollm":
-			client, clientErr = createGollmClient(option, apiKey)
+			client, clientErr = createGollmClient(option, apiKey, pricingTable)
 		case "awsbedrock":
 			client, clientErr = createAWSBedrockClient(option)
 		default:
@@ -204,6 +478,11 @@ func initializeAIClient() (cloneAttack.AIClient, error) {
 			continue
 		}
 
+		// Wrap the client in whatever middleware aiClientConfig.json asks for
+		// before it's ever called, so a panic or hang in testAIClient below
+		// is guarded the same way a real evaluation call would be.
+		client, _ = middleware.Apply(client, aiConfig.Middleware)
+
 		// Test the client with a simple request
 		if testErr := testAIClient(client); testErr != nil {
 			if aiConfig.LogProviderSelection {
@@ -213,28 +492,53 @@ func initializeAIClient() (cloneAttack.AIClient, error) {
 		}
 
 		if aiConfig.LogProviderSelection {
-			log.Printf("Successfully initialized AI client: %s", option.Description)
+			log.Printf("Added AI provider to pool: %s", option.Description)
 		}
-		return client, nil
+		members = append(members, providerpool.Member{
+			Name:           option.Description,
+			Client:         client,
+			Weight:         option.Weight,
+			MaxConcurrency: option.MaxConcurrency,
+		})
 	}
 
 	// If no provider worked and fallback is enabled, try AWS Bedrock
-	if aiConfig.FallbackToBedrock {
+	if len(members) == 0 && aiConfig.FallbackToBedrock {
 		if aiConfig.LogProviderSelection {
 			log.Println("All configured providers failed, falling back to AWS Bedrock")
 		}
-		bedrockClient := awsbedrock.New()
+		bedrockClient, _ := middleware.Apply(awsbedrock.New(), aiConfig.Middleware)
 		if testErr := testAIClient(bedrockClient); testErr != nil {
 			return nil, fmt.Errorf("all AI providers failed, including AWS Bedrock fallback: %w", testErr)
 		}
-		return bedrockClient, nil
+		members = append(members, providerpool.Member{Name: "aws-bedrock-fallback", Client: bedrockClient})
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no AI providers could be initialized - check your API keys and configuration")
 	}
 
-	return nil, fmt.Errorf("no AI providers could be initialized - check your API keys and configuration")
+	return providerpool.New(members, providerpool.Config{
+		CheckInterval: time.Duration(aiConfig.HealthCheckIntervalSeconds) * time.Second,
+	}), nil
 }
 
-// createGollmClient creates a gollm client based on the configuration option
-func createGollmClient(option AIClientOption, apiKey string) (cloneAttack.AIClient, error) {
+// createGollmClient creates a gollm client based on the configuration
+// option, then applies pricingTable so its GenerateAIMetered/
+// GenerateAISchemaMetered calls report USDCost against it instead of
+// pricing.Default().
+func createGollmClient(option AIClientOption, apiKey string, pricingTable pricing.Table) (cloneAttack.AIClient, error) {
+	client, err := createGollmClientUnmetered(option, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	client.SetPricing(pricingTable)
+	return client, nil
+}
+
+// createGollmClientUnmetered builds the *gollmClient.GollmClient itself,
+// before pricingTable is applied.
+func createGollmClientUnmetered(option AIClientOption, apiKey string) (*gollmClient.GollmClient, error) {
 	switch option.Type {
 	case "openai":
 		return gollmClient.NewOpenAIClient(apiKey, option.Model)
@@ -248,6 +552,14 @@ func createGollmClient(option AIClientOption, apiKey string) (cloneAttack.AIClie
 			endpoint = "http://localhost:11434"
 		}
 		return gollmClient.NewOllamaClient(option.Model, endpoint)
+	case "openai-compatible":
+		// Any self-hosted or third-party inference stack that speaks the
+		// OpenAI chat completions API (LM Studio, vLLM, LiteLLM, Together,
+		// DeepInfra, a custom gateway, ...) behind a non-default base URL.
+		if option.Endpoint == "" {
+			return nil, fmt.Errorf("openai-compatible provider %q requires an endpoint (set it on the option or via PROVIDER_URL/providerUrls)", option.Description)
+		}
+		return gollmClient.NewOpenAICompatibleClient(apiKey, option.Model, option.Endpoint)
 	default:
 		// Generic gollm client creation
 		config := gollmClient.Config{
@@ -278,6 +590,14 @@ func testAIClient(client cloneAttack.AIClient) error {
 func init() {
 	rootCmd.AddCommand(evaluateCmd)
 
+	evaluateCmd.Flags().StringVar(&evaluateFormat, "format", "json", "output format for the results file: \"json\" or \"sarif\"")
+	evaluateCmd.Flags().BoolVar(&evaluateStream, "stream", false, "print AI-generated test prompts and analyses live as tokens arrive")
+	evaluateCmd.Flags().StringVar(&evaluatePricing, "pricing", "", "path to a pricing.Table YAML file overriding the default $/1M-token rates used to estimate resource_usage.usdCost")
+	evaluateCmd.Flags().StringVar(&evaluateOutput, "output", "", "additional report to write alongside the JSON results file: \"sarif\" or \"\" (none)")
+	evaluateCmd.Flags().StringVar(&evaluateFailOnSeverity, "fail-on-severity", "", "exit non-zero when any fleet member finds a vulnerability at or above this severity: low, medium, high, or critical")
+	evaluateCmd.Flags().BoolVar(&evaluateAllowIfNoScan, "allow-if-no-scan", false, "with --fail-on-severity, allow a run through when the AI-based analyzer errored on every call instead of failing closed")
+	evaluateCmd.Flags().BoolVar(&evaluateBypassIfNoVulns, "bypass-if-no-vulns", false, "with --fail-on-severity, allow a run through when zero vulnerabilities were found even if the analyzer itself was degraded")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -4,7 +4,10 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"datasnack/cloneAttack"
+	"datasnack/pricing"
+	"datasnack/vulncatalog"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -21,13 +24,18 @@ import (
 
 // PromptConfig represents the structure of the prompt_config.yaml file
 type PromptConfig struct {
-	Version          string                 `yaml:"version"`
-	LastUpdated      string                 `yaml:"last_updated"`
-	Description      string                 `yaml:"description"`
-	OriginalPrompts  map[string]PromptInfo  `yaml:"original_prompts"`
-	ModifiedPrompts  map[string]interface{} `yaml:"modified_prompts"`
-	UsageStats       map[string]interface{} `yaml:"usage_stats"`
-	PromptCategories map[string]Category    `yaml:"prompt_categories"`
+	Version         string                `yaml:"version"`
+	LastUpdated     string                `yaml:"last_updated"`
+	Description     string                `yaml:"description"`
+	OriginalPrompts map[string]PromptInfo `yaml:"original_prompts"`
+
+	// ModifiedPrompts is suggestionsApplyCmd's audit trail: every time it
+	// installs a PromptSuggestion, it appends a ModifiedPromptEntry here
+	// (keyed by prompt name) instead of overwriting OriginalPrompts, so
+	// suggestionsRevertCmd can restore any prior state by timestamp.
+	ModifiedPrompts  map[string][]ModifiedPromptEntry `yaml:"modified_prompts"`
+	UsageStats       map[string]interface{}           `yaml:"usage_stats"`
+	PromptCategories map[string]Category              `yaml:"prompt_categories"`
 }
 
 type PromptInfo struct {
@@ -57,13 +65,21 @@ type EvaluationResults struct {
 }
 
 type Vulnerability struct {
-	Type        string `json:"Type"`
-	Severity    string `json:"Severity"`
-	Description string `json:"Description"`
-	CallID      string `json:"CallID"`
-	Score       int    `json:"Score"`
-	Response    string `json:"Response"`
-	Prompt      string `json:"Prompt"`
+	Type           string `json:"Type"`
+	Severity       string `json:"Severity"`
+	Description    string `json:"Description"`
+	CallID         string `json:"CallID"`
+	Score          int    `json:"Score"`
+	Response       string `json:"Response"`
+	Prompt         string `json:"Prompt"`
+	NormalizedType string `json:"NormalizedType,omitempty"`
+	CWE            string `json:"CWE,omitempty"`
+
+	// Reachability is set by findRelevantVulnerabilities from a
+	// promptUsageGraph: "reachable", "imported-only", or "unused" relative
+	// to whichever prompt this copy of the vulnerability was matched
+	// against. Empty when no usage graph was available to classify it.
+	Reachability string `json:"Reachability,omitempty"`
 }
 
 // PromptSuggestion represents a suggestion for improving a prompt
@@ -73,21 +89,87 @@ type PromptSuggestion struct {
 	SuggestedPrompt    string   `json:"suggested_prompt"`
 	Reasoning          string   `json:"reasoning"`
 	VulnerabilityTypes []string `json:"vulnerability_types"`
+	NormalizedTypes    []string `json:"normalized_types,omitempty"`
 	Severity           string   `json:"severity"`
 	Confidence         float64  `json:"confidence"`
 	Impact             string   `json:"impact"`
+
+	// MatchScore is the cosine similarity embeddingMatcher found between
+	// this prompt and its best-matching relevant vulnerability, so users
+	// can audit why a vulnerability was attributed to this prompt instead
+	// of another. Zero when no embeddingMatcher was available (or none of
+	// its matches cleared --min-similarity).
+	MatchScore float64 `json:"match_score,omitempty"`
 }
 
 // SuggestionsReport represents the complete suggestions report
 type SuggestionsReport struct {
-	GeneratedAt            string             `json:"generated_at"`
-	EvaluationFile         string             `json:"evaluation_file"`
-	PromptConfigFile       string             `json:"prompt_config_file"`
-	TotalVulnerabilities   int                `json:"total_vulnerabilities"`
-	VulnerabilitySummary   map[string]int     `json:"vulnerability_summary"`
-	Suggestions            []PromptSuggestion `json:"suggestions"`
-	OverallRecommendations []string           `json:"overall_recommendations"`
-	AnalysisSummary        string             `json:"analysis_summary"`
+	GeneratedAt                string             `json:"generated_at"`
+	EvaluationFile             string             `json:"evaluation_file"`
+	PromptConfigFile           string             `json:"prompt_config_file"`
+	TotalVulnerabilities       int                `json:"total_vulnerabilities"`
+	VulnerabilitySummary       map[string]int     `json:"vulnerability_summary"`
+	VulnerabilityByLLMCategory map[string]int     `json:"vulnerability_by_llm_category,omitempty"`
+	CWEReferences              map[string]string  `json:"cwe_references,omitempty"`
+	Suggestions                []PromptSuggestion `json:"suggestions"`
+	// UnreachableVulnerabilities holds findings generatePromptSuggestions
+	// skipped because buildPromptUsageGraph found the prompt they matched
+	// unused in the agent's source tree - reported separately rather than
+	// silently dropped, unless --include-unreachable folded them back into
+	// Suggestions instead.
+	UnreachableVulnerabilities []Vulnerability `json:"unreachable_vulnerabilities,omitempty"`
+	OverallRecommendations     []string        `json:"overall_recommendations"`
+	AnalysisSummary            string          `json:"analysis_summary"`
+
+	// NewVulnerabilities, FixedVulnerabilities, and PersistingVulnerabilities
+	// come from diffing this run against --baseline (or its auto-detected
+	// second-most-recent evaluation file): New wasn't present in the
+	// baseline, Fixed was present in the baseline but not here, and
+	// Persisting was present in both. With no baseline, every vulnerability
+	// is listed as New and the other two stay empty. Suggestions only
+	// cover New and Persisting findings, so CI gating via --fail-on doesn't
+	// drown developers in suggestions for findings nothing changed about.
+	NewVulnerabilities        []Vulnerability `json:"new_vulnerabilities,omitempty"`
+	FixedVulnerabilities      []Vulnerability `json:"fixed_vulnerabilities,omitempty"`
+	PersistingVulnerabilities []Vulnerability `json:"persisting_vulnerabilities,omitempty"`
+}
+
+// suggestionsFormat holds the --format flag shared in shape with reportCmd:
+// "json" (the default) writes the full SuggestionsReport; "sarif", "csv",
+// and "md" write the shared findings view instead, via writeFindingsReport.
+var suggestionsFormat string
+
+// includeUnreachable holds --include-unreachable: when false (the
+// default), generatePromptSuggestions skips prompts buildPromptUsageGraph
+// found unused in the agent source tree, moving their findings to
+// SuggestionsReport.UnreachableVulnerabilities instead of generating noisy
+// suggestions for code that's never called.
+var includeUnreachable bool
+
+// minSimilarity and topK hold --min-similarity and --top-k: the cosine
+// similarity threshold and match count embeddingMatcher applies when
+// ranking vulnerabilities against a prompt, replacing the old
+// strings.Contains(...AgentType...) heuristic.
+var minSimilarity float64
+var topK int
+
+// suggestionsBaseline and suggestionsFailOn hold --baseline and --fail-on:
+// the prior evaluation results to diff against (auto-detected as the
+// second-most-recent results/evaluation_*.json file when left empty) and
+// the new-vulnerability severity threshold that makes suggestionsCmd exit
+// non-zero, for CI gating on a pull request's evaluation run.
+var suggestionsBaseline string
+var suggestionsFailOn string
+
+// validFailOnThresholds are the --fail-on values suggestionsCmd accepts;
+// "" disables gating entirely.
+var validFailOnThresholds = map[string]bool{"": true, "any-new": true, "new-medium": true, "new-high": true}
+
+func validateFailOn(failOn string) error {
+	if !validFailOnThresholds[failOn] {
+		return fmt.Errorf("unsupported --fail-on %q: must be one of any-new, new-medium, new-high", failOn)
+	}
+	return nil
 }
 
 // go run . suggestions
@@ -102,8 +184,22 @@ The command:
 2. Loads the prompt_config.yaml from the agent's root folder
 3. Analyzes vulnerabilities and performance issues
 4. Generates specific suggestions for prompt improvements
-5. Saves suggestions to a prompt_suggestions_*.json file`,
+5. Saves suggestions to a prompt_suggestions_*.json file (or, with --format, a
+   sarif/csv/md rendering of the same vulnerabilities and suggestions)
+
+With --baseline (or its auto-detected second-most-recent evaluation file),
+vulnerabilities are diffed against that prior run and suggestions only cover
+new or still-persisting findings; --fail-on exits non-zero when new findings
+meet a severity threshold (any-new, new-medium, new-high), for gating CI on
+a pull request's evaluation run.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateReportFormat(suggestionsFormat); err != nil {
+			log.Fatalln(err)
+		}
+		if err := validateFailOn(suggestionsFailOn); err != nil {
+			log.Fatalln(err)
+		}
+
 		// Load agent configuration to get the root folder
 		configPath := os.Getenv("AGENT_CONFIG")
 		if configPath == "" {
@@ -136,6 +232,46 @@ The command:
 			log.Fatalln("Failed to load evaluation results:", err)
 		}
 
+		// Load the vuln taxonomy and normalize each vulnerability's free-text
+		// Type against it before anything downstream groups by type.
+		catalog, err := loadVulnCatalog()
+		if err != nil {
+			log.Fatalln("Failed to load vuln taxonomy:", err)
+		}
+		normalizeVulnerabilities(evaluationResults, catalog)
+
+		// Diff against a baseline evaluation run, so suggestions only cover
+		// vulnerabilities that are new or still persisting rather than
+		// every finding this agent has ever had. --baseline picks the file
+		// explicitly; left empty, the second-most-recent results/evaluation_*.json
+		// is used if one exists.
+		baselinePath := suggestionsBaseline
+		if baselinePath == "" {
+			if path, err := findBaselineEvaluationFile(resultsDir); err == nil {
+				baselinePath = path
+			}
+		}
+
+		var delta vulnerabilityDelta
+		if baselinePath != "" {
+			log.Printf("Diffing against baseline: %s", baselinePath)
+			baselineResults, err := loadEvaluationResults(baselinePath)
+			if err != nil {
+				log.Fatalln("Failed to load baseline evaluation results:", err)
+			}
+			normalizeVulnerabilities(baselineResults, catalog)
+			delta = diffVulnerabilities(evaluationResults.Vulnerabilities, baselineResults.Vulnerabilities)
+			log.Printf("Vulnerabilities: %d new, %d fixed, %d persisting", len(delta.New), len(delta.Fixed), len(delta.Persisting))
+		} else {
+			log.Println("No baseline evaluation found, treating every vulnerability as new")
+			delta = vulnerabilityDelta{New: evaluationResults.Vulnerabilities}
+		}
+
+		// Only new/persisting findings generate suggestions; fixed findings
+		// shouldn't resurface as noise once they're gone.
+		actionableResults := *evaluationResults
+		actionableResults.Vulnerabilities = append(append([]Vulnerability{}, delta.New...), delta.Persisting...)
+
 		// Load prompt configuration
 		promptConfigPath := filepath.Join(agentConfig.AgentRootFolder, "backend", "evaluation", "config", "prompt_config.yaml")
 		log.Printf("Loading prompt config from: %s", promptConfigPath)
@@ -145,60 +281,126 @@ The command:
 			log.Fatalln("Failed to load prompt config:", err)
 		}
 
+		// Build the prompt usage graph for reachability filtering. A scan
+		// failure (e.g. AgentRootFolder missing) isn't fatal: usage stays
+		// nil, and every prompt is treated as reachable.
+		var usage *promptUsageGraph
+		if !includeUnreachable {
+			usage, err = buildPromptUsageGraph(agentConfig.AgentRootFolder, promptConfig)
+			if err != nil {
+				log.Printf("Failed to scan agent source tree for prompt usage, skipping reachability filtering: %v", err)
+				usage = nil
+			}
+		}
+
 		// Initialize AI client for generating suggestions
-		ai, err := initializeAIClient()
+		pricingTable, err := pricing.Default()
+		if err != nil {
+			log.Fatalln("Failed to load default pricing table:", err)
+		}
+		ai, err := initializeAIClient(pricingTable)
 		if err != nil {
 			log.Fatalln("Failed to initialize AI client:", err)
 		}
 
+		// Initialize the embedding matcher that ranks vulnerabilities
+		// against each prompt by cosine similarity, persisting embeddings
+		// to resultsDir/embeddings_cache.json so repeat runs reuse them.
+		embedder, err := initializeEmbedder()
+		if err != nil {
+			log.Fatalln("Failed to initialize embedder:", err)
+		}
+		matcher := newEmbeddingMatcher(embedder, filepath.Join(resultsDir, "embeddings_cache.json"), minSimilarity, topK)
+		ctx := context.Background()
+
 		// Generate suggestions
 		log.Println("Analyzing evaluation results and generating suggestions...")
-		suggestions, err := generatePromptSuggestions(evaluationResults, promptConfig, ai)
+		suggestions, unreachableVulns, err := generatePromptSuggestions(ctx, &actionableResults, promptConfig, ai, usage, includeUnreachable, matcher)
 		if err != nil {
 			log.Fatalln("Failed to generate suggestions:", err)
 		}
+		if len(unreachableVulns) > 0 {
+			log.Printf("Skipped %d vulnerabilities for prompts unused in the agent source tree (see --include-unreachable)", len(unreachableVulns))
+		}
+		if err := matcher.cache.save(); err != nil {
+			log.Printf("Failed to save embeddings cache: %v", err)
+		}
 
-		// Create suggestions report
-		report := createSuggestionsReport(evaluationFile, promptConfigPath, evaluationResults, suggestions)
-
-		// Save suggestions to file
 		timestamp := time.Now().Format("20060102_150405")
-		filename := fmt.Sprintf("results/prompt_suggestions_%s.json", timestamp)
+		basePath := fmt.Sprintf("results/prompt_suggestions_%s", timestamp)
 
-		reportJSON, err := json.MarshalIndent(report, "", "  ")
-		if err != nil {
-			log.Fatalln("Failed to marshal suggestions report:", err)
-		}
+		var filename string
+		if suggestionsFormat == "json" {
+			report := createSuggestionsReport(evaluationFile, promptConfigPath, evaluationResults, suggestions, unreachableVulns, delta)
 
-		if err := os.WriteFile(filename, reportJSON, 0644); err != nil {
-			log.Fatalln("Failed to write suggestions file:", err)
+			reportJSON, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalln("Failed to marshal suggestions report:", err)
+			}
+
+			filename = basePath + ".json"
+			if err := os.WriteFile(filename, reportJSON, 0644); err != nil {
+				log.Fatalln("Failed to write suggestions file:", err)
+			}
+		} else {
+			findings := buildFindings(evaluationResults, suggestions, promptConfig)
+			filename, err = writeFindingsReport(suggestionsFormat, basePath, findings)
+			if err != nil {
+				log.Fatalln("Failed to write suggestions file:", err)
+			}
 		}
 
 		log.Printf("Suggestions saved to: %s", filename)
 		log.Printf("Generated %d prompt suggestions based on %d vulnerabilities", len(suggestions), len(evaluationResults.Vulnerabilities))
+
+		if failOnThresholdMet(delta, suggestionsFailOn) {
+			log.Fatalf("Failing: %d new vulnerabilities meet --fail-on=%s", len(delta.New), suggestionsFailOn)
+		}
 	},
 }
 
 // findMostRecentEvaluationFile finds the most recent evaluation_results_*.json file
 func findMostRecentEvaluationFile(resultsDir string) (string, error) {
+	files, err := listEvaluationFilesByRecency(resultsDir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no evaluation results files found in %s", resultsDir)
+	}
+	return files[0], nil
+}
+
+// findBaselineEvaluationFile returns the second-most-recent
+// evaluation_results_*.json file in resultsDir, for auto-detecting a
+// --baseline when the flag is left empty.
+func findBaselineEvaluationFile(resultsDir string) (string, error) {
+	files, err := listEvaluationFilesByRecency(resultsDir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) < 2 {
+		return "", fmt.Errorf("no prior evaluation results file found in %s to diff against", resultsDir)
+	}
+	return files[1], nil
+}
+
+// listEvaluationFilesByRecency lists every evaluation_*.json file in
+// resultsDir, most recently modified first.
+func listEvaluationFilesByRecency(resultsDir string) ([]string, error) {
 	var files []string
 
 	err := filepath.WalkDir(resultsDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && strings.HasPrefix(d.Name(), "evaluation_results_") && strings.HasSuffix(d.Name(), ".json") {
+		if !d.IsDir() && strings.HasPrefix(d.Name(), "evaluation_") && strings.HasSuffix(d.Name(), ".json") {
 			files = append(files, path)
 		}
 		return nil
 	})
-
 	if err != nil {
-		return "", err
-	}
-
-	if len(files) == 0 {
-		return "", fmt.Errorf("no evaluation results files found in %s", resultsDir)
+		return nil, err
 	}
 
 	// Sort by modification time (most recent first)
@@ -208,7 +410,7 @@ func findMostRecentEvaluationFile(resultsDir string) (string, error) {
 		return info1.ModTime().After(info2.ModTime())
 	})
 
-	return files[0], nil
+	return files, nil
 }
 
 // loadEvaluationResults loads evaluation results from a JSON file
@@ -226,6 +428,38 @@ func loadEvaluationResults(filename string) (*EvaluationResults, error) {
 	return &results, nil
 }
 
+// loadVulnCatalog loads the vuln taxonomy from VULN_TAXONOMY_PATH (or
+// config/vuln_taxonomy.yaml, if that file exists), falling back to
+// vulncatalog's embedded default table otherwise.
+func loadVulnCatalog() (*vulncatalog.VulnCatalog, error) {
+	path := os.Getenv("VULN_TAXONOMY_PATH")
+	if path == "" {
+		path = "config/vuln_taxonomy.yaml"
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return vulncatalog.Load(path)
+	}
+	return vulncatalog.Default()
+}
+
+// normalizeVulnerabilities sets NormalizedType and CWE on every vulnerability
+// in results whose Type or Description catalog recognizes, so downstream
+// grouping (the suggestions report's vulnerability_by_llm_category, a SARIF
+// or dashboard export) can key on a stable taxonomy code instead of the AI's
+// ad-hoc Type string.
+func normalizeVulnerabilities(results *EvaluationResults, catalog *vulncatalog.VulnCatalog) {
+	for i := range results.Vulnerabilities {
+		vuln := &results.Vulnerabilities[i]
+		normalizedType, cwe := catalog.Normalize(vuln.Type)
+		if normalizedType == "" {
+			normalizedType, cwe = catalog.Normalize(vuln.Description)
+		}
+		vuln.NormalizedType = normalizedType
+		vuln.CWE = cwe
+	}
+}
+
 // loadPromptConfig loads prompt configuration from a YAML file
 func loadPromptConfig(filename string) (*PromptConfig, error) {
 	data, err := os.ReadFile(filename)
@@ -241,10 +475,14 @@ func loadPromptConfig(filename string) (*PromptConfig, error) {
 	return &config, nil
 }
 
-// generatePromptSuggestions generates suggestions based on evaluation results and prompt config
-func generatePromptSuggestions(results *EvaluationResults, config *PromptConfig, ai cloneAttack.AIClient) ([]PromptSuggestion, error) {
-	var suggestions []PromptSuggestion
-
+// generatePromptSuggestions generates suggestions based on evaluation results and prompt config.
+// usage classifies each prompt's reachability in the agent source tree (nil
+// if buildPromptUsageGraph couldn't run); unless includeUnreachable is set,
+// a prompt whose usage.classify is unusedStatus is skipped entirely and its
+// would-be relevant vulnerabilities are returned in unreachable instead.
+// matcher ranks each prompt's relevant vulnerabilities by embedding cosine
+// similarity rather than the old AgentType substring heuristic.
+func generatePromptSuggestions(ctx context.Context, results *EvaluationResults, config *PromptConfig, ai cloneAttack.AIClient, usage *promptUsageGraph, includeUnreachable bool, matcher *embeddingMatcher) (suggestions []PromptSuggestion, unreachable []Vulnerability, err error) {
 	// Analyze vulnerabilities by type
 	vulnerabilityTypes := make(map[string]int)
 	for _, vuln := range results.Vulnerabilities {
@@ -256,32 +494,46 @@ func generatePromptSuggestions(results *EvaluationResults, config *PromptConfig,
 		log.Printf("Analyzing category: %s", categoryName)
 
 		for _, promptName := range category.Prompts {
-			if promptInfo, exists := config.OriginalPrompts[promptName]; exists {
-				suggestion, err := generateSuggestionForPrompt(promptName, promptInfo, results, ai)
-				if err != nil {
-					log.Printf("Failed to generate suggestion for %s: %v", promptName, err)
-					continue
-				}
+			promptInfo, exists := config.OriginalPrompts[promptName]
+			if !exists {
+				continue
+			}
 
-				if suggestion != nil {
-					suggestions = append(suggestions, *suggestion)
-				}
+			relevantVulns, matchScore, findErr := findRelevantVulnerabilities(ctx, promptName, promptInfo, results, usage, matcher)
+			if findErr != nil {
+				log.Printf("Failed to match vulnerabilities for %s: %v", promptName, findErr)
+				continue
+			}
+			if len(relevantVulns) == 0 {
+				continue
+			}
+
+			if !includeUnreachable && usage != nil && usage.classify(promptName) == unusedStatus {
+				log.Printf("Skipping %s: prompt not referenced anywhere in %s", promptName, promptInfo.Location)
+				unreachable = append(unreachable, relevantVulns...)
+				continue
+			}
+
+			suggestion, genErr := generateSuggestionForPrompt(promptName, promptInfo, relevantVulns, ai, matchScore)
+			if genErr != nil {
+				log.Printf("Failed to generate suggestion for %s: %v", promptName, genErr)
+				continue
+			}
+
+			if suggestion != nil {
+				suggestions = append(suggestions, *suggestion)
 			}
 		}
 	}
 
-	return suggestions, nil
+	return suggestions, unreachable, nil
 }
 
 // generateSuggestionForPrompt generates a suggestion for a specific prompt
-func generateSuggestionForPrompt(promptName string, promptInfo PromptInfo, results *EvaluationResults, ai cloneAttack.AIClient) (*PromptSuggestion, error) {
-	// Find relevant vulnerabilities for this prompt type
-	relevantVulns := findRelevantVulnerabilities(promptName, promptInfo, results)
-
-	if len(relevantVulns) == 0 {
-		return nil, nil // No relevant vulnerabilities found
-	}
-
+// from its already-filtered relevantVulns, recording matchScore (the best
+// cosine similarity findRelevantVulnerabilities found for this prompt) onto
+// the resulting PromptSuggestion.
+func generateSuggestionForPrompt(promptName string, promptInfo PromptInfo, relevantVulns []Vulnerability, ai cloneAttack.AIClient, matchScore float64) (*PromptSuggestion, error) {
 	// Create analysis prompt for AI
 	analysisPrompt := createAnalysisPrompt(promptName, promptInfo, relevantVulns)
 
@@ -293,24 +545,28 @@ func generateSuggestionForPrompt(promptName string, promptInfo PromptInfo, resul
 
 	// Parse AI response to extract suggestion
 	suggestion := parseAISuggestion(promptName, promptInfo.Prompt, response, relevantVulns)
+	suggestion.MatchScore = matchScore
 
 	return suggestion, nil
 }
 
-// findRelevantVulnerabilities finds vulnerabilities relevant to a specific prompt
-func findRelevantVulnerabilities(promptName string, promptInfo PromptInfo, results *EvaluationResults) []Vulnerability {
-	var relevant []Vulnerability
+// findRelevantVulnerabilities ranks results' vulnerabilities against
+// promptInfo by embedding cosine similarity via matcher, tagging each
+// returned copy's Reachability from usage (if set). When nothing clears
+// matcher's --min-similarity threshold, it falls back to high/medium
+// severity vulnerabilities (score 0) so a prompt with no close semantic
+// match still gets a suggestion instead of silently dropping out.
+func findRelevantVulnerabilities(ctx context.Context, promptName string, promptInfo PromptInfo, results *EvaluationResults, usage *promptUsageGraph, matcher *embeddingMatcher) ([]Vulnerability, float64, error) {
+	reachability := ""
+	if usage != nil {
+		reachability = usage.classify(promptName)
+	}
 
-	// Simple heuristic: look for vulnerabilities that might be related to this prompt type
-	for _, vuln := range results.Vulnerabilities {
-		// Check if vulnerability type matches prompt agent type or category
-		if strings.Contains(strings.ToLower(vuln.Type), strings.ToLower(promptInfo.AgentType)) ||
-			strings.Contains(strings.ToLower(vuln.Description), strings.ToLower(promptInfo.AgentType)) {
-			relevant = append(relevant, vuln)
-		}
+	relevant, bestScore, err := matcher.rankRelevantVulnerabilities(ctx, promptInfo, results.Vulnerabilities)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// If no specific matches, include high-severity vulnerabilities
 	if len(relevant) == 0 {
 		for _, vuln := range results.Vulnerabilities {
 			if vuln.Severity == "high" || vuln.Severity == "medium" {
@@ -319,7 +575,11 @@ func findRelevantVulnerabilities(promptName string, promptInfo PromptInfo, resul
 		}
 	}
 
-	return relevant
+	for i := range relevant {
+		relevant[i].Reachability = reachability
+	}
+
+	return relevant, bestScore, nil
 }
 
 // createAnalysisPrompt creates a prompt for AI analysis
@@ -378,11 +638,17 @@ func parseAISuggestion(promptName, currentPrompt, aiResponse string, vulnerabili
 			// Extract vulnerability types
 			vulnTypes := make([]string, 0)
 			vulnTypeMap := make(map[string]bool)
+			normalizedTypes := make([]string, 0)
+			normalizedTypeMap := make(map[string]bool)
 			for _, vuln := range vulnerabilities {
 				if !vulnTypeMap[vuln.Type] {
 					vulnTypes = append(vulnTypes, vuln.Type)
 					vulnTypeMap[vuln.Type] = true
 				}
+				if vuln.NormalizedType != "" && !normalizedTypeMap[vuln.NormalizedType] {
+					normalizedTypes = append(normalizedTypes, vuln.NormalizedType)
+					normalizedTypeMap[vuln.NormalizedType] = true
+				}
 			}
 
 			// Determine severity based on vulnerabilities
@@ -402,6 +668,7 @@ func parseAISuggestion(promptName, currentPrompt, aiResponse string, vulnerabili
 				SuggestedPrompt:    suggestionData.SuggestedPrompt,
 				Reasoning:          suggestionData.Reasoning,
 				VulnerabilityTypes: vulnTypes,
+				NormalizedTypes:    normalizedTypes,
 				Severity:           severity,
 				Confidence:         suggestionData.Confidence,
 				Impact:             suggestionData.Impact,
@@ -423,11 +690,19 @@ func parseAISuggestion(promptName, currentPrompt, aiResponse string, vulnerabili
 }
 
 // createSuggestionsReport creates the complete suggestions report
-func createSuggestionsReport(evaluationFile, promptConfigFile string, results *EvaluationResults, suggestions []PromptSuggestion) *SuggestionsReport {
+func createSuggestionsReport(evaluationFile, promptConfigFile string, results *EvaluationResults, suggestions []PromptSuggestion, unreachable []Vulnerability, delta vulnerabilityDelta) *SuggestionsReport {
 	// Create vulnerability summary
 	vulnSummary := make(map[string]int)
+	byLLMCategory := make(map[string]int)
+	cweReferences := make(map[string]string)
 	for _, vuln := range results.Vulnerabilities {
 		vulnSummary[vuln.Type]++
+		if vuln.NormalizedType != "" {
+			byLLMCategory[vuln.NormalizedType]++
+			if vuln.CWE != "" {
+				cweReferences[vuln.NormalizedType] = vuln.CWE
+			}
+		}
 	}
 
 	// Generate overall recommendations
@@ -447,17 +722,29 @@ func createSuggestionsReport(evaluationFile, promptConfigFile string, results *E
 		vulnSummary["medium"])
 
 	return &SuggestionsReport{
-		GeneratedAt:            time.Now().Format(time.RFC3339),
-		EvaluationFile:         evaluationFile,
-		PromptConfigFile:       promptConfigFile,
-		TotalVulnerabilities:   len(results.Vulnerabilities),
-		VulnerabilitySummary:   vulnSummary,
-		Suggestions:            suggestions,
-		OverallRecommendations: recommendations,
-		AnalysisSummary:        summary,
+		GeneratedAt:                time.Now().Format(time.RFC3339),
+		EvaluationFile:             evaluationFile,
+		PromptConfigFile:           promptConfigFile,
+		TotalVulnerabilities:       len(results.Vulnerabilities),
+		VulnerabilitySummary:       vulnSummary,
+		VulnerabilityByLLMCategory: byLLMCategory,
+		CWEReferences:              cweReferences,
+		Suggestions:                suggestions,
+		UnreachableVulnerabilities: unreachable,
+		OverallRecommendations:     recommendations,
+		AnalysisSummary:            summary,
+		NewVulnerabilities:         delta.New,
+		FixedVulnerabilities:       delta.Fixed,
+		PersistingVulnerabilities:  delta.Persisting,
 	}
 }
 
 func init() {
+	suggestionsCmd.Flags().StringVar(&suggestionsFormat, "format", "json", "output format: json, sarif, csv, md")
+	suggestionsCmd.Flags().BoolVar(&includeUnreachable, "include-unreachable", false, "include suggestions for prompts unused in the agent source tree, restoring pre-reachability-filtering behavior")
+	suggestionsCmd.Flags().Float64Var(&minSimilarity, "min-similarity", 0.5, "minimum cosine similarity for a vulnerability to be considered relevant to a prompt")
+	suggestionsCmd.Flags().IntVar(&topK, "top-k", 5, "maximum number of vulnerabilities matched per prompt")
+	suggestionsCmd.Flags().StringVar(&suggestionsBaseline, "baseline", "", "prior evaluation_results_*.json to diff against (defaults to the second-most-recent file in results/)")
+	suggestionsCmd.Flags().StringVar(&suggestionsFailOn, "fail-on", "", "exit non-zero when new vulnerabilities meet this threshold: any-new, new-medium, or new-high")
 	rootCmd.AddCommand(suggestionsCmd)
 }
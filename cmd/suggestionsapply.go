@@ -0,0 +1,399 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ModifiedPromptEntry records one application of a suggested prompt change
+// to prompt_config.yaml's ModifiedPrompts history, for suggestionsApplyCmd's
+// audit trail and suggestionsRevertCmd's restore.
+type ModifiedPromptEntry struct {
+	Original           string  `yaml:"original" json:"original"`
+	Applied            string  `yaml:"applied" json:"applied"`
+	AppliedAt          string  `yaml:"applied_at" json:"applied_at"`
+	SourceSuggestionID string  `yaml:"source_suggestion_id" json:"source_suggestion_id"`
+	Confidence         float64 `yaml:"confidence" json:"confidence"`
+}
+
+// severityRank orders Vulnerability/Suggestion severities for --severity
+// filtering and --verify's regression check; unrecognized severities rank
+// below "low".
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 3
+	case "high":
+		return 2
+	case "medium":
+		return 1
+	case "low":
+		return 0
+	default:
+		return -1
+	}
+}
+
+// applyMinConfidence, applySeverities, applySuggestionsFile, and applyVerify
+// back suggestionsApplyCmd's flags.
+var applyMinConfidence float64
+var applySeverities []string
+var applySuggestionsFile string
+var applyVerify bool
+
+// suggestionID derives a stable identifier for a PromptSuggestion, recorded
+// as ModifiedPromptEntry.SourceSuggestionID so an applied change can be
+// traced back to the suggestion that produced it.
+func suggestionID(s PromptSuggestion) string {
+	sum := sha256.Sum256([]byte(s.PromptName + "|" + s.CurrentPrompt + "|" + s.SuggestedPrompt))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// go run . suggestions apply
+var suggestionsApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Install a prompt_suggestions_*.json report's suggested prompts into prompt_config.yaml",
+	Long: `Reads a prompt_suggestions_*.json report and installs each suggestion meeting
+--min-confidence and --severity into the agent's prompt_config.yaml and the
+PromptInfo.Location file it points to, recording the change (original,
+applied, applied_at, source_suggestion_id, confidence) in
+PromptConfig.ModifiedPrompts for audit and later "suggestions revert".
+
+Every edited file gets a .bak written alongside it before the change.
+With --verify, a fresh evaluation runs immediately afterward; if it
+introduces any vulnerability at or above the severity of what the applied
+suggestions claimed to fix, every change from this run is rolled back
+automatically.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, sev := range applySeverities {
+			if severityRank(sev) < 0 {
+				log.Fatalf("unsupported --severity %q: must be one of critical, high, medium, low", sev)
+			}
+		}
+
+		configPath := os.Getenv("AGENT_CONFIG")
+		if configPath == "" {
+			configPath = "config/agentConfig.json"
+		}
+		configData, err := os.ReadFile(configPath)
+		if err != nil {
+			log.Fatalln("Failed to read agent config file:", err)
+		}
+		var agentConfig PythonAgentConfig
+		if err := json.Unmarshal(configData, &agentConfig); err != nil {
+			log.Fatalln("Failed to unmarshal agent config:", err)
+		}
+
+		resultsDir := "results"
+		suggestionsFile := applySuggestionsFile
+		if suggestionsFile == "" {
+			suggestionsFile, err = findMostRecentSuggestionsFile(resultsDir)
+			if err != nil {
+				log.Fatalln("Failed to find prompt suggestions file:", err)
+			}
+		}
+		log.Printf("Applying suggestions from: %s", suggestionsFile)
+
+		report, err := loadSuggestionsReport(suggestionsFile)
+		if err != nil {
+			log.Fatalln("Failed to load prompt suggestions:", err)
+		}
+
+		promptConfigPath := filepath.Join(agentConfig.AgentRootFolder, "backend", "evaluation", "config", "prompt_config.yaml")
+		promptConfig, err := loadPromptConfig(promptConfigPath)
+		if err != nil {
+			log.Fatalln("Failed to load prompt config:", err)
+		}
+
+		var applied []PromptSuggestion
+		appliedAt := time.Now().Format(time.RFC3339)
+
+		for _, suggestion := range report.Suggestions {
+			if suggestion.Confidence < applyMinConfidence {
+				continue
+			}
+			if len(applySeverities) > 0 && !containsString(applySeverities, suggestion.Severity) {
+				continue
+			}
+			if suggestion.SuggestedPrompt == "" || suggestion.SuggestedPrompt == suggestion.CurrentPrompt {
+				continue
+			}
+
+			info, exists := promptConfig.OriginalPrompts[suggestion.PromptName]
+			if !exists {
+				log.Printf("Skipping %s: not found in prompt_config.yaml", suggestion.PromptName)
+				continue
+			}
+
+			if info.Location != "" {
+				locationPath := filepath.Join(agentConfig.AgentRootFolder, info.Location)
+				if err := applySuggestionToFile(locationPath, suggestion.CurrentPrompt, suggestion.SuggestedPrompt); err != nil {
+					log.Printf("Skipping %s: %v", suggestion.PromptName, err)
+					continue
+				}
+			}
+
+			entry := ModifiedPromptEntry{
+				Original:           info.Prompt,
+				Applied:            suggestion.SuggestedPrompt,
+				AppliedAt:          appliedAt,
+				SourceSuggestionID: suggestionID(suggestion),
+				Confidence:         suggestion.Confidence,
+			}
+			if promptConfig.ModifiedPrompts == nil {
+				promptConfig.ModifiedPrompts = make(map[string][]ModifiedPromptEntry)
+			}
+			promptConfig.ModifiedPrompts[suggestion.PromptName] = append(promptConfig.ModifiedPrompts[suggestion.PromptName], entry)
+
+			info.Prompt = suggestion.SuggestedPrompt
+			promptConfig.OriginalPrompts[suggestion.PromptName] = info
+
+			applied = append(applied, suggestion)
+			log.Printf("Applied suggestion to %s (confidence %.2f)", suggestion.PromptName, suggestion.Confidence)
+		}
+
+		if len(applied) == 0 {
+			log.Println("No suggestions met --min-confidence/--severity; nothing applied")
+			return
+		}
+
+		if err := backupFile(promptConfigPath); err != nil {
+			log.Fatalln("Failed to back up prompt config:", err)
+		}
+		if err := savePromptConfig(promptConfigPath, promptConfig); err != nil {
+			log.Fatalln("Failed to write prompt config:", err)
+		}
+		log.Printf("Applied %d suggestions to %s", len(applied), promptConfigPath)
+
+		if !applyVerify {
+			return
+		}
+
+		if verifyAndRollbackOnRegression(resultsDir, agentConfig, promptConfig, promptConfigPath, applied, appliedAt) {
+			log.Fatalln("Verification found regressions; rolled back applied suggestions")
+		}
+	},
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// applySuggestionToFile replaces the first occurrence of currentPrompt with
+// suggestedPrompt in the file at path, after writing a .bak of its current
+// contents. Returns an error (and makes no change) if currentPrompt isn't
+// found verbatim in the file, since a silent no-op would make the applied
+// PromptSuggestion inaccurate about what actually changed on disk.
+func applySuggestionToFile(path, currentPrompt, suggestedPrompt string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	contents := string(data)
+	if !strings.Contains(contents, currentPrompt) {
+		return fmt.Errorf("current prompt text not found verbatim in %s", path)
+	}
+
+	if err := backupFile(path); err != nil {
+		return err
+	}
+
+	updated := strings.Replace(contents, currentPrompt, suggestedPrompt, 1)
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// backupFile writes path's current contents to path+".bak", overwriting any
+// prior backup - it's a last-resort undo of the most recent edit, not the
+// audit trail (that's ModifiedPrompts).
+func backupFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s for backup: %w", path, err)
+	}
+	return os.WriteFile(path+".bak", data, 0644)
+}
+
+// savePromptConfig marshals config back to path as YAML.
+func savePromptConfig(path string, config *PromptConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// verifyAndRollbackOnRegression runs a fresh evaluation (evaluateCmd.Run)
+// and compares its vulnerabilities against the most recent evaluation
+// results from before this apply, for each prompt suggestionsApplyCmd just
+// applied. If the new run introduces any vulnerability at or above the
+// highest severity among the vulnerabilities applied targeted, every change
+// from this run is rolled back via restoreModifiedPrompts, and true is
+// returned so the caller can report failure.
+func verifyAndRollbackOnRegression(resultsDir string, agentConfig PythonAgentConfig, promptConfig *PromptConfig, promptConfigPath string, applied []PromptSuggestion, appliedAt string) bool {
+	preApplyFile, preApplyErr := findMostRecentEvaluationFile(resultsDir)
+
+	highestTargeted := 0
+	for _, s := range applied {
+		if r := severityRank(s.Severity); r > highestTargeted {
+			highestTargeted = r
+		}
+	}
+
+	log.Println("Running a fresh evaluation to verify the applied suggestions...")
+	evaluateCmd.Run(nil, nil)
+
+	postApplyFile, err := findMostRecentEvaluationFile(resultsDir)
+	if err != nil {
+		log.Printf("Failed to find post-verify evaluation results, skipping regression check: %v", err)
+		return false
+	}
+	postApplyResults, err := loadEvaluationResults(postApplyFile)
+	if err != nil {
+		log.Printf("Failed to load post-verify evaluation results, skipping regression check: %v", err)
+		return false
+	}
+
+	var preApplyVulns []Vulnerability
+	if preApplyErr == nil && preApplyFile != postApplyFile {
+		if preApplyResults, err := loadEvaluationResults(preApplyFile); err == nil {
+			preApplyVulns = preApplyResults.Vulnerabilities
+		}
+	}
+
+	delta := diffVulnerabilities(postApplyResults.Vulnerabilities, preApplyVulns)
+	for _, v := range delta.New {
+		if severityRank(v.Severity) >= highestTargeted {
+			log.Printf("Regression: new %s severity vulnerability %q after applying suggestions", v.Severity, v.Type)
+			if err := restoreModifiedPrompts(agentConfig, promptConfig, promptConfigPath, appliedAt); err != nil {
+				log.Printf("Failed to roll back applied suggestions: %v", err)
+			}
+			return true
+		}
+	}
+
+	log.Println("Verification found no regressions; keeping applied suggestions")
+	return false
+}
+
+// revertToTimestamp backs suggestionsRevertCmd's --to flag.
+var revertToTimestamp string
+
+// go run . suggestions revert
+var suggestionsRevertCmd = &cobra.Command{
+	Use:   "revert",
+	Short: "Restore prompt_config.yaml and its prompt files to their state before a suggestions apply run",
+	Long: `Restores every prompt whose most recent PromptConfig.ModifiedPrompts entry was
+applied at --to (an RFC3339 timestamp, as printed by "suggestions apply" or
+present in applied_at), setting OriginalPrompts and its PromptInfo.Location
+file back to that entry's Original text and removing the entry from history.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if revertToTimestamp == "" {
+			log.Fatalln("--to is required: pass the applied_at timestamp to revert")
+		}
+
+		configPath := os.Getenv("AGENT_CONFIG")
+		if configPath == "" {
+			configPath = "config/agentConfig.json"
+		}
+		configData, err := os.ReadFile(configPath)
+		if err != nil {
+			log.Fatalln("Failed to read agent config file:", err)
+		}
+		var agentConfig PythonAgentConfig
+		if err := json.Unmarshal(configData, &agentConfig); err != nil {
+			log.Fatalln("Failed to unmarshal agent config:", err)
+		}
+
+		promptConfigPath := filepath.Join(agentConfig.AgentRootFolder, "backend", "evaluation", "config", "prompt_config.yaml")
+		promptConfig, err := loadPromptConfig(promptConfigPath)
+		if err != nil {
+			log.Fatalln("Failed to load prompt config:", err)
+		}
+
+		if err := restoreModifiedPrompts(agentConfig, promptConfig, promptConfigPath, revertToTimestamp); err != nil {
+			log.Fatalln("Failed to revert:", err)
+		}
+	},
+}
+
+// restoreModifiedPrompts restores every prompt in config whose most recent
+// ModifiedPrompts entry's AppliedAt equals to, setting its PromptInfo.Prompt
+// and Location file back to that entry's Original text, popping the entry
+// from history, and saving config back to configPath.
+func restoreModifiedPrompts(agentConfig PythonAgentConfig, config *PromptConfig, configPath, to string) error {
+	names := make([]string, 0, len(config.ModifiedPrompts))
+	for name := range config.ModifiedPrompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	restored := 0
+	for _, name := range names {
+		history := config.ModifiedPrompts[name]
+		if len(history) == 0 {
+			continue
+		}
+		last := history[len(history)-1]
+		if last.AppliedAt != to {
+			continue
+		}
+
+		info, exists := config.OriginalPrompts[name]
+		if !exists {
+			continue
+		}
+
+		if info.Location != "" {
+			locationPath := filepath.Join(agentConfig.AgentRootFolder, info.Location)
+			if err := applySuggestionToFile(locationPath, last.Applied, last.Original); err != nil {
+				log.Printf("Failed to restore %s on disk, reverting config only: %v", name, err)
+			}
+		}
+
+		info.Prompt = last.Original
+		config.OriginalPrompts[name] = info
+		config.ModifiedPrompts[name] = history[:len(history)-1]
+		restored++
+		log.Printf("Reverted %s to its state before %s", name, to)
+	}
+
+	if restored == 0 {
+		return fmt.Errorf("no ModifiedPrompts entry found with applied_at %q", to)
+	}
+
+	if err := backupFile(configPath); err != nil {
+		return err
+	}
+	return savePromptConfig(configPath, config)
+}
+
+func init() {
+	suggestionsApplyCmd.Flags().Float64Var(&applyMinConfidence, "min-confidence", 0.0, "only apply suggestions with at least this confidence")
+	suggestionsApplyCmd.Flags().StringSliceVar(&applySeverities, "severity", nil, "only apply suggestions of these severities (critical, high, medium, low); defaults to all")
+	suggestionsApplyCmd.Flags().StringVar(&applySuggestionsFile, "suggestions", "", "prompt_suggestions_*.json to apply (defaults to the most recent in results/)")
+	suggestionsApplyCmd.Flags().BoolVar(&applyVerify, "verify", false, "run a fresh evaluation after applying and roll back automatically on regression")
+	suggestionsCmd.AddCommand(suggestionsApplyCmd)
+
+	suggestionsRevertCmd.Flags().StringVar(&revertToTimestamp, "to", "", "applied_at timestamp (RFC3339) to revert to")
+	suggestionsCmd.AddCommand(suggestionsRevertCmd)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// fingerprintVulnerability derives a stable identity for v across runs:
+// Type|Severity|normalized(Prompt)|normalized(Response), hashed so
+// diffVulnerabilities can tell a persisting finding from a new one despite
+// insignificant whitespace differences in the AI's Prompt/Response text.
+func fingerprintVulnerability(v Vulnerability) string {
+	text := v.Type + "|" + v.Severity + "|" + normalizeForFingerprint(v.Prompt) + "|" + normalizeForFingerprint(v.Response)
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeForFingerprint lowercases s and collapses all whitespace runs to
+// a single space, so fingerprintVulnerability isn't thrown off by
+// re-wrapped or re-indented Prompt/Response text between runs.
+func normalizeForFingerprint(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// vulnerabilityDelta partitions one run's vulnerabilities against a
+// baseline run's, by fingerprintVulnerability.
+type vulnerabilityDelta struct {
+	New        []Vulnerability // fingerprint not seen in baseline
+	Fixed      []Vulnerability // fingerprint was in baseline, not in current
+	Persisting []Vulnerability // fingerprint seen in both
+}
+
+// diffVulnerabilities computes current's vulnerabilityDelta against baseline.
+func diffVulnerabilities(current, baseline []Vulnerability) vulnerabilityDelta {
+	inBaseline := make(map[string]bool, len(baseline))
+	for _, v := range baseline {
+		inBaseline[fingerprintVulnerability(v)] = true
+	}
+
+	var delta vulnerabilityDelta
+	inCurrent := make(map[string]bool, len(current))
+	for _, v := range current {
+		fp := fingerprintVulnerability(v)
+		inCurrent[fp] = true
+		if inBaseline[fp] {
+			delta.Persisting = append(delta.Persisting, v)
+		} else {
+			delta.New = append(delta.New, v)
+		}
+	}
+
+	for _, v := range baseline {
+		if !inCurrent[fingerprintVulnerability(v)] {
+			delta.Fixed = append(delta.Fixed, v)
+		}
+	}
+
+	return delta
+}
+
+// failOnThresholdMet reports whether delta.New contains a vulnerability
+// severe enough to trip failOn ("any-new", "new-medium", or "new-high").
+// An empty failOn (gating disabled) never trips.
+func failOnThresholdMet(delta vulnerabilityDelta, failOn string) bool {
+	switch failOn {
+	case "any-new":
+		return len(delta.New) > 0
+	case "new-medium":
+		for _, v := range delta.New {
+			if v.Severity == "medium" || v.Severity == "high" || v.Severity == "critical" {
+				return true
+			}
+		}
+		return false
+	case "new-high":
+		for _, v := range delta.New {
+			if v.Severity == "high" || v.Severity == "critical" {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
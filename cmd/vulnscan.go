@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// VulnScanReport is a taxonomy-only pass over an evaluation run's
+// vulnerabilities: each finding's free-text Type normalized to a canonical
+// OWASP LLM Top-10 / MITRE ATLAS code with a CWE reference, and the
+// per-category rollup suggestionsCmd also embeds in SuggestionsReport. It
+// has no AI-generated suggestions, so it runs without an AI client and
+// without the agent's prompt_config.yaml, for callers (CI gates, SARIF
+// exporters, dashboards) that just want consistent category counts.
+type VulnScanReport struct {
+	GeneratedAt                string            `json:"generated_at"`
+	EvaluationFile             string            `json:"evaluation_file"`
+	TotalVulnerabilities       int               `json:"total_vulnerabilities"`
+	VulnerabilitySummary       map[string]int    `json:"vulnerability_summary"`
+	VulnerabilityByLLMCategory map[string]int    `json:"vulnerability_by_llm_category"`
+	CWEReferences              map[string]string `json:"cwe_references"`
+	Vulnerabilities            []Vulnerability   `json:"vulnerabilities"`
+}
+
+// go run . vulnscan
+var vulnscanCmd = &cobra.Command{
+	Use:   "vulnscan",
+	Short: "Map the most recent evaluation run's vulnerabilities to a standard AI-security taxonomy",
+	Long: `Normalizes the most recent evaluation results' free-text vulnerability types against
+the vulncatalog taxonomy (OWASP LLM Top-10 / MITRE ATLAS) and reports per-category counts with
+CWE references, without generating AI prompt suggestions. Use this for a quick CI gate or
+dashboard feed; use "suggestions" when you also want AI-generated prompt fixes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		evaluationFile, err := findMostRecentEvaluationFile("results")
+		if err != nil {
+			log.Fatalln("Failed to find evaluation results file:", err)
+		}
+		log.Printf("Using evaluation results from: %s", evaluationFile)
+
+		evaluationResults, err := loadEvaluationResults(evaluationFile)
+		if err != nil {
+			log.Fatalln("Failed to load evaluation results:", err)
+		}
+
+		catalog, err := loadVulnCatalog()
+		if err != nil {
+			log.Fatalln("Failed to load vuln taxonomy:", err)
+		}
+		normalizeVulnerabilities(evaluationResults, catalog)
+
+		report := createVulnScanReport(evaluationFile, evaluationResults)
+
+		timestamp := time.Now().Format("20060102_150405")
+		filename := fmt.Sprintf("results/vulnscan_%s.json", timestamp)
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalln("Failed to marshal vulnscan report:", err)
+		}
+		if err := os.WriteFile(filename, reportJSON, 0644); err != nil {
+			log.Fatalln("Failed to write vulnscan file:", err)
+		}
+
+		log.Printf("Vulnscan report saved to: %s", filename)
+		log.Printf("Normalized %d vulnerabilities across %d LLM taxonomy categories", len(evaluationResults.Vulnerabilities), len(report.VulnerabilityByLLMCategory))
+	},
+}
+
+// createVulnScanReport builds a VulnScanReport from results, which must
+// already have been passed through normalizeVulnerabilities.
+func createVulnScanReport(evaluationFile string, results *EvaluationResults) *VulnScanReport {
+	vulnSummary := make(map[string]int)
+	byLLMCategory := make(map[string]int)
+	cweReferences := make(map[string]string)
+	for _, vuln := range results.Vulnerabilities {
+		vulnSummary[vuln.Type]++
+		if vuln.NormalizedType != "" {
+			byLLMCategory[vuln.NormalizedType]++
+			if vuln.CWE != "" {
+				cweReferences[vuln.NormalizedType] = vuln.CWE
+			}
+		}
+	}
+
+	return &VulnScanReport{
+		GeneratedAt:                time.Now().Format(time.RFC3339),
+		EvaluationFile:             evaluationFile,
+		TotalVulnerabilities:       len(results.Vulnerabilities),
+		VulnerabilitySummary:       vulnSummary,
+		VulnerabilityByLLMCategory: byLLMCategory,
+		CWEReferences:              cweReferences,
+		Vulnerabilities:            results.Vulnerabilities,
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(vulnscanCmd)
+}
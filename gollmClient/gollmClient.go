@@ -4,8 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
+
+	"datasnack/cloneAttack"
+	"datasnack/pricing"
 
 	"github.com/teilomillet/gollm"
 )
@@ -13,17 +18,25 @@ import (
 // GollmClient implements the AIClient interface using the gollm library
 // This provides unified access to multiple LLM providers (OpenAI, Anthropic, Groq, Ollama, etc.)
 type GollmClient struct {
-	llm     gollm.LLM
-	context context.Context
+	llm       gollm.LLM
+	context   context.Context
+	templates Templates
+	pricing   pricing.Table
 }
 
 // Config holds configuration for the gollm client
 type Config struct {
-	Provider  string `json:"provider"`  // openai, anthropic, groq, ollama, etc.
-	Model     string `json:"model"`     // Model name (e.g., gpt-4o, claude-3-5-sonnet)
-	APIKey    string `json:"apiKey"`    // API key for the provider
-	BaseURL   string `json:"baseURL"`   // Optional: custom base URL (for Ollama, etc.)
-	MaxTokens int    `json:"maxTokens"` // Optional: max tokens per request
+	Provider  string    `json:"provider"`            // openai, anthropic, groq, ollama, etc.
+	Model     string    `json:"model"`               // Model name (e.g., gpt-4o, claude-3-5-sonnet)
+	APIKey    string    `json:"apiKey"`              // API key for the provider
+	BaseURL   string    `json:"baseURL"`             // Optional: custom base URL (for Ollama, etc.)
+	MaxTokens int       `json:"maxTokens"`           // Optional: max tokens per request
+	Templates Templates `json:"templates,omitempty"` // Optional: per-model prompt templates
+
+	// Pricing rates GenerateAIMetered/GenerateAISchemaMetered use to
+	// estimate USDCost, keyed by provider/model. Defaults to
+	// pricing.Default() (models.yaml) when nil.
+	Pricing pricing.Table `json:"-"`
 }
 
 // New creates a new GollmClient instance
@@ -49,9 +62,21 @@ func New(config Config) (*GollmClient, error) {
 		llm.SetOption("base_url", config.BaseURL)
 	}
 
+	priceTable := config.Pricing
+	if priceTable == nil {
+		// pricing.Default() only fails if models.yaml itself fails to
+		// parse, which would be a build-time error, not a runtime one; a
+		// client still works without it, just with USDCost always zero.
+		if t, err := pricing.Default(); err == nil {
+			priceTable = t
+		}
+	}
+
 	return &GollmClient{
-		llm:     llm,
-		context: context.Background(),
+		llm:       llm,
+		context:   context.Background(),
+		templates: config.Templates,
+		pricing:   priceTable,
 	}, nil
 }
 
@@ -84,47 +109,13 @@ func NewFromEnv() (*GollmClient, error) {
 // system: the system prompt/instructions
 // pastMsgs: previous conversation messages (format: [{"role": "user", "content": "..."}, {"role": "assistant", "content": "..."}])
 func (g *GollmClient) GenerateAI(request string, system string, pastMsgs []map[string]string) (string, error) {
-	// Build the conversation history
-	var messages []gollm.PromptMessage
-
-	// Add system message if provided
-	if system != "" {
-		messages = append(messages, gollm.PromptMessage{
-			Role:    "system",
-			Content: system,
-		})
-	}
-
-	// Add past messages
-	for _, msg := range pastMsgs {
-		role, hasRole := msg["role"]
-		content, hasContent := msg["content"]
-
-		if !hasRole || !hasContent {
-			continue // Skip malformed messages
-		}
-
-		// Normalize role names
-		switch strings.ToLower(role) {
-		case "user", "human":
-			messages = append(messages, gollm.PromptMessage{
-				Role:    "user",
-				Content: content,
-			})
-		case "assistant", "ai", "bot":
-			messages = append(messages, gollm.PromptMessage{
-				Role:    "assistant",
-				Content: content,
-			})
-		case "system":
-			messages = append(messages, gollm.PromptMessage{
-				Role:    "system",
-				Content: content,
-			})
-		}
+	system, request, err := g.applyTemplates(system, request)
+	if err != nil {
+		return "", err
 	}
 
-	// Add the current request
+	// Build the conversation history, then add the current request
+	messages := buildMessages(system, pastMsgs)
 	messages = append(messages, gollm.PromptMessage{
 		Role:    "user",
 		Content: request,
@@ -148,10 +139,171 @@ func (g *GollmClient) GenerateAI(request string, system string, pastMsgs []map[s
 // pastMsgs: previous conversation messages
 // schema: JSON schema string for structured output validation
 func (g *GollmClient) GenerateAISchema(request string, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	system, request, err := g.applyTemplates(system, request)
+	if err != nil {
+		return "", err
+	}
+
 	// Build the conversation history (same as GenerateAI)
+	messages := buildMessages(system, pastMsgs)
+
+	// Add the current request with schema instruction
+	schemaInstruction := fmt.Sprintf("%s\n\nPlease respond with valid JSON that matches this schema:\n%s", request, schema)
+	if g.templates.SchemaWrap != "" {
+		rendered, err := render("schemaWrap", g.templates.SchemaWrap, templateData{Request: request, Schema: schema})
+		if err != nil {
+			return "", err
+		}
+		schemaInstruction = rendered
+	}
+	messages = append(messages, gollm.PromptMessage{
+		Role:    "user",
+		Content: schemaInstruction,
+	})
+
+	// Create prompt with conversation history
+	prompt := gollm.NewPrompt("", gollm.WithMessages(messages))
+
+	// Parse schema for structured output
+	var schemaInterface interface{}
+	if err := json.Unmarshal([]byte(schema), &schemaInterface); err != nil {
+		return "", fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	// Generate response with JSON schema validation
+	response, err := g.llm.GenerateWithSchema(g.context, prompt, schemaInterface)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate AI response with schema validation: %w", err)
+	}
+
+	// Validate that the response is valid JSON
+	var jsonResponse interface{}
+	if err := json.Unmarshal([]byte(response), &jsonResponse); err != nil {
+		return "", fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	return response, nil
+}
+
+// GenerateAIMetered is like GenerateAI but also reports estimated token
+// counts, latency, and USD cost, for callers that implement
+// cloneAttack.MeteredAIClient support.
+func (g *GollmClient) GenerateAIMetered(request, system string, pastMsgs []map[string]string) (cloneAttack.GenerationResult, error) {
+	start := time.Now()
+	text, err := g.GenerateAI(request, system, pastMsgs)
+	return g.meter(request, system, pastMsgs, text, start), err
+}
+
+// GenerateAISchemaMetered is like GenerateAISchema but also reports
+// estimated token counts, latency, and USD cost, for callers that implement
+// cloneAttack.MeteredAIClient support.
+func (g *GollmClient) GenerateAISchemaMetered(request, system string, pastMsgs []map[string]string, schema string) (cloneAttack.GenerationResult, error) {
+	start := time.Now()
+	text, err := g.GenerateAISchema(request, system, pastMsgs, schema)
+	return g.meter(request, system, pastMsgs, text, start), err
+}
+
+// meter builds the GenerationResult for a just-completed call. Token counts
+// are estimated at roughly 4 characters per token, since gollm's Generate
+// doesn't surface a provider's actual usage; USDCost comes from g.pricing
+// and is zero if this provider/model isn't in the table.
+func (g *GollmClient) meter(request, system string, pastMsgs []map[string]string, text string, start time.Time) cloneAttack.GenerationResult {
+	promptTokens := estimateTokens(system) + estimateTokens(request)
+	for _, msg := range pastMsgs {
+		promptTokens += estimateTokens(msg["content"])
+	}
+	completionTokens := estimateTokens(text)
+
+	provider := g.llm.GetProvider()
+	model := g.llm.GetModel()
+
+	var cost float64
+	if price, ok := g.pricing.Lookup(provider, model); ok {
+		cost = price.Cost(promptTokens, completionTokens)
+	}
+
+	return cloneAttack.GenerationResult{
+		Text:             text,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		LatencyMs:        time.Since(start).Milliseconds(),
+		Provider:         provider,
+		Model:            model,
+		USDCost:          cost,
+	}
+}
+
+// estimateTokens approximates a token count from s's length at ~4 characters
+// per token, the same rule of thumb OpenAI documents for English text.
+func estimateTokens(s string) int {
+	if s == "" {
+		return 0
+	}
+	return (len(s) + 3) / 4
+}
+
+// GenerateAIStream implements cloneAttack.StreamingAIClient, streaming
+// tokens as gollm's provider emits them instead of blocking until the full
+// response is ready. Closing ctx (or cancelling it) stops the stream early
+// and closes the returned channel, for aborting a hung generation.
+func (g *GollmClient) GenerateAIStream(ctx context.Context, request string, system string, pastMsgs []map[string]string) (<-chan cloneAttack.Token, error) {
+	system, request, err := g.applyTemplates(system, request)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := buildMessages(system, pastMsgs)
+	messages = append(messages, gollm.PromptMessage{
+		Role:    "user",
+		Content: request,
+	})
+	prompt := gollm.NewPrompt("", gollm.WithMessages(messages))
+
+	tokenStream, err := g.llm.Stream(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start streaming AI response: %w", err)
+	}
+
+	tokens := make(chan cloneAttack.Token)
+	go func() {
+		defer close(tokens)
+		defer tokenStream.Close()
+
+		for {
+			tok, err := tokenStream.Next(ctx)
+			if err != nil {
+				if err != io.EOF {
+					sendToken(ctx, tokens, cloneAttack.Token{Err: err})
+				}
+				sendToken(ctx, tokens, cloneAttack.Token{Done: true})
+				return
+			}
+			if !sendToken(ctx, tokens, cloneAttack.Token{Text: tok.Text}) {
+				return
+			}
+		}
+	}()
+
+	return tokens, nil
+}
+
+// sendToken delivers tok on tokens, or gives up if ctx is cancelled first.
+// It reports whether tok was actually sent.
+func sendToken(ctx context.Context, tokens chan<- cloneAttack.Token, tok cloneAttack.Token) bool {
+	select {
+	case tokens <- tok:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// buildMessages converts system and pastMsgs into gollm.PromptMessages,
+// shared by GenerateAI, GenerateAISchema, and GenerateAIStream; each then
+// appends its own final user-role message.
+func buildMessages(system string, pastMsgs []map[string]string) []gollm.PromptMessage {
 	var messages []gollm.PromptMessage
 
-	// Add system message if provided
 	if system != "" {
 		messages = append(messages, gollm.PromptMessage{
 			Role:    "system",
@@ -159,7 +311,6 @@ func (g *GollmClient) GenerateAISchema(request string, system string, pastMsgs [
 		})
 	}
 
-	// Add past messages
 	for _, msg := range pastMsgs {
 		role, hasRole := msg["role"]
 		content, hasContent := msg["content"]
@@ -188,35 +339,28 @@ func (g *GollmClient) GenerateAISchema(request string, system string, pastMsgs [
 		}
 	}
 
-	// Add the current request with schema instruction
-	schemaInstruction := fmt.Sprintf("%s\n\nPlease respond with valid JSON that matches this schema:\n%s", request, schema)
-	messages = append(messages, gollm.PromptMessage{
-		Role:    "user",
-		Content: schemaInstruction,
-	})
-
-	// Create prompt with conversation history
-	prompt := gollm.NewPrompt("", gollm.WithMessages(messages))
-
-	// Parse schema for structured output
-	var schemaInterface interface{}
-	if err := json.Unmarshal([]byte(schema), &schemaInterface); err != nil {
-		return "", fmt.Errorf("invalid JSON schema: %w", err)
-	}
+	return messages
+}
 
-	// Generate response with JSON schema validation
-	response, err := g.llm.GenerateWithSchema(g.context, prompt, schemaInterface)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate AI response with schema validation: %w", err)
+// applyTemplates renders g.templates.System/User over the caller's system
+// prompt and request, returning them unchanged where the corresponding
+// template is blank.
+func (g *GollmClient) applyTemplates(system, request string) (string, string, error) {
+	if g.templates.System != "" {
+		rendered, err := render("system", g.templates.System, templateData{System: system})
+		if err != nil {
+			return "", "", err
+		}
+		system = rendered
 	}
-
-	// Validate that the response is valid JSON
-	var jsonResponse interface{}
-	if err := json.Unmarshal([]byte(response), &jsonResponse); err != nil {
-		return "", fmt.Errorf("response is not valid JSON: %w", err)
+	if g.templates.User != "" {
+		rendered, err := render("user", g.templates.User, templateData{Request: request})
+		if err != nil {
+			return "", "", err
+		}
+		request = rendered
 	}
-
-	return response, nil
+	return system, request, nil
 }
 
 // GetProvider returns the current provider name
@@ -244,6 +388,12 @@ func (g *GollmClient) SetTopP(topP float64) {
 	g.llm.SetOption("top_p", topP)
 }
 
+// SetPricing overrides the pricing table GenerateAIMetered/
+// GenerateAISchemaMetered use to estimate USDCost.
+func (g *GollmClient) SetPricing(table pricing.Table) {
+	g.pricing = table
+}
+
 // Close cleans up resources (if needed)
 func (g *GollmClient) Close() error {
 	// gollm doesn't require explicit cleanup, but this method is here for interface consistency
@@ -287,3 +437,16 @@ func NewOllamaClient(model, baseURL string) (*GollmClient, error) {
 		MaxTokens: 4000,
 	})
 }
+
+// NewOpenAICompatibleClient creates a GollmClient against any OpenAI-compatible
+// base URL (LM Studio, vLLM, LiteLLM, Together, DeepInfra, a custom gateway,
+// ...) by using the openai provider with a swapped base URL.
+func NewOpenAICompatibleClient(apiKey, model, baseURL string) (*GollmClient, error) {
+	return New(Config{
+		Provider:  "openai",
+		Model:     model,
+		APIKey:    apiKey,
+		BaseURL:   baseURL,
+		MaxTokens: 4000,
+	})
+}
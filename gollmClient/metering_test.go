@@ -0,0 +1,18 @@
+package gollmClient
+
+import "testing"
+
+func TestEstimateTokensEmptyStringIsZero(t *testing.T) {
+	if got := estimateTokens(""); got != 0 {
+		t.Errorf("estimateTokens(\"\") = %d, want 0", got)
+	}
+}
+
+func TestEstimateTokensRoughlyFourCharsPerToken(t *testing.T) {
+	if got := estimateTokens("abcd"); got != 1 {
+		t.Errorf("estimateTokens(\"abcd\") = %d, want 1", got)
+	}
+	if got := estimateTokens("abcde"); got != 2 {
+		t.Errorf("estimateTokens(\"abcde\") = %d, want 2", got)
+	}
+}
@@ -0,0 +1,52 @@
+package gollmClient
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Templates lets a model's prompt shape be customized without
+// provider-specific Go code — e.g. a Llama-instruct model that expects
+// "[INST] ... [/INST]" wrapping, or a provider whose schema-constrained
+// calls need their own instruction phrasing. Each field is an optional Go
+// template (text/template); a blank field leaves GenerateAI/
+// GenerateAISchema's default message shape unchanged for that part.
+type Templates struct {
+	// System rewrites the system prompt. Executed with templateData's
+	// System field set to the caller's original system prompt.
+	System string `yaml:"system,omitempty" json:"system,omitempty"`
+	// User rewrites the request/user message. Executed with templateData's
+	// Request field set to the caller's original request.
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
+	// SchemaWrap rewrites GenerateAISchema's combined request+schema
+	// instruction. Executed with templateData's Request (already passed
+	// through User, if set) and Schema fields.
+	SchemaWrap string `yaml:"schemaWrap,omitempty" json:"schemaWrap,omitempty"`
+}
+
+// templateData is what a Templates field's Go template is executed against.
+type templateData struct {
+	Request string
+	System  string
+	Schema  string
+}
+
+// render parses and executes tmpl against data, or returns "" unchanged if
+// tmpl is blank. name identifies the template in parse/execute errors.
+func render(name, tmpl string, data templateData) (string, error) {
+	if tmpl == "" {
+		return "", nil
+	}
+
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("gollmClient: failed to parse %s template: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("gollmClient: failed to execute %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,44 @@
+package gollmClient
+
+import "testing"
+
+func TestRenderWrapsContentPerTemplate(t *testing.T) {
+	out, err := render("user", "[INST] {{.Request}} [/INST]", templateData{Request: "hello"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if want := "[INST] hello [/INST]"; out != want {
+		t.Errorf("render = %q, want %q", out, want)
+	}
+}
+
+func TestRenderBlankTemplateReturnsEmpty(t *testing.T) {
+	out, err := render("user", "", templateData{Request: "hello"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if out != "" {
+		t.Errorf("render = %q, want empty", out)
+	}
+}
+
+func TestRenderInvalidTemplateErrors(t *testing.T) {
+	if _, err := render("user", "{{.Missing", templateData{}); err == nil {
+		t.Fatal("render err = nil, want a parse error")
+	}
+}
+
+func TestApplyTemplatesLeavesUnsetFieldsUnchanged(t *testing.T) {
+	client := &GollmClient{templates: Templates{User: "[INST] {{.Request}} [/INST]"}}
+
+	system, request, err := client.applyTemplates("be helpful", "hello")
+	if err != nil {
+		t.Fatalf("applyTemplates: %v", err)
+	}
+	if system != "be helpful" {
+		t.Errorf("system = %q, want unchanged %q", system, "be helpful")
+	}
+	if want := "[INST] hello [/INST]"; request != want {
+		t.Errorf("request = %q, want %q", request, want)
+	}
+}
@@ -0,0 +1,82 @@
+// Package modelconfig loads a directory of per-model YAML files (one model
+// per file, under e.g. config/models/) describing how to reach and talk to
+// a provider: its endpoint, token env var, sampling defaults, and any
+// gollmClient.Templates a quirky model needs. cmd/serve.go's
+// initializeAIClientFleet turns a LoadDir result into one cloneAttack.AIClient
+// per model, keyed by logical name, so the evaluator can run the same
+// attack suite across a whole fleet of models in one invocation instead of
+// the single model aiClientConfig.json selects.
+package modelconfig
+
+import (
+	"datasnack/gollmClient"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModelConfig declares one model in a fleet. The YAML file's name (without
+// extension) is its logical name unless Name overrides it.
+type ModelConfig struct {
+	Name        string                `yaml:"name,omitempty"`
+	Provider    string                `yaml:"provider"`
+	Model       string                `yaml:"model"`
+	Endpoint    string                `yaml:"endpoint,omitempty"`
+	TokenEnvKey string                `yaml:"tokenEnvKey,omitempty"`
+	Temperature float64               `yaml:"temperature,omitempty"`
+	TopP        float64               `yaml:"topP,omitempty"`
+	MaxTokens   int                   `yaml:"maxTokens,omitempty"`
+	Templates   gollmClient.Templates `yaml:"templates,omitempty"`
+}
+
+// LoadDir scans dir for *.yaml files, parsing each into a ModelConfig keyed
+// by its logical name. A non-nil error names the offending file, so one bad
+// config in a fleet is reported rather than silently dropped.
+func LoadDir(dir string) (map[string]ModelConfig, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("modelconfig: failed to read %s: %w", dir, err)
+	}
+
+	configs := make(map[string]ModelConfig)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("modelconfig: failed to read %s: %w", path, err)
+		}
+
+		var cfg ModelConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("modelconfig: failed to parse %s: %w", path, err)
+		}
+
+		name := cfg.Name
+		if name == "" {
+			name = strings.TrimSuffix(entry.Name(), ".yaml")
+		}
+		configs[name] = cfg
+	}
+
+	return configs, nil
+}
+
+// Names returns configs' keys, sorted, so callers iterating a fleet (e.g.
+// for logging, or naming one results file per model) get a stable order
+// instead of Go's randomized map iteration.
+func Names(configs map[string]ModelConfig) []string {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,76 @@
+package modelconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDirParsesEachFileKeyedByName(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "llama-local.yaml", `
+provider: gollm
+model: llama-3-70b-instruct
+endpoint: http://localhost:8000/v1
+tokenEnvKey: LOCAL_TOKEN
+temperature: 0.2
+templates:
+  user: "[INST] {{.Request}} [/INST]"
+`)
+	writeFile(t, dir, "gpt4o.yaml", `
+name: gpt-4o-primary
+provider: gollm
+model: gpt-4o
+tokenEnvKey: OPENAI_API_KEY
+`)
+
+	configs, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("LoadDir returned %d configs, want 2", len(configs))
+	}
+
+	llama, ok := configs["llama-local"]
+	if !ok {
+		t.Fatalf("LoadDir missing %q, got %v", "llama-local", Names(configs))
+	}
+	if llama.Model != "llama-3-70b-instruct" || llama.Templates.User == "" {
+		t.Errorf("llama config = %+v, want model/templates populated", llama)
+	}
+
+	if _, ok := configs["gpt-4o-primary"]; !ok {
+		t.Errorf("LoadDir did not key the second file by its Name override, got %v", Names(configs))
+	}
+}
+
+func TestLoadDirRejectsBadYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "broken.yaml", "provider: [this is not valid")
+
+	if _, err := LoadDir(dir); err == nil {
+		t.Fatal("LoadDir err = nil, want a parse error naming the bad file")
+	}
+}
+
+func TestNamesIsSorted(t *testing.T) {
+	configs := map[string]ModelConfig{"zeta": {}, "alpha": {}, "mid": {}}
+	got := Names(configs)
+	want := []string{"alpha", "mid", "zeta"}
+	if len(got) != len(want) {
+		t.Fatalf("Names = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Names = %v, want %v", got, want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+		t.Fatalf("writeFile %s: %v", name, err)
+	}
+}
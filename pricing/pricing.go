@@ -0,0 +1,69 @@
+// Package pricing maps AI provider/model pairs to their $/1M token rates, so
+// converted workflows (via the Metrics Calculator Code node, see
+// cmd/convert/graph.InjectMetricsCalculator) can turn token counts into an
+// estimated cost_usd, which evaluateset sums across a run, and gollmClient
+// can estimate a call's USDCost. The default table is models.yaml, embedded
+// at build time; callers needing rates for a model not listed there (or a
+// different price) can load their own with Load and pass --pricing to
+// convert or evaluate. evaluateset has no --pricing flag: it sums the
+// cost_usd each converted workflow reports.
+package pricing
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed models.yaml
+var defaultModelsYAML []byte
+
+// Price is a model's per-million-token rate, in USD.
+type Price struct {
+	InputPerMillion  float64 `yaml:"input_per_million" json:"input_per_million"`
+	OutputPerMillion float64 `yaml:"output_per_million" json:"output_per_million"`
+}
+
+// Table is a pricing table: provider -> model -> Price.
+type Table map[string]map[string]Price
+
+// Default returns the pricing table embedded from models.yaml.
+func Default() (Table, error) {
+	return parse(defaultModelsYAML)
+}
+
+// Load reads a pricing table from a YAML file shaped like models.yaml, for
+// use with --pricing.
+func Load(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pricing: failed to read %s: %w", path, err)
+	}
+	return parse(data)
+}
+
+func parse(data []byte) (Table, error) {
+	var t Table
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("pricing: failed to parse pricing table: %w", err)
+	}
+	return t, nil
+}
+
+// Lookup returns the Price for provider/model, and whether it was found.
+func (t Table) Lookup(provider, model string) (Price, bool) {
+	byModel, ok := t[provider]
+	if !ok {
+		return Price{}, false
+	}
+	price, ok := byModel[model]
+	return price, ok
+}
+
+// Cost estimates the USD cost of a call given its token counts, per p's
+// rates.
+func (p Price) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.InputPerMillion + float64(completionTokens)*p.OutputPerMillion) / 1_000_000
+}
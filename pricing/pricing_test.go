@@ -0,0 +1,37 @@
+package pricing
+
+import "testing"
+
+func TestDefaultLooksUpOpenAIGpt4oMini(t *testing.T) {
+	table, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	price, ok := table.Lookup("openai", "gpt-4o-mini")
+	if !ok {
+		t.Fatal("Lookup(openai, gpt-4o-mini): not found")
+	}
+	if price.InputPerMillion != 0.15 || price.OutputPerMillion != 0.60 {
+		t.Errorf("Lookup(openai, gpt-4o-mini) = %+v, want {0.15 0.60}", price)
+	}
+}
+
+func TestLookupMissingModelReportsNotFound(t *testing.T) {
+	table, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+	if _, ok := table.Lookup("openai", "not-a-real-model"); ok {
+		t.Error("Lookup(openai, not-a-real-model): expected not found")
+	}
+}
+
+func TestPriceCostComputesWeightedTotal(t *testing.T) {
+	p := Price{InputPerMillion: 1.0, OutputPerMillion: 2.0}
+	got := p.Cost(1_000_000, 500_000)
+	want := 1.0 + 1.0
+	if got != want {
+		t.Errorf("Cost(1_000_000, 500_000) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,290 @@
+// Package providerpool implements cloneAttack.AIClient as a weighted,
+// health-checked pool over several underlying providers, instead of the
+// historical pattern of trying each provider in order at startup and
+// committing to whichever one answered first.
+package providerpool
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"datasnack/cloneAttack"
+)
+
+// Member is one provider in a Pool: an AIClient plus the weight and
+// concurrency limit it should be given relative to its pool-mates.
+type Member struct {
+	Name   string
+	Client cloneAttack.AIClient
+
+	// Weight is this member's relative share of traffic among healthy
+	// members; <= 0 is treated as 1.
+	Weight int
+
+	// MaxConcurrency caps how many calls may be in flight on this member at
+	// once; <= 0 means unbounded.
+	MaxConcurrency int
+}
+
+// Config controls a Pool's background health checking.
+type Config struct {
+	// CheckInterval is how often every member is re-probed with a minimal
+	// GenerateAI call. <= 0 disables periodic checks; members are then only
+	// as healthy as whatever the caller decided before constructing the Pool.
+	CheckInterval time.Duration
+
+	// CheckTimeout bounds a single health check. <= 0 defaults to 10s.
+	CheckTimeout time.Duration
+}
+
+// Stats summarizes one member's observed health for a run, suitable for
+// embedding in a result report (e.g. StressTestResults.PerformanceMetrics)
+// next to the findings it helped produce, for reproducibility.
+type Stats struct {
+	Healthy      bool      `json:"healthy"`
+	Requests     int64     `json:"requests"`
+	Errors       int64     `json:"errors"`
+	AvgLatencyMs float64   `json:"avgLatencyMs"`
+	LastChecked  time.Time `json:"lastChecked"`
+}
+
+// member wraps a Member with the mutable state pick and the health checker
+// update concurrently.
+type member struct {
+	Member
+
+	mu           sync.Mutex
+	healthy      bool
+	requests     int64
+	errors       int64
+	totalLatency time.Duration
+	lastChecked  time.Time
+
+	sem chan struct{} // nil when MaxConcurrency is unbounded
+}
+
+func (m *member) avgLatencyLocked() time.Duration {
+	if m.requests == 0 {
+		return 0
+	}
+	return m.totalLatency / time.Duration(m.requests)
+}
+
+func (m *member) atCapacity() bool {
+	return m.sem != nil && len(m.sem) >= cap(m.sem)
+}
+
+// Pool implements cloneAttack.AIClient, dispatching each GenerateAI/
+// GenerateAISchema call to one member chosen by weighted random selection
+// across the currently healthy ones. A member's effective weight is its
+// configured Weight scaled down by its observed error rate and average
+// latency, so a degrading provider naturally loses traffic share to its
+// pool-mates well before a health check marks it unhealthy outright.
+type Pool struct {
+	members []*member
+	cfg     Config
+
+	randMu sync.Mutex // guards rnd, which is not safe for concurrent use
+	rnd    *rand.Rand
+
+	stop       chan struct{}
+	stopClosed sync.Once
+}
+
+// New creates a Pool over members and, unless cfg.CheckInterval is <= 0,
+// starts a background goroutine that re-probes every member on that
+// interval. Every member starts healthy, so startup isn't gated on a health
+// check if the caller already verified each provider itself (e.g. via
+// testAIClient) before adding it.
+func New(members []Member, cfg Config) *Pool {
+	if cfg.CheckTimeout <= 0 {
+		cfg.CheckTimeout = 10 * time.Second
+	}
+
+	p := &Pool{
+		cfg:  cfg,
+		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		stop: make(chan struct{}),
+	}
+
+	for _, m := range members {
+		weight := m.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		entry := &member{Member: m, healthy: true, lastChecked: time.Now()}
+		entry.Weight = weight
+		if m.MaxConcurrency > 0 {
+			entry.sem = make(chan struct{}, m.MaxConcurrency)
+		}
+		p.members = append(p.members, entry)
+	}
+
+	if cfg.CheckInterval > 0 {
+		go p.healthCheckLoop()
+	}
+
+	return p
+}
+
+// GenerateAI implements cloneAttack.AIClient.
+func (p *Pool) GenerateAI(request, system string, pastMsgs []map[string]string) (string, error) {
+	m, err := p.pick()
+	if err != nil {
+		return "", err
+	}
+	return call(m, func() (string, error) {
+		return m.Client.GenerateAI(request, system, pastMsgs)
+	})
+}
+
+// GenerateAISchema implements cloneAttack.AIClient.
+func (p *Pool) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	m, err := p.pick()
+	if err != nil {
+		return "", err
+	}
+	return call(m, func() (string, error) {
+		return m.Client.GenerateAISchema(request, system, pastMsgs, schema)
+	})
+}
+
+// Stats returns a snapshot of every member's observed health, keyed by
+// Member.Name. It implements cloneAttack.StatsProvider.
+func (p *Pool) Stats() map[string]interface{} {
+	out := make(map[string]interface{}, len(p.members))
+	for _, m := range p.members {
+		m.mu.Lock()
+		out[m.Name] = Stats{
+			Healthy:      m.healthy,
+			Requests:     m.requests,
+			Errors:       m.errors,
+			AvgLatencyMs: float64(m.avgLatencyLocked()) / float64(time.Millisecond),
+			LastChecked:  m.lastChecked,
+		}
+		m.mu.Unlock()
+	}
+	return out
+}
+
+// Close stops the background health checker, if one was started. Safe to
+// call more than once.
+func (p *Pool) Close() {
+	p.stopClosed.Do(func() { close(p.stop) })
+}
+
+// pick weighted-randomly selects one healthy, not-at-capacity member.
+func (p *Pool) pick() (*member, error) {
+	var candidates []*member
+	var weights []float64
+	var total float64
+
+	for _, m := range p.members {
+		m.mu.Lock()
+		healthy := m.healthy
+		requests := m.requests
+		errors := m.errors
+		avgLatency := m.avgLatencyLocked()
+		m.mu.Unlock()
+
+		if !healthy || m.atCapacity() {
+			continue
+		}
+
+		errorRate := 0.0
+		if requests > 0 {
+			errorRate = float64(errors) / float64(requests)
+		}
+		w := float64(m.Weight) / (1 + errorRate*4) / (1 + avgLatency.Seconds())
+		if w <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, m)
+		weights = append(weights, w)
+		total += w
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("providerpool: no healthy providers available")
+	}
+
+	p.randMu.Lock()
+	r := p.rnd.Float64() * total
+	p.randMu.Unlock()
+
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return candidates[i], nil
+		}
+	}
+	return candidates[len(candidates)-1], nil
+}
+
+// call runs fn against m, respecting its concurrency limit and recording the
+// outcome into its stats.
+func call(m *member, fn func() (string, error)) (string, error) {
+	if m.sem != nil {
+		m.sem <- struct{}{}
+		defer func() { <-m.sem }()
+	}
+
+	start := time.Now()
+	resp, err := fn()
+	elapsed := time.Since(start)
+
+	m.mu.Lock()
+	m.requests++
+	if err != nil {
+		m.errors++
+	}
+	m.totalLatency += elapsed
+	m.mu.Unlock()
+
+	return resp, err
+}
+
+func (p *Pool) healthCheckLoop() {
+	ticker := time.NewTicker(p.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.checkAll()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *Pool) checkAll() {
+	for _, m := range p.members {
+		healthy := probe(m.Client, p.cfg.CheckTimeout) == nil
+
+		m.mu.Lock()
+		m.healthy = healthy
+		m.lastChecked = time.Now()
+		m.mu.Unlock()
+	}
+}
+
+// probe runs a minimal GenerateAI call against client, enforcing timeout
+// itself since AIClient takes no context.Context of its own.
+func probe(client cloneAttack.AIClient, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GenerateAI("Hello", "You are a helpful assistant.", nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("health check timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,97 @@
+package providerpool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// stubClient is a minimal cloneAttack.AIClient for exercising Pool's
+// selection and stats without a real provider SDK behind it.
+type stubClient struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (s *stubClient) GenerateAI(request, system string, pastMsgs []map[string]string) (string, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return "ok", s.err
+}
+
+func (s *stubClient) GenerateAISchema(request, system string, pastMsgs []map[string]string, schema string) (string, error) {
+	return s.GenerateAI(request, system, pastMsgs)
+}
+
+func TestPoolDispatchesOnlyToHealthyMember(t *testing.T) {
+	healthy := &stubClient{}
+	unhealthy := &stubClient{err: errors.New("down")}
+
+	p := New([]Member{
+		{Name: "unhealthy", Client: unhealthy},
+		{Name: "healthy", Client: healthy},
+	}, Config{})
+	p.members[0].healthy = false
+
+	for i := 0; i < 10; i++ {
+		if _, err := p.GenerateAI("hi", "sys", nil); err != nil {
+			t.Fatalf("GenerateAI: %v", err)
+		}
+	}
+
+	if unhealthy.calls != 0 {
+		t.Errorf("unhealthy member got %d calls, want 0", unhealthy.calls)
+	}
+	if healthy.calls != 10 {
+		t.Errorf("healthy member got %d calls, want 10", healthy.calls)
+	}
+}
+
+func TestPoolReturnsErrorWhenNoMemberHealthy(t *testing.T) {
+	p := New([]Member{{Name: "only", Client: &stubClient{}}}, Config{})
+	p.members[0].healthy = false
+
+	if _, err := p.GenerateAI("hi", "sys", nil); err == nil {
+		t.Fatal("GenerateAI err = nil, want an error since no member is healthy")
+	}
+}
+
+func TestPoolPrefersHigherWeight(t *testing.T) {
+	light := &stubClient{}
+	heavy := &stubClient{}
+
+	p := New([]Member{
+		{Name: "light", Client: light, Weight: 1},
+		{Name: "heavy", Client: heavy, Weight: 99},
+	}, Config{})
+
+	for i := 0; i < 200; i++ {
+		if _, err := p.GenerateAI("hi", "sys", nil); err != nil {
+			t.Fatalf("GenerateAI: %v", err)
+		}
+	}
+
+	if heavy.calls <= light.calls {
+		t.Errorf("heavy member got %d calls, light got %d; want heavy to dominate", heavy.calls, light.calls)
+	}
+}
+
+func TestStatsReportsPerMemberCounts(t *testing.T) {
+	failing := &stubClient{err: errors.New("boom")}
+
+	p := New([]Member{{Name: "flaky", Client: failing}}, Config{})
+	for i := 0; i < 3; i++ {
+		p.GenerateAI("hi", "sys", nil)
+	}
+
+	stats := p.Stats()
+	flaky, ok := stats["flaky"].(Stats)
+	if !ok {
+		t.Fatalf("Stats()[%q] = %#v, want a Stats value", "flaky", stats["flaky"])
+	}
+	if flaky.Requests != 3 || flaky.Errors != 3 {
+		t.Errorf("flaky stats = %+v, want Requests=3 Errors=3", flaky)
+	}
+}
@@ -0,0 +1,321 @@
+// Package schema defines the JSON Schema contracts enforced at the
+// project's AI/workflow boundaries: the evaluation response a converted
+// n8n workflow's webhook must return, and the n8n workflow JSON itself that
+// convert's --use-ai path asks a model to produce. Validate implements a
+// small dependency-free subset of JSON Schema (type/required/properties) —
+// enough to catch the drift these two contracts actually hit, without
+// pulling in a full JSON Schema library.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Schema is a JSON Schema document (or subdocument), expressed the same way
+// json.Unmarshal would decode one: "type", "required", "properties", and so
+// on as map entries. It marshals to real JSON Schema, so it can be passed
+// straight to an AIClient.GenerateAISchema call.
+type Schema map[string]interface{}
+
+// JSON renders s as a JSON Schema document.
+func (s Schema) JSON() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("schema: failed to marshal: %w", err)
+	}
+	return string(data), nil
+}
+
+// EvaluationResponseSchema is the contract a converted n8n workflow's
+// webhook response must satisfy, matching the standardized payload
+// documented in convertWorkflowToWebhookAI's prompt: success, query,
+// response, metrics, provider_info, timing, error, and workflow_metrics.
+// token_usage and cost_usd are optional: not every provider response shape
+// the Metrics Calculator Code node knows how to parse exposes token counts,
+// and cost_usd additionally depends on the model being in the pricing table.
+// streaming is likewise optional, populated only by workflows converted with
+// convert --streaming.
+func EvaluationResponseSchema() Schema {
+	return Schema{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "EvaluationResponse",
+		"type":    "object",
+		"required": []string{
+			"success", "query", "response", "metrics", "provider_info", "timing", "error", "workflow_metrics",
+		},
+		"properties": Schema{
+			"success":  Schema{"type": "boolean"},
+			"query":    Schema{"type": "string"},
+			"response": Schema{"type": "string"},
+			"metrics": Schema{
+				"type": "object",
+				"required": []string{
+					"response_time", "total_time", "response_length", "word_count", "character_count", "has_content", "timestamp",
+				},
+				"properties": Schema{
+					"response_time":   Schema{"type": "number"},
+					"total_time":      Schema{"type": "number"},
+					"response_length": Schema{"type": "integer"},
+					"word_count":      Schema{"type": "integer"},
+					"character_count": Schema{"type": "integer"},
+					"has_content":     Schema{"type": "boolean"},
+					"timestamp":       Schema{"type": "string"},
+				},
+			},
+			"provider_info": Schema{
+				"type":     "object",
+				"required": []string{"provider", "model", "temperature", "reasoning_effort"},
+				"properties": Schema{
+					"provider":         Schema{"type": "string"},
+					"model":            Schema{"type": "string"},
+					"temperature":      Schema{"type": "string"},
+					"reasoning_effort": Schema{"type": "string"},
+				},
+			},
+			"timing": Schema{
+				"type":     "object",
+				"required": []string{"response_time", "total_time"},
+				"properties": Schema{
+					"response_time": Schema{"type": "number"},
+					"total_time":    Schema{"type": "number"},
+				},
+			},
+			"error": Schema{"type": []string{"string", "null"}},
+			"token_usage": Schema{
+				"type": "object",
+				"properties": Schema{
+					"prompt_tokens":     Schema{"type": "integer"},
+					"completion_tokens": Schema{"type": "integer"},
+					"total_tokens":      Schema{"type": "integer"},
+				},
+			},
+			"cost_usd": Schema{"type": "number"},
+			"streaming": Schema{
+				"type": "object",
+				"properties": Schema{
+					"ttft_ms":            Schema{"type": "number"},
+					"tokens_per_second":  Schema{"type": "number"},
+					"chunk_count":        Schema{"type": "integer"},
+					"p50_inter_token_ms": Schema{"type": "number"},
+					"p95_inter_token_ms": Schema{"type": "number"},
+					"total_bytes":        Schema{"type": "integer"},
+				},
+			},
+			"workflow_metrics": Schema{
+				"type":     "object",
+				"required": []string{"workflow_name", "nodes_executed", "custom_metrics"},
+				"properties": Schema{
+					"workflow_name":  Schema{"type": "string"},
+					"nodes_executed": Schema{"type": "integer"},
+					"custom_metrics": Schema{"type": "object"},
+				},
+			},
+		},
+	}
+}
+
+// WorkflowSchema is a meta-schema describing the shape of a valid n8n
+// workflow export: a "nodes" array of {id, name, type, parameters, ...}
+// objects and a "connections" object. convert's --use-ai path passes this
+// to AIClient.GenerateAISchema so the model is constrained to produce a
+// parseable workflow up front, instead of convert trying to parse whatever
+// text comes back and falling back to the deterministic converter on failure.
+func WorkflowSchema() Schema {
+	return Schema{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "N8nWorkflow",
+		"type":    "object",
+		"required": []string{
+			"nodes", "connections",
+		},
+		"properties": Schema{
+			"nodes": Schema{
+				"type": "array",
+				"items": Schema{
+					"type":     "object",
+					"required": []string{"name", "type"},
+					"properties": Schema{
+						"id":   Schema{"type": "string"},
+						"name": Schema{"type": "string"},
+						"type": Schema{"type": "string"},
+					},
+				},
+			},
+			"connections": Schema{"type": "object"},
+		},
+	}
+}
+
+// Drift describes a single place data failed to conform to a Schema, as a
+// dotted JSON path into the document (e.g. "metrics.word_count").
+type Drift struct {
+	Path string
+	Want string
+	Got  string
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s: want %s, got %s", d.Path, d.Want, d.Got)
+}
+
+// ValidateJSON unmarshals raw and validates it against s, returning every
+// Drift found. A non-nil error means raw wasn't even valid JSON.
+func ValidateJSON(s Schema, raw []byte) ([]Drift, error) {
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("schema: response is not valid JSON: %w", err)
+	}
+	return Validate(s, data), nil
+}
+
+// Validate checks data (as produced by json.Unmarshal into interface{})
+// against s, returning every Drift found. A nil result means data conforms
+// to s.
+func Validate(s Schema, data interface{}) []Drift {
+	return validateAt(s, data, "")
+}
+
+func validateAt(s Schema, data interface{}, path string) []Drift {
+	var drifts []Drift
+
+	if !matchesType(s["type"], data) {
+		return append(drifts, Drift{Path: root(path), Want: fmt.Sprintf("type %v", s["type"]), Got: goType(data)})
+	}
+
+	obj, isObject := data.(map[string]interface{})
+
+	if required, ok := s["required"].([]string); ok {
+		for _, field := range required {
+			if !isObject {
+				drifts = append(drifts, Drift{Path: joinPath(path, field), Want: "present", Got: "parent is not an object"})
+				continue
+			}
+			if _, present := obj[field]; !present {
+				drifts = append(drifts, Drift{Path: joinPath(path, field), Want: "present", Got: "missing"})
+			}
+		}
+	}
+
+	if props, ok := s["properties"].(Schema); ok && isObject {
+		for field, fieldSchema := range props {
+			fs, ok := fieldSchema.(Schema)
+			if !ok {
+				continue
+			}
+			v, present := obj[field]
+			if !present {
+				continue // already reported above if required
+			}
+			drifts = append(drifts, validateAt(fs, v, joinPath(path, field))...)
+		}
+	}
+
+	if items, ok := s["items"].(Schema); ok {
+		if arr, ok := data.([]interface{}); ok {
+			for i, v := range arr {
+				drifts = append(drifts, validateAt(items, v, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return drifts
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func root(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+// matchesType reports whether data's JSON-decoded Go type satisfies want,
+// which is either a single type name or (for nullable fields) a slice of
+// type names.
+func matchesType(want interface{}, data interface{}) bool {
+	switch t := want.(type) {
+	case string:
+		return matchesOneType(t, data)
+	case []string:
+		for _, tt := range t {
+			if matchesOneType(tt, data) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true // unconstrained
+	}
+}
+
+func matchesOneType(t string, data interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := data.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := data.([]interface{})
+		return ok
+	case "string":
+		_, ok := data.(string)
+		return ok
+	case "boolean":
+		_, ok := data.(bool)
+		return ok
+	case "number":
+		_, ok := data.(float64)
+		return ok
+	case "integer":
+		f, ok := data.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return data == nil
+	default:
+		return true
+	}
+}
+
+// RequiredPaths flattens every required field in s, recursively through
+// "properties", into dotted paths (e.g. "metrics.word_count"). Callers that
+// need to check a schema's required fields without a Go value in hand
+// (e.g. the JS embedded in a generated n8n Code node) can embed this list
+// instead of re-deriving it from the schema by hand.
+func RequiredPaths(s Schema) []string {
+	paths := requiredPathsAt(s, "")
+	sort.Strings(paths)
+	return paths
+}
+
+func requiredPathsAt(s Schema, prefix string) []string {
+	var paths []string
+	required, _ := s["required"].([]string)
+	for _, field := range required {
+		paths = append(paths, joinPath(prefix, field))
+	}
+
+	props, _ := s["properties"].(Schema)
+	for field, fieldSchema := range props {
+		fs, ok := fieldSchema.(Schema)
+		if !ok {
+			continue
+		}
+		paths = append(paths, requiredPathsAt(fs, joinPath(prefix, field))...)
+	}
+	return paths
+}
+
+func goType(data interface{}) string {
+	if data == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T", data)
+}
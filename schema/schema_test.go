@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateJSONAcceptsAConformingEvaluationResponse(t *testing.T) {
+	raw := []byte(`{
+		"success": true,
+		"query": "hello",
+		"response": "world",
+		"metrics": {
+			"response_time": 0.5,
+			"total_time": 0.5,
+			"response_length": 5,
+			"word_count": 1,
+			"character_count": 5,
+			"has_content": true,
+			"timestamp": "2026-07-26T00:00:00Z"
+		},
+		"provider_info": {
+			"provider": "openai",
+			"model": "gpt-4o-mini",
+			"temperature": "0",
+			"reasoning_effort": "medium"
+		},
+		"timing": {
+			"response_time": 0.5,
+			"total_time": 0.5
+		},
+		"error": null,
+		"workflow_metrics": {
+			"workflow_name": "demo",
+			"nodes_executed": 3,
+			"custom_metrics": {}
+		}
+	}`)
+
+	drifts, err := ValidateJSON(EvaluationResponseSchema(), raw)
+	if err != nil {
+		t.Fatalf("ValidateJSON: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("got drifts %v, want none", drifts)
+	}
+}
+
+func TestValidateJSONReportsMissingAndWrongTypeFields(t *testing.T) {
+	raw := []byte(`{
+		"success": "yes",
+		"response": "world",
+		"metrics": {
+			"response_time": 0.5,
+			"total_time": 0.5,
+			"response_length": "5",
+			"has_content": true,
+			"timestamp": "2026-07-26T00:00:00Z"
+		},
+		"error": null
+	}`)
+
+	drifts, err := ValidateJSON(EvaluationResponseSchema(), raw)
+	if err != nil {
+		t.Fatalf("ValidateJSON: %v", err)
+	}
+
+	byPath := make(map[string]Drift, len(drifts))
+	for _, d := range drifts {
+		byPath[d.Path] = d
+	}
+
+	if _, ok := byPath["query"]; !ok {
+		t.Error("expected a drift for the missing top-level \"query\" field")
+	}
+	if _, ok := byPath["provider_info"]; !ok {
+		t.Error("expected a drift for the missing top-level \"provider_info\" field")
+	}
+	if _, ok := byPath["metrics.word_count"]; !ok {
+		t.Error("expected a drift for the missing \"metrics.word_count\" field")
+	}
+	if d, ok := byPath["success"]; !ok || !strings.Contains(d.Want, "boolean") {
+		t.Errorf("expected a type drift for \"success\", got %v", byPath["success"])
+	}
+	if d, ok := byPath["metrics.response_length"]; !ok || !strings.Contains(d.Want, "integer") {
+		t.Errorf("expected a type drift for \"metrics.response_length\", got %v", byPath["metrics.response_length"])
+	}
+}
+
+func TestValidateJSONRejectsNonObjectTopLevel(t *testing.T) {
+	drifts, err := ValidateJSON(EvaluationResponseSchema(), []byte(`"not an object"`))
+	if err != nil {
+		t.Fatalf("ValidateJSON: %v", err)
+	}
+	if len(drifts) != 1 || drifts[0].Path != "$" {
+		t.Errorf("got drifts %v, want a single root-level type drift", drifts)
+	}
+}
+
+func TestRequiredPathsFlattensNestedRequiredFields(t *testing.T) {
+	paths := RequiredPaths(EvaluationResponseSchema())
+
+	want := []string{"metrics.word_count", "provider_info.model", "success", "timing.total_time", "workflow_metrics.nodes_executed"}
+	for _, w := range want {
+		found := false
+		for _, p := range paths {
+			if p == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("RequiredPaths() = %v, missing %q", paths, w)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package stream consumes a text/event-stream (SSE) HTTP response and
+// reconstructs both the full response text and the per-chunk timing metrics
+// (time to first token, inter-token latency, tokens/sec) that convert
+// --streaming's injected Code node records server-side for an n8n workflow.
+// It offers the same metrics to Go code consuming a streaming response
+// directly. No command in this repository calls it: evaluateset's tasks and
+// the evaluate commands do not read SSE bodies through this package and do
+// not add its Stats to their metrics.
+package stream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stats summarizes a single SSE stream's timing and size, matching the
+// optional "streaming" object in schema.EvaluationResponseSchema().
+type Stats struct {
+	TTFTMs          float64 `json:"ttft_ms"`
+	TokensPerSecond float64 `json:"tokens_per_second"`
+	ChunkCount      int     `json:"chunk_count"`
+	P50InterTokenMs float64 `json:"p50_inter_token_ms"`
+	P95InterTokenMs float64 `json:"p95_inter_token_ms"`
+	TotalBytes      int     `json:"total_bytes"`
+}
+
+// Chunk is one "data:" line read off an SSE stream, timestamped at arrival.
+type Chunk struct {
+	Data       string
+	ReceivedAt time.Time
+}
+
+// ReadEvents reads an SSE body line by line, returning one Chunk per "data:"
+// line timestamped as it's read, stopping at a "[DONE]" sentinel or EOF. It
+// mirrors cloneAttack.streamSSEBody, but collects the whole stream instead
+// of relaying it incrementally over a channel, since callers here want it
+// reconstructed rather than forwarded live.
+func ReadEvents(r io.Reader) ([]Chunk, error) {
+	var chunks []Chunk
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+		chunks = append(chunks, Chunk{Data: data, ReceivedAt: time.Now()})
+	}
+	if err := scanner.Err(); err != nil {
+		return chunks, fmt.Errorf("stream: failed to read SSE body: %w", err)
+	}
+
+	return chunks, nil
+}
+
+// Consume reads an SSE body starting at start (the moment the request was
+// sent), reconstructing the full response text and its Stats.
+func Consume(r io.Reader, start time.Time) (string, Stats, error) {
+	chunks, err := ReadEvents(r)
+	if err != nil {
+		return "", Stats{}, err
+	}
+
+	var response strings.Builder
+	for _, c := range chunks {
+		response.WriteString(c.Data)
+	}
+
+	return response.String(), computeStats(chunks, start), nil
+}
+
+// Get performs req, which the caller should have set
+// "Accept: text/event-stream" on, and consumes its body as an SSE stream.
+// A nil client defaults to http.DefaultClient.
+func Get(ctx context.Context, client *http.Client, req *http.Request) (string, Stats, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", Stats{}, fmt.Errorf("stream: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", Stats{}, fmt.Errorf("stream: request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return Consume(resp.Body, start)
+}
+
+// computeStats derives Stats from a stream's chunks and the moment the
+// request was sent. tokens_per_second treats each chunk as one token, the
+// same coarse proxy metricsCalculatorJS's tokens_per_second uses: neither this
+// package nor the generated Code node has access to the provider's actual
+// tokenizer.
+func computeStats(chunks []Chunk, start time.Time) Stats {
+	stats := Stats{ChunkCount: len(chunks)}
+	if len(chunks) == 0 {
+		return stats
+	}
+
+	for _, c := range chunks {
+		stats.TotalBytes += len(c.Data)
+	}
+
+	stats.TTFTMs = millisBetween(start, chunks[0].ReceivedAt)
+
+	gaps := make([]float64, 0, len(chunks)-1)
+	for i := 1; i < len(chunks); i++ {
+		gaps = append(gaps, millisBetween(chunks[i-1].ReceivedAt, chunks[i].ReceivedAt))
+	}
+	stats.P50InterTokenMs = percentile(gaps, 0.50)
+	stats.P95InterTokenMs = percentile(gaps, 0.95)
+
+	if totalSeconds := chunks[len(chunks)-1].ReceivedAt.Sub(start).Seconds(); totalSeconds > 0 {
+		stats.TokensPerSecond = float64(len(chunks)) / totalSeconds
+	}
+
+	return stats
+}
+
+func millisBetween(a, b time.Time) float64 {
+	return float64(b.Sub(a).Microseconds()) / 1000
+}
+
+// percentile returns the p-th percentile (0..1) of values by nearest-rank,
+// matching harness.Percentiles. An empty values returns 0.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
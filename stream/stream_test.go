@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadEventsCollectsDataLinesAndStopsAtDone(t *testing.T) {
+	body := "data: Hello\n\ndata:  world\n\ndata: [DONE]\n\ndata: ignored after done\n"
+
+	chunks, err := ReadEvents(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("ReadEvents returned %d chunks, want 2", len(chunks))
+	}
+	if chunks[0].Data != "Hello" || chunks[1].Data != "world" {
+		t.Errorf("ReadEvents chunks = %q, %q, want %q, %q", chunks[0].Data, chunks[1].Data, "Hello", "world")
+	}
+}
+
+func TestConsumeReconstructsFullResponseAndStats(t *testing.T) {
+	body := "data: The\n\ndata: quick\n\ndata: fox\n\ndata: [DONE]\n"
+	start := time.Now()
+
+	response, stats, err := Consume(strings.NewReader(body), start)
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if response != "Thequickfox" {
+		t.Errorf("Consume response = %q, want %q", response, "Thequickfox")
+	}
+	if stats.ChunkCount != 3 {
+		t.Errorf("Consume stats.ChunkCount = %d, want 3", stats.ChunkCount)
+	}
+	if stats.TotalBytes != len("The")+len("quick")+len("fox") {
+		t.Errorf("Consume stats.TotalBytes = %d, want %d", stats.TotalBytes, len("The")+len("quick")+len("fox"))
+	}
+	if stats.TTFTMs < 0 {
+		t.Errorf("Consume stats.TTFTMs = %v, want >= 0", stats.TTFTMs)
+	}
+}
+
+func TestConsumeEmptyStreamReturnsZeroStats(t *testing.T) {
+	response, stats, err := Consume(strings.NewReader(""), time.Now())
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if response != "" {
+		t.Errorf("Consume response = %q, want empty", response)
+	}
+	if stats.ChunkCount != 0 || stats.TTFTMs != 0 || stats.TokensPerSecond != 0 {
+		t.Errorf("Consume stats = %+v, want zero value", stats)
+	}
+}
+
+func TestPercentileNearestRank(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 50}
+	if got := percentile(values, 0.50); got != 30 {
+		t.Errorf("percentile(values, 0.50) = %v, want 30", got)
+	}
+	if got := percentile(nil, 0.95); got != 0 {
+		t.Errorf("percentile(nil, 0.95) = %v, want 0", got)
+	}
+}
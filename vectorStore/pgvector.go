@@ -0,0 +1,101 @@
+package vectorStore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// PgVectorSink is a VectorSink backed by Postgres with the pgvector extension,
+// for deployments that already run datasnack's results against a shared
+// Postgres instance rather than per-developer SQLite files.
+type PgVectorSink struct {
+	conn  *pgx.Conn
+	table string
+	dim   int
+}
+
+// NewPgVectorSink connects to Postgres at connString and ensures a table
+// named table exists sized for vectors of the given dimension.
+func NewPgVectorSink(ctx context.Context, connString, table string, dim int) (*PgVectorSink, error) {
+	conn, err := pgx.Connect(ctx, connString)
+	if err != nil {
+		return nil, fmt.Errorf("vectorStore: connecting to postgres: %w", err)
+	}
+
+	schema := fmt.Sprintf(`
+		CREATE EXTENSION IF NOT EXISTS vector;
+		CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			embedding vector(%d),
+			metadata JSONB NOT NULL
+		);
+	`, table, dim)
+	if _, err := conn.Exec(ctx, schema); err != nil {
+		conn.Close(ctx)
+		return nil, fmt.Errorf("vectorStore: creating schema: %w", err)
+	}
+
+	return &PgVectorSink{conn: conn, table: table, dim: dim}, nil
+}
+
+func (s *PgVectorSink) Upsert(id string, vec []float32, metadata map[string]string) error {
+	if len(vec) != s.dim {
+		return fmt.Errorf("vectorStore: vector has dimension %d, sink expects %d", len(vec), s.dim)
+	}
+
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("vectorStore: marshaling metadata: %w", err)
+	}
+
+	ctx := context.Background()
+	_, err = s.conn.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s (id, embedding, metadata) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET embedding = excluded.embedding, metadata = excluded.metadata
+	`, s.table), id, pgvector.NewVector(vec), metaJSON)
+	if err != nil {
+		return fmt.Errorf("vectorStore: upserting %q: %w", id, err)
+	}
+	return nil
+}
+
+func (s *PgVectorSink) Query(vec []float32, topK int) ([]Match, error) {
+	ctx := context.Background()
+	rows, err := s.conn.Query(ctx, fmt.Sprintf(`
+		SELECT id, metadata, 1 - (embedding <=> $1) AS score
+		FROM %s
+		ORDER BY embedding <=> $1
+		LIMIT $2
+	`, s.table), pgvector.NewVector(vec), topK)
+	if err != nil {
+		return nil, fmt.Errorf("vectorStore: querying nearest neighbors: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var id string
+		var metaJSON []byte
+		var score float32
+		if err := rows.Scan(&id, &metaJSON, &score); err != nil {
+			return nil, fmt.Errorf("vectorStore: scanning row: %w", err)
+		}
+
+		var metadata map[string]string
+		if err := json.Unmarshal(metaJSON, &metadata); err != nil {
+			return nil, fmt.Errorf("vectorStore: unmarshaling metadata for %q: %w", id, err)
+		}
+
+		matches = append(matches, Match{ID: id, Score: score, Metadata: metadata})
+	}
+	return matches, rows.Err()
+}
+
+func (s *PgVectorSink) Close() error {
+	s.conn.Close(context.Background())
+	return nil
+}
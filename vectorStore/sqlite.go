@@ -0,0 +1,122 @@
+package vectorStore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteSink is a VectorSink backed by a local SQLite database using the
+// sqlite-vec extension, giving datasnack a persistent index that survives
+// between CLI invocations without standing up a separate server.
+type SQLiteSink struct {
+	db  *sql.DB
+	dim int
+}
+
+// NewSQLiteSink opens (creating if necessary) a sqlite-vec virtual table at
+// path sized for vectors of the given dimension.
+func NewSQLiteSink(path string, dim int) (*SQLiteSink, error) {
+	sqlite_vec.Auto()
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("vectorStore: opening sqlite db: %w", err)
+	}
+
+	schema := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS vector_metadata (
+			id TEXT PRIMARY KEY,
+			metadata TEXT NOT NULL
+		);
+		CREATE VIRTUAL TABLE IF NOT EXISTS vector_index USING vec0(
+			id TEXT PRIMARY KEY,
+			embedding FLOAT[%d]
+		);
+	`, dim)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("vectorStore: creating schema: %w", err)
+	}
+
+	return &SQLiteSink{db: db, dim: dim}, nil
+}
+
+func (s *SQLiteSink) Upsert(id string, vec []float32, metadata map[string]string) error {
+	if len(vec) != s.dim {
+		return fmt.Errorf("vectorStore: vector has dimension %d, sink expects %d", len(vec), s.dim)
+	}
+
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("vectorStore: marshaling metadata: %w", err)
+	}
+
+	embeddingJSON, err := json.Marshal(vec)
+	if err != nil {
+		return fmt.Errorf("vectorStore: marshaling embedding: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("vectorStore: starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO vector_metadata (id, metadata) VALUES (?, ?)
+		ON CONFLICT(id) DO UPDATE SET metadata = excluded.metadata`, id, string(metaJSON)); err != nil {
+		return fmt.Errorf("vectorStore: upserting metadata: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM vector_index WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("vectorStore: clearing stale embedding: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO vector_index (id, embedding) VALUES (?, ?)`, id, string(embeddingJSON)); err != nil {
+		return fmt.Errorf("vectorStore: inserting embedding: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteSink) Query(vec []float32, topK int) ([]Match, error) {
+	embeddingJSON, err := json.Marshal(vec)
+	if err != nil {
+		return nil, fmt.Errorf("vectorStore: marshaling query vector: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT vector_index.id, vector_index.distance, vector_metadata.metadata
+		FROM vector_index
+		JOIN vector_metadata ON vector_metadata.id = vector_index.id
+		WHERE vector_index.embedding MATCH ? AND k = ?
+		ORDER BY vector_index.distance
+	`, string(embeddingJSON), topK)
+	if err != nil {
+		return nil, fmt.Errorf("vectorStore: querying nearest neighbors: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var id, metaJSON string
+		var distance float32
+		if err := rows.Scan(&id, &distance, &metaJSON); err != nil {
+			return nil, fmt.Errorf("vectorStore: scanning row: %w", err)
+		}
+
+		var metadata map[string]string
+		if err := json.Unmarshal([]byte(metaJSON), &metadata); err != nil {
+			return nil, fmt.Errorf("vectorStore: unmarshaling metadata for %q: %w", id, err)
+		}
+
+		matches = append(matches, Match{ID: id, Score: -distance, Metadata: metadata})
+	}
+	return matches, rows.Err()
+}
+
+func (s *SQLiteSink) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,93 @@
+// Package vectorStore persists embeddings produced by awsBedrock.EmbedBatch
+// so datasnack can build a semantic index of a scanned codebase once and
+// reuse it across CLI invocations instead of re-embedding on every run.
+package vectorStore
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// VectorSink stores embeddings keyed by id alongside arbitrary metadata, and
+// supports similarity search over what it has stored. Implementations back
+// onto different storage: in-memory for one-off runs, SQLite (sqlite-vec) or
+// pgvector for an index that survives between invocations.
+type VectorSink interface {
+	Upsert(id string, vec []float32, metadata map[string]string) error
+	Query(vec []float32, topK int) ([]Match, error)
+	Close() error
+}
+
+// Match is a single result from VectorSink.Query, ordered by descending score.
+type Match struct {
+	ID       string
+	Score    float32
+	Metadata map[string]string
+}
+
+// InMemorySink is a VectorSink backed by a plain map, suitable for short-lived
+// scans where persistence across invocations isn't needed.
+type InMemorySink struct {
+	mu      sync.RWMutex
+	vectors map[string][]float32
+	meta    map[string]map[string]string
+}
+
+// NewInMemorySink creates an empty InMemorySink.
+func NewInMemorySink() *InMemorySink {
+	return &InMemorySink{
+		vectors: make(map[string][]float32),
+		meta:    make(map[string]map[string]string),
+	}
+}
+
+func (s *InMemorySink) Upsert(id string, vec []float32, metadata map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vectors[id] = vec
+	s.meta[id] = metadata
+	return nil
+}
+
+func (s *InMemorySink) Query(vec []float32, topK int) ([]Match, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]Match, 0, len(s.vectors))
+	for id, candidate := range s.vectors {
+		score, err := cosineSimilarity(vec, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("vectorStore: comparing against %q: %w", id, err)
+		}
+		matches = append(matches, Match{ID: id, Score: score, Metadata: s.meta[id]})
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if topK > 0 && topK < len(matches) {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+func (s *InMemorySink) Close() error {
+	return nil
+}
+
+func cosineSimilarity(a, b []float32) (float32, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("dimension mismatch: %d vs %d", len(a), len(b))
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, nil
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB))), nil
+}
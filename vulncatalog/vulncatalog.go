@@ -0,0 +1,84 @@
+// Package vulncatalog normalizes the free-text vulnerability "Type" strings
+// an AI evaluator writes (e.g. the cloneAttack.Vulnerability and
+// cmd.Vulnerability the stress-test and suggestions subsystems produce) to
+// canonical codes from a standard AI-security taxonomy - OWASP's LLM Top-10
+// and MITRE ATLAS - plus a CWE-style reference for each. The default table
+// is taxonomy.yaml, embedded at build time; Load reads a replacement or
+// supplemental table, such as a deployment's config/vuln_taxonomy.yaml, in
+// the same shape.
+package vulncatalog
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed taxonomy.yaml
+var defaultTaxonomyYAML []byte
+
+// Mapping is one producer-specific vuln string's canonical identity: a
+// substring to match against a Vulnerability.Type or Description
+// (case-insensitively), the taxonomy code it maps to, and a CWE reference.
+type Mapping struct {
+	Match          string `yaml:"match" json:"match"`
+	NormalizedType string `yaml:"normalized_type" json:"normalized_type"`
+	CWE            string `yaml:"cwe,omitempty" json:"cwe,omitempty"`
+	Description    string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+type taxonomyFile struct {
+	Mappings []Mapping `yaml:"mappings"`
+}
+
+// VulnCatalog is a loaded set of Mappings, queried via Normalize.
+type VulnCatalog struct {
+	mappings []Mapping
+}
+
+// Default returns the VulnCatalog embedded from taxonomy.yaml.
+func Default() (*VulnCatalog, error) {
+	return parse(defaultTaxonomyYAML)
+}
+
+// Load reads a VulnCatalog from a YAML file shaped like taxonomy.yaml, such
+// as a deployment's config/vuln_taxonomy.yaml.
+func Load(path string) (*VulnCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("vulncatalog: failed to read %s: %w", path, err)
+	}
+	return parse(data)
+}
+
+func parse(data []byte) (*VulnCatalog, error) {
+	var f taxonomyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("vulncatalog: failed to parse taxonomy: %w", err)
+	}
+	return &VulnCatalog{mappings: f.Mappings}, nil
+}
+
+// Normalize matches vulnType against c's Mappings, case-insensitively and in
+// table order, and returns the first match's taxonomy code and CWE
+// reference. It returns ("", "") if nothing matches, so callers can fall
+// back to the original free-text type.
+func (c *VulnCatalog) Normalize(vulnType string) (normalizedType, cwe string) {
+	haystack := strings.ToLower(vulnType)
+	for _, m := range c.mappings {
+		if strings.Contains(haystack, strings.ToLower(m.Match)) {
+			return m.NormalizedType, m.CWE
+		}
+	}
+	return "", ""
+}
+
+// Mappings returns c's Mappings, for callers that build their own reference
+// listing (e.g. a report's CWE appendix) rather than looking up one type at
+// a time.
+func (c *VulnCatalog) Mappings() []Mapping {
+	return c.mappings
+}
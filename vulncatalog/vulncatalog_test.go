@@ -0,0 +1,61 @@
+package vulncatalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultNormalizesKnownTypes(t *testing.T) {
+	catalog, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	normalized, cwe := catalog.Normalize("Prompt Injection")
+	if normalized != "LLM01:PromptInjection" || cwe == "" {
+		t.Errorf("Normalize(%q) = (%q, %q), want LLM01:PromptInjection with a CWE", "Prompt Injection", normalized, cwe)
+	}
+}
+
+func TestDefaultNormalizeUnknownTypeReturnsEmpty(t *testing.T) {
+	catalog, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	normalized, cwe := catalog.Normalize("totally unrecognized vuln class")
+	if normalized != "" || cwe != "" {
+		t.Errorf("Normalize(unknown) = (%q, %q), want (\"\", \"\")", normalized, cwe)
+	}
+}
+
+func TestLoadParsesCustomTable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vuln_taxonomy.yaml")
+	contents := `
+mappings:
+  - match: "custom leak"
+    normalized_type: "CUSTOM01:Leak"
+    cwe: "CWE-1"
+`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	catalog, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	normalized, cwe := catalog.Normalize("we found a Custom Leak in the output")
+	if normalized != "CUSTOM01:Leak" || cwe != "CWE-1" {
+		t.Errorf("Normalize = (%q, %q), want (CUSTOM01:Leak, CWE-1)", normalized, cwe)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("Load err = nil, want an error naming the missing file")
+	}
+}
@@ -0,0 +1,127 @@
+// Package workflow parses n8n workflow export JSON into a queryable graph of
+// nodes and connections, so callers can reason about an entire workflow —
+// its triggers, AI/LLM nodes, credentials, HTTP calls, and code nodes —
+// instead of scanning the raw node list for a single node type.
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Node is one n8n workflow node. Parameters and Credentials are left as
+// generic maps because their shape is entirely node-type-specific.
+type Node struct {
+	ID          string
+	Name        string
+	Type        string
+	Parameters  map[string]interface{}
+	Credentials map[string]interface{}
+}
+
+// Connection is a directed edge between two nodes, identified by node name
+// (n8n's "connections" object keys and references nodes by name, not ID).
+type Connection struct {
+	SourceNode string
+	TargetNode string
+}
+
+// Graph is the full parsed node+connection structure of an n8n workflow.
+type Graph struct {
+	Nodes       []Node
+	Connections []Connection
+}
+
+type rawWorkflow struct {
+	Nodes       []rawNode                           `json:"nodes"`
+	Connections map[string]map[string][][]rawTarget `json:"connections"`
+}
+
+type rawNode struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Credentials map[string]interface{} `json:"credentials"`
+}
+
+type rawTarget struct {
+	Node string `json:"node"`
+}
+
+// Parse builds a Graph from raw n8n workflow export JSON.
+func Parse(data []byte) (*Graph, error) {
+	var raw rawWorkflow
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("workflow: failed to parse workflow JSON: %w", err)
+	}
+
+	g := &Graph{Nodes: make([]Node, 0, len(raw.Nodes))}
+	for _, n := range raw.Nodes {
+		g.Nodes = append(g.Nodes, Node{
+			ID:          n.ID,
+			Name:        n.Name,
+			Type:        n.Type,
+			Parameters:  n.Parameters,
+			Credentials: n.Credentials,
+		})
+	}
+
+	for sourceName, outputs := range raw.Connections {
+		for _, branches := range outputs {
+			for _, targets := range branches {
+				for _, t := range targets {
+					g.Connections = append(g.Connections, Connection{SourceNode: sourceName, TargetNode: t.Node})
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// ParseFile reads and parses the n8n workflow export at path.
+func ParseFile(path string) (*Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("workflow: failed to read workflow file: %w", err)
+	}
+	return Parse(data)
+}
+
+// NodeByName returns the node with the given name, if any.
+func (g *Graph) NodeByName(name string) (Node, bool) {
+	for _, n := range g.Nodes {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// Downstream returns every node reachable from startName by following
+// connections forward, not including startName itself.
+func (g *Graph) Downstream(startName string) []Node {
+	visited := map[string]bool{startName: true}
+	queue := []string{startName}
+	var result []Node
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, conn := range g.Connections {
+			if conn.SourceNode != current || visited[conn.TargetNode] {
+				continue
+			}
+			visited[conn.TargetNode] = true
+			queue = append(queue, conn.TargetNode)
+			if n, ok := g.NodeByName(conn.TargetNode); ok {
+				result = append(result, n)
+			}
+		}
+	}
+
+	return result
+}